@@ -0,0 +1,139 @@
+package peakdetect
+
+import (
+	"fmt"
+	"math"
+)
+
+// AdaptiveLagDetector is a PeakDetector variant whose effective lag adapts to the volatility of the data. The lag
+// expands during quiet periods, for robustness, and contracts during volatile periods, so the threshold adapts quickly.
+// A static lag forces a compromise for data that alternates between flat stretches and active regions.
+//
+// Volatility is measured as the ratio of the standard deviation of the most recent minLag values to the standard
+// deviation of the most recent maxLag values. At a ratio of 0.5 or less, the lag is maxLag. At a ratio of 1.5 or more,
+// the lag is minLag. Between those, the lag is linearly interpolated, so stationary data uses a lag halfway between the
+// bounds.
+//
+// Each update is O(maxLag), as the statistics for the current lag are computed from the retained values.
+type AdaptiveLagDetector interface {
+	// Initialize initializes the AdaptiveLagDetector with its configuration and initialValues. The length of the
+	// initialValues is the maximum lag. minLag is the minimum lag. It must be at least two and less than the maximum
+	// lag. See PeakDetector.Initialize for a description of influence and threshold. The AdaptiveLagDetector will never
+	// return any signals for the initialValues.
+	Initialize(minLag uint, influence, threshold float64, initialValues []float64) error
+	// Next processes the next value and determines its signal.
+	Next(value float64) Signal
+	// NextBatch processes the next values and determines their signals. Their signals will be returned in a slice equal
+	// to the length of the input.
+	NextBatch(values []float64) []Signal
+	// Lag returns the effective lag that will be used for the next value.
+	Lag() uint
+}
+
+type adaptiveLagDetector struct {
+	index     uint
+	influence float64
+	lag       uint
+	maxLag    uint
+	minLag    uint
+	prevValue float64
+	threshold float64
+	values    []float64
+}
+
+// NewAdaptiveLagDetector creates a new AdaptiveLagDetector. It must be initialized before use.
+func NewAdaptiveLagDetector() AdaptiveLagDetector {
+	return &adaptiveLagDetector{}
+}
+
+func (a *adaptiveLagDetector) Initialize(minLag uint, influence, threshold float64, initialValues []float64) error {
+	maxLag := uint(len(initialValues))
+	if maxLag == 0 {
+		return fmt.Errorf("the length of the initial values is zero, the length is used as the maximum lag: %w", ErrInvalidInitialValues)
+	}
+	if minLag < 2 || minLag >= maxLag {
+		return fmt.Errorf("the minimum lag %d must be at least two and less than the maximum lag %d: %w", minLag, maxLag, ErrInvalidConfig)
+	}
+	err := Config{
+		Influence: influence,
+		Lag:       maxLag,
+		Threshold: threshold,
+	}.Validate()
+	if err != nil {
+		return err
+	}
+	*a = adaptiveLagDetector{
+		influence: influence,
+		maxLag:    maxLag,
+		minLag:    minLag,
+		prevValue: initialValues[maxLag-1],
+		threshold: threshold,
+		values:    append([]float64(nil), initialValues...),
+	}
+	a.adapt()
+	return nil
+}
+
+func (a *adaptiveLagDetector) Next(value float64) (signal Signal) {
+	mean, stdDev := a.recent(a.lag)
+	if math.Abs(value-mean) > a.threshold*stdDev {
+		if value > mean {
+			signal = SignalPositive
+		} else {
+			signal = SignalNegative
+		}
+		value = a.influence*value + (1-a.influence)*a.prevValue
+	} else {
+		signal = SignalNeutral
+	}
+
+	a.values[a.index] = value
+	a.index++
+	if a.index == a.maxLag {
+		a.index = 0
+	}
+	a.prevValue = value
+	a.adapt()
+
+	return signal
+}
+
+func (a *adaptiveLagDetector) NextBatch(values []float64) []Signal {
+	signals := make([]Signal, len(values))
+	for i, v := range values {
+		signals[i] = a.Next(v)
+	}
+	return signals
+}
+
+func (a *adaptiveLagDetector) Lag() uint {
+	return a.lag
+}
+
+// adapt sets the effective lag based on the volatility of the retained values.
+func (a *adaptiveLagDetector) adapt() {
+	_, short := a.recent(a.minLag)
+	_, long := a.recent(a.maxLag)
+
+	fraction := 0.0
+	if long != 0 {
+		fraction = math.Min(math.Max(short/long-0.5, 0), 1)
+	}
+	a.lag = a.maxLag - uint(math.Round(fraction*float64(a.maxLag-a.minLag)))
+}
+
+// recent computes the mean and population standard deviation of the n most recent retained values.
+func (a *adaptiveLagDetector) recent(n uint) (mean, stdDev float64) {
+	start := a.index + a.maxLag - n
+	for i := uint(0); i < n; i++ {
+		mean += a.values[(start+i)%a.maxLag]
+	}
+	mean /= float64(n)
+
+	var sumOfSquares float64
+	for i := uint(0); i < n; i++ {
+		deviation := a.values[(start+i)%a.maxLag] - mean
+		sumOfSquares += deviation * deviation
+	}
+	return mean, math.Sqrt(sumOfSquares / float64(n))
+}
@@ -0,0 +1,84 @@
+package peakdetect_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/MicahParks/peakdetect"
+)
+
+func TestAdaptiveLagDetector_Initialize(t *testing.T) {
+	detector := peakdetect.NewAdaptiveLagDetector()
+	err := detector.Initialize(2, 0, 1, nil)
+	if !errors.Is(err, peakdetect.ErrInvalidInitialValues) {
+		t.Fatalf("Invalid initilization did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidInitialValues, err)
+	}
+	err = detector.Initialize(1, 0, 1, []float64{1, 2, 3})
+	if !errors.Is(err, peakdetect.ErrInvalidConfig) {
+		t.Fatalf("Invalid minimum lag did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidConfig, err)
+	}
+	err = detector.Initialize(2, 1.5, 1, []float64{1, 2, 3})
+	if !errors.Is(err, peakdetect.ErrInvalidConfig) {
+		t.Fatalf("Invalid influence did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidConfig, err)
+	}
+	err = detector.Initialize(2, 0, math.NaN(), []float64{1, 2, 3})
+	if !errors.Is(err, peakdetect.ErrInvalidConfig) {
+		t.Fatalf("Invalid threshold did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidConfig, err)
+	}
+}
+
+func TestAdaptiveLagDetector_Lag(t *testing.T) {
+	const (
+		minLag = 5
+		maxLag = 50
+	)
+
+	// Alternate between stationary noise and a quiet stretch followed by a volatile stretch.
+	noise := []float64{0.1, -0.1, 0.05, -0.05}
+	var data []float64
+	for i := 0; i < maxLag; i++ {
+		data = append(data, 1+noise[i%len(noise)])
+	}
+
+	detector := peakdetect.NewAdaptiveLagDetector()
+	err := detector.Initialize(minLag, 1, 3, data)
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+	if lag := detector.Lag(); lag <= minLag || lag >= maxLag {
+		t.Fatalf("Stationary data should use a lag between the bounds.\n  Actual: %d", lag)
+	}
+
+	for i := 0; i < 10; i++ {
+		detector.Next(1)
+	}
+	if lag := detector.Lag(); lag != maxLag {
+		t.Fatalf("Quiet data should expand the lag.\n  Expected: %d\n  Actual: %d", maxLag, detector.Lag())
+	}
+
+	for i := 0; i < 5; i++ {
+		detector.Next(1 + 10*noise[i%len(noise)])
+	}
+	if lag := detector.Lag(); lag != minLag {
+		t.Fatalf("Volatile data should contract the lag.\n  Expected: %d\n  Actual: %d", minLag, detector.Lag())
+	}
+}
+
+func TestAdaptiveLagDetector_NextBatch(t *testing.T) {
+	detector := peakdetect.NewAdaptiveLagDetector()
+	err := detector.Initialize(10, exampleInfluence, exampleThreshold, exampleInputs[0:exampleLag])
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+
+	signals := detector.NextBatch(exampleInputs[exampleLag:])
+	for i, signal := range signals {
+		// The first peaks of the example are detected regardless of the lag.
+		if index := i + exampleLag; index == 47 || index == 49 {
+			if signal != peakdetect.SignalPositive {
+				t.Fatalf("Peak was not detected at index %d.\n  Actual: %d", index, signal)
+			}
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package peakdetect
+
+import (
+	"compress/gzip"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// archiveVersion is the version of the archive written by MultiPeakDetector.ExportAll. It is incremented whenever the
+// layout of the archive changes.
+const archiveVersion = 1
+
+// archiveHeader is the first value of an archive written by MultiPeakDetector.ExportAll. It is followed by the state of
+// each channel, as encoded by PeakDetector.MarshalBinary.
+type archiveHeader struct {
+	Version  uint8
+	Channels int
+}
+
+// ExportAll writes the state of every channel, including its configuration, labels, and lag window, to w as a single
+// gzip compressed archive, so a fleet of warmed up detectors can be moved to another process without a new warm up
+// period. The channels are written one at a time, so the whole archive is never held in memory. See
+// PeakDetector.MarshalBinary for what is included in the state of each channel.
+//
+// If progress is not nil, it is called after each channel is written with the number of channels written so far and
+// the total.
+func (m *MultiPeakDetector) ExportAll(w io.Writer, progress func(done, total int)) error {
+	zw := gzip.NewWriter(w)
+	enc := gob.NewEncoder(zw)
+	err := enc.Encode(archiveHeader{
+		Version:  archiveVersion,
+		Channels: len(m.detectors),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write archive header: %w", err)
+	}
+	for i, detector := range m.detectors {
+		state, err := detector.MarshalBinary()
+		if err != nil {
+			return fmt.Errorf("failed to encode channel %d: %w", i, err)
+		}
+		err = enc.Encode(state)
+		if err != nil {
+			return fmt.Errorf("failed to write channel %d: %w", i, err)
+		}
+		if progress != nil {
+			progress(i+1, len(m.detectors))
+		}
+	}
+	err = zw.Close()
+	if err != nil {
+		return fmt.Errorf("failed to finish archive: %w", err)
+	}
+	return nil
+}
+
+// ImportAll replaces every channel with the channels read from an archive written by ExportAll. The channels are only
+// replaced once the whole archive has been read, so the MultiPeakDetector is unchanged if an error is returned. The
+// zero value of a MultiPeakDetector can import an archive.
+//
+// If progress is not nil, it is called after each channel is read with the number of channels read so far and the
+// total.
+func (m *MultiPeakDetector) ImportAll(r io.Reader, progress func(done, total int)) error {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("failed to read archive: %v: %w", err, ErrInvalidState)
+	}
+	dec := gob.NewDecoder(zr)
+	var header archiveHeader
+	err = dec.Decode(&header)
+	if err != nil {
+		return fmt.Errorf("failed to read archive header: %v: %w", err, ErrInvalidState)
+	}
+	if header.Version != archiveVersion {
+		return fmt.Errorf("unsupported archive version %d: %w", header.Version, ErrInvalidState)
+	}
+	if header.Channels < 0 {
+		return fmt.Errorf("the archive has %d channels: %w", header.Channels, ErrInvalidState)
+	}
+
+	// The number of channels is not trusted for preallocation, as the archive has not been read yet.
+	var detectors []PeakDetector
+	for i := 0; i < header.Channels; i++ {
+		var state []byte
+		err = dec.Decode(&state)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				err = io.ErrUnexpectedEOF
+			}
+			return fmt.Errorf("failed to read channel %d: %v: %w", i, err, ErrInvalidState)
+		}
+		detector := NewPeakDetector()
+		err = detector.UnmarshalBinary(state)
+		if err != nil {
+			return fmt.Errorf("failed to decode channel %d: %w", i, err)
+		}
+		detectors = append(detectors, detector)
+		if progress != nil {
+			progress(i+1, header.Channels)
+		}
+	}
+	m.detectors = detectors
+	return nil
+}
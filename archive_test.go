@@ -0,0 +1,79 @@
+package peakdetect_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/MicahParks/peakdetect"
+)
+
+func TestMultiPeakDetector_ExportAll(t *testing.T) {
+	detector := newExampleMultiPeakDetector(t)
+	detector.Channel(0).SetLabels(map[string]string{"series": "a"})
+	_, err := detector.NextBatchColumns([][]float64{exampleInputs[exampleLag:40], exampleInputs[exampleLag:45]})
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to process values.", err)
+	}
+
+	var buf bytes.Buffer
+	var exported []int
+	err = detector.ExportAll(&buf, func(done, total int) {
+		if total != detector.Channels() {
+			t.Fatalf("Incorrect total.\n  Expected: %d\n  Actual: %d", detector.Channels(), total)
+		}
+		exported = append(exported, done)
+	})
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to export detectors.", err)
+	}
+	if !reflect.DeepEqual(exported, []int{1, 2}) {
+		t.Fatalf("Incorrect progress.\n  Expected: %v\n  Actual: %v", []int{1, 2}, exported)
+	}
+
+	var imported peakdetect.MultiPeakDetector
+	var progress int
+	err = imported.ImportAll(bytes.NewReader(buf.Bytes()), func(done, total int) {
+		progress = done
+	})
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to import detectors.", err)
+	}
+	if imported.Channels() != detector.Channels() || progress != detector.Channels() {
+		t.Fatalf("Incorrect number of channels.\n  Expected: %d\n  Actual: %d", detector.Channels(), imported.Channels())
+	}
+	if imported.Channel(0).Labels()["series"] != "a" {
+		t.Fatalf("Labels were not imported.\n  Actual: %v", imported.Channel(0).Labels())
+	}
+
+	for _, v := range exampleInputs[45:] {
+		expected, _ := detector.NextVector([]float64{v, v})
+		actual, err := imported.NextVector([]float64{v, v})
+		if err != nil {
+			t.Fatalf(logFmt, "Failed to process values.", err)
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Fatalf("Imported detectors did not match.\n  Expected: %v\n  Actual: %v", expected, actual)
+		}
+	}
+}
+
+func TestMultiPeakDetector_ImportAllInvalid(t *testing.T) {
+	detector := newExampleMultiPeakDetector(t)
+	var buf bytes.Buffer
+	err := detector.ExportAll(&buf, nil)
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to export detectors.", err)
+	}
+
+	for _, data := range [][]byte{[]byte("not an archive"), buf.Bytes()[:buf.Len()/2]} {
+		err = detector.ImportAll(bytes.NewReader(data), nil)
+		if !errors.Is(err, peakdetect.ErrInvalidState) {
+			t.Fatalf("Invalid archive did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidState, err)
+		}
+	}
+	if detector.Channels() != 2 {
+		t.Fatalf("A failed import modified the detector.\n  Expected: %d\n  Actual: %d", 2, detector.Channels())
+	}
+}
@@ -0,0 +1,186 @@
+// Command peakdetect runs the peak detection algorithm over a data file or stdin. It is meant for trying parameters on
+// your own data without writing any Go.
+//
+// Usage:
+//
+//	peakdetect [flags] [file]
+//
+// If file is omitted or is "-", the data is read from stdin. The first lag values are used for initialization and
+// always have a neutral signal.
+//
+// # Server
+//
+//	peakdetect serve [-addr :8080]
+//
+// The serve subcommand runs an HTTP API with JSON bodies, so services in other languages can use the algorithm. Each
+// detector has a name, and the index of a value counts every value the detector has processed, starting at zero.
+//
+//	GET    /detectors              lists the names of the detectors.
+//	PUT    /detectors/{name}       creates or replaces a detector from {"config": {...}, "initialValues": [...]}.
+//	GET    /detectors/{name}       describes a detector, including its config and moving statistics.
+//	DELETE /detectors/{name}       deletes a detector.
+//	POST   /detectors/{name}/values processes {"values": [...]} and responds with {"startIndex": 0, "signals": [...]}.
+//	POST   /detectors/{name}/stream processes newline delimited values, either numbers or {"value": 1.5}, and responds
+//	                               with a newline delimited {"index": 0, "signal": 1, "value": 1.5} for each value as
+//	                               soon as it is processed, until the request body ends.
+//
+// The config fields are those of peakdetect.Config, such as {"lag": 30, "threshold": 5, "influence": 0}. Errors are
+// responded with {"error": "..."}.
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/MicahParks/peakdetect"
+	"github.com/MicahParks/peakdetect/peakdetectio"
+	"github.com/MicahParks/peakdetect/peakdetectplot"
+)
+
+const (
+	outputCSV     = "csv"
+	outputHTML    = "html"
+	outputPeaks   = "peaks"
+	outputSignals = "signals"
+	outputSVG     = "svg"
+)
+
+var errUsage = errors.New("invalid usage")
+
+var formats = map[string]peakdetectio.Format{
+	"csv":   peakdetectio.FormatCSV,
+	"jsonl": peakdetectio.FormatJSONL,
+	"lines": peakdetectio.FormatLines,
+}
+
+func main() {
+	err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+	if err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			_, _ = fmt.Fprintf(os.Stderr, "peakdetect: %s\n", err)
+		}
+		os.Exit(2)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	if len(args) > 0 && args[0] == "serve" {
+		return serve(args[1:], stderr)
+	}
+
+	flags := flag.NewFlagSet("peakdetect", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		_, _ = fmt.Fprintf(flags.Output(), "Usage: peakdetect [flags] [file]\n       peakdetect serve [flags]\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	lag := flags.Uint("lag", 30, "the number of values in the moving window, used for initialization")
+	threshold := flags.Float64("threshold", 5, "the number of standard deviations from the moving mean for a signal")
+	influence := flags.Float64("influence", 0, "the influence of signals on the moving mean and standard deviation, from 0 to 1")
+	format := flags.String("format", "lines", "the input format: lines, csv, or jsonl")
+	valueField := flags.String("value", "", "the CSV column or JSON field with the values")
+	output := flags.String("output", outputSignals, "the output: signals, one per line; peaks, one CSV row per peak; csv, every value with its signal; or svg or html, a plot of the values with the threshold band")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+	if flags.NArg() > 1 {
+		flags.Usage()
+		return fmt.Errorf("at most one file may be given: %w", errUsage)
+	}
+
+	f, ok := formats[*format]
+	if !ok {
+		return fmt.Errorf("unknown format %q: %w", *format, errUsage)
+	}
+	switch *output {
+	case outputCSV, outputHTML, outputPeaks, outputSignals, outputSVG:
+	default:
+		return fmt.Errorf("unknown output %q: %w", *output, errUsage)
+	}
+
+	in := stdin
+	if name := flags.Arg(0); name != "" && name != "-" {
+		file, err := os.Open(name)
+		if err != nil {
+			return fmt.Errorf("failed to open the data file: %w", err)
+		}
+		defer file.Close()
+		in = file
+	}
+
+	data, err := peakdetectio.ReadValues(in, peakdetectio.Config{
+		Format:     f,
+		ValueField: *valueField,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to read the data: %w", err)
+	}
+	if uint(len(data)) < *lag {
+		return fmt.Errorf("%d values are too few for a lag of %d: %w", len(data), *lag, errUsage)
+	}
+
+	cfg := peakdetect.Config{
+		Influence: *influence,
+		Lag:       *lag,
+		Threshold: *threshold,
+	}
+	opts := peakdetectplot.Options{
+		Title: flags.Arg(0),
+	}
+	switch *output {
+	case outputHTML:
+		return peakdetectplot.HTML(stdout, data, cfg, opts)
+	case outputSVG:
+		return peakdetectplot.SVG(stdout, data, cfg, opts)
+	}
+
+	signals, peaks, err := peakdetect.Detect(data, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to detect peaks: %w", err)
+	}
+
+	return write(stdout, *output, data, signals, peaks)
+}
+
+func write(w io.Writer, output string, data []float64, signals []peakdetect.Signal, peaks []peakdetect.PeakEvent) error {
+	if output == outputSignals {
+		for _, signal := range signals {
+			_, err := fmt.Fprintln(w, signal)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	writer := csv.NewWriter(w)
+	if output == outputPeaks {
+		_ = writer.Write([]string{"start", "end", "apex", "apex_value", "signal"})
+		for _, peak := range peaks {
+			_ = writer.Write([]string{
+				strconv.FormatUint(peak.StartIndex, 10),
+				strconv.FormatUint(peak.EndIndex, 10),
+				strconv.FormatUint(peak.ApexIndex, 10),
+				strconv.FormatFloat(peak.ApexValue, 'g', -1, 64),
+				strconv.Itoa(int(peak.Signal)),
+			})
+		}
+	} else {
+		_ = writer.Write([]string{"index", "value", "signal"})
+		for i, v := range data {
+			_ = writer.Write([]string{
+				strconv.Itoa(i),
+				strconv.FormatFloat(v, 'g', -1, 64),
+				strconv.Itoa(int(signals[i])),
+			})
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+const logFmt = "%s\nError: %s"
+
+func TestRun(t *testing.T) {
+	input := "1\n1\n1.1\n1\n0.9\n1\n5\n6\n1\n"
+
+	tests := map[string]struct {
+		args     []string
+		expected string
+	}{
+		"Signals": {
+			args:     []string{"-lag", "5", "-threshold", "3"},
+			expected: "0\n0\n0\n0\n0\n0\n1\n1\n0\n",
+		},
+		"Peaks": {
+			args:     []string{"-lag", "5", "-threshold", "3", "-output", "peaks", "-"},
+			expected: "start,end,apex,apex_value,signal\n6,7,7,6,1\n",
+		},
+		"CSV": {
+			args:     []string{"-lag=5", "-threshold=3", "-output=csv"},
+			expected: "index,value,signal\n0,1,0\n1,1,0\n2,1.1,0\n3,1,0\n4,0.9,0\n5,1,0\n6,5,1\n7,6,1\n8,1,0\n",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			err := run(tc.args, strings.NewReader(input), &stdout, io.Discard)
+			if err != nil {
+				t.Fatalf(logFmt, "Failed to run.", err)
+			}
+			if stdout.String() != tc.expected {
+				t.Fatalf("Incorrect output.\n  Expected: %q\n  Actual: %q", tc.expected, stdout.String())
+			}
+		})
+	}
+}
+
+func TestRun_Plot(t *testing.T) {
+	for _, output := range []string{"svg", "html"} {
+		var stdout bytes.Buffer
+		err := run([]string{"-lag", "5", "-output", output}, strings.NewReader("1\n1\n1.1\n1\n0.9\n1\n5\n"), &stdout, io.Discard)
+		if err != nil {
+			t.Fatalf(logFmt, "Failed to run.", err)
+		}
+		if !strings.Contains(stdout.String(), "<svg") {
+			t.Fatalf("The %s output does not have a plot.\n  Actual: %s", output, stdout.String())
+		}
+	}
+}
+
+func TestRun_Usage(t *testing.T) {
+	for _, args := range [][]string{
+		{"-format", "xml"},
+		{"-output", "png"},
+		{"-lag", "100"},
+		{"a.csv", "b.csv"},
+	} {
+		err := run(args, strings.NewReader("1\n2\n3\n"), io.Discard, io.Discard)
+		if !errors.Is(err, errUsage) {
+			t.Fatalf("Invalid usage did not produce the correct error for %v.\n  Expected: %s\n  Actual: %v", args, errUsage, err)
+		}
+	}
+}
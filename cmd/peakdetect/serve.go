@@ -0,0 +1,312 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MicahParks/peakdetect"
+)
+
+// maxBodyBytes limits the size of a request body that is read all at once.
+const maxBodyBytes = 32 << 20
+
+// serve runs the HTTP API until the server fails.
+func serve(args []string, stderr io.Writer) error {
+	flags := flag.NewFlagSet("peakdetect serve", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		_, _ = fmt.Fprintf(flags.Output(), "Usage: peakdetect serve [flags]\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	addr := flags.String("addr", ":8080", "the address to listen on")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return fmt.Errorf("serve does not take arguments: %w", errUsage)
+	}
+
+	_, _ = fmt.Fprintf(stderr, "peakdetect: serving on %s\n", *addr)
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           newServer(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return server.ListenAndServe()
+}
+
+// server is the HTTP API for named detectors. See the command documentation for the routes.
+type server struct {
+	detectors map[string]*namedDetector
+	mux       sync.Mutex
+}
+
+// namedDetector is a detector along with the number of values it has processed, which is the index of the next value.
+type namedDetector struct {
+	detector peakdetect.PeakDetector
+	index    uint64
+	mux      sync.Mutex
+}
+
+// createRequest is the body of a request to create a detector.
+type createRequest struct {
+	Config        peakdetect.Config `json:"config"`
+	InitialValues []float64         `json:"initialValues"`
+}
+
+// detectorResponse describes a detector.
+type detectorResponse struct {
+	Config peakdetect.Config `json:"config"`
+	Count  uint64            `json:"count"`
+	Mean   float64           `json:"mean"`
+	Name   string            `json:"name"`
+	StdDev float64           `json:"stdDev"`
+}
+
+// valuesRequest is the body of a request to process values.
+type valuesRequest struct {
+	Values []float64 `json:"values"`
+}
+
+// valuesResponse is the body of the response to processing values.
+type valuesResponse struct {
+	// StartIndex is the index of the first value of the request.
+	StartIndex uint64              `json:"startIndex"`
+	Signals    []peakdetect.Signal `json:"signals"`
+}
+
+// streamValue is a line of the body of a request to stream values. A bare number is also accepted.
+type streamValue struct {
+	Value *float64 `json:"value"`
+}
+
+// event is a line of the body of the response to streaming values.
+type event struct {
+	Index  uint64            `json:"index"`
+	Signal peakdetect.Signal `json:"signal"`
+	Value  float64           `json:"value"`
+}
+
+// errorResponse is the body of a response for a failed request.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func newServer() *server {
+	return &server{
+		detectors: make(map[string]*namedDetector),
+	}
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(r.URL.Path, "/")
+	parts := strings.Split(path, "/")
+	if parts[0] != "detectors" || len(parts) > 3 {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	switch {
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		s.list(w)
+	case len(parts) == 2 && r.Method == http.MethodPut:
+		s.create(w, r, parts[1])
+	case len(parts) == 2 && r.Method == http.MethodGet:
+		s.describe(w, parts[1])
+	case len(parts) == 2 && r.Method == http.MethodDelete:
+		s.delete(w, parts[1])
+	case len(parts) == 3 && parts[2] == "values" && r.Method == http.MethodPost:
+		s.values(w, r, parts[1])
+	case len(parts) == 3 && parts[2] == "stream" && r.Method == http.MethodPost:
+		s.stream(w, r, parts[1])
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (s *server) list(w http.ResponseWriter) {
+	s.mux.Lock()
+	names := make([]string, 0, len(s.detectors))
+	for name := range s.detectors {
+		names = append(names, name)
+	}
+	s.mux.Unlock()
+	sort.Strings(names)
+	writeJSON(w, http.StatusOK, names)
+}
+
+func (s *server) create(w http.ResponseWriter, r *http.Request, name string) {
+	var req createRequest
+	err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes)).Decode(&req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("failed to decode the request: %s", err))
+		return
+	}
+	detector := peakdetect.NewPeakDetector()
+	err = detector.InitializeWithConfig(req.Config, req.InitialValues)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("failed to initialize the detector: %s", err))
+		return
+	}
+
+	s.mux.Lock()
+	_, exists := s.detectors[name]
+	s.detectors[name] = &namedDetector{
+		detector: detector,
+	}
+	s.mux.Unlock()
+
+	status := http.StatusCreated
+	if exists {
+		status = http.StatusOK
+	}
+	writeJSON(w, status, describe(name, detector, 0))
+}
+
+func (s *server) describe(w http.ResponseWriter, name string) {
+	d, ok := s.detector(w, name)
+	if !ok {
+		return
+	}
+	d.mux.Lock()
+	response := describe(name, d.detector, d.index)
+	d.mux.Unlock()
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *server) delete(w http.ResponseWriter, name string) {
+	s.mux.Lock()
+	_, ok := s.detectors[name]
+	delete(s.detectors, name)
+	s.mux.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("no detector is named %q", name))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *server) values(w http.ResponseWriter, r *http.Request, name string) {
+	d, ok := s.detector(w, name)
+	if !ok {
+		return
+	}
+	var req valuesRequest
+	err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes)).Decode(&req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("failed to decode the request: %s", err))
+		return
+	}
+
+	d.mux.Lock()
+	response := valuesResponse{
+		StartIndex: d.index,
+		Signals:    d.detector.NextBatch(req.Values),
+	}
+	d.index += uint64(len(req.Values))
+	d.mux.Unlock()
+	writeJSON(w, http.StatusOK, response)
+}
+
+// stream reads newline delimited values from the request body and writes a newline delimited event for each as soon as
+// it is processed, until the request body ends.
+func (s *server) stream(w http.ResponseWriter, r *http.Request, name string) {
+	d, ok := s.detector(w, name)
+	if !ok {
+		return
+	}
+	controller := http.NewResponseController(w)
+	// HTTP/1 responses are written while the request body is still being read.
+	_ = controller.EnableFullDuplex()
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	_ = controller.Flush()
+
+	encoder := json.NewEncoder(w)
+	scanner := bufio.NewScanner(r.Body)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		value, err := parseStreamValue(line)
+		if err != nil {
+			_ = encoder.Encode(errorResponse{Error: err.Error()})
+			return
+		}
+
+		d.mux.Lock()
+		e := event{
+			Index:  d.index,
+			Signal: d.detector.Next(value),
+			Value:  value,
+		}
+		d.index++
+		d.mux.Unlock()
+
+		err = encoder.Encode(e)
+		if err != nil {
+			return
+		}
+		_ = controller.Flush()
+	}
+	if err := scanner.Err(); err != nil {
+		_ = encoder.Encode(errorResponse{Error: fmt.Sprintf("failed to read the request: %s", err)})
+	}
+}
+
+// detector returns the detector with the name or writes a not found response.
+func (s *server) detector(w http.ResponseWriter, name string) (*namedDetector, bool) {
+	s.mux.Lock()
+	d, ok := s.detectors[name]
+	s.mux.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("no detector is named %q", name))
+	}
+	return d, ok
+}
+
+func describe(name string, detector peakdetect.PeakDetector, count uint64) detectorResponse {
+	mean, stdDev, _ := detector.Stats()
+	return detectorResponse{
+		Config: detector.Config(),
+		Count:  count,
+		Mean:   mean,
+		Name:   name,
+		StdDev: stdDev,
+	}
+}
+
+// parseStreamValue parses a line of a stream, which is either a JSON number or an object with a value.
+func parseStreamValue(line string) (float64, error) {
+	var value float64
+	if err := json.Unmarshal([]byte(line), &value); err == nil {
+		return value, nil
+	}
+	var v streamValue
+	err := json.Unmarshal([]byte(line), &v)
+	if err != nil || v.Value == nil {
+		return 0, fmt.Errorf("the line %q is not a number or an object with a value", line)
+	}
+	return *v.Value, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, errorResponse{Error: message})
+}
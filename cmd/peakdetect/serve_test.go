@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const serveInitial = `{"config": {"lag": 5, "threshold": 3}, "initialValues": [1, 1, 1.1, 1, 0.9]}`
+
+func TestServe_Values(t *testing.T) {
+	server := httptest.NewServer(newServer())
+	defer server.Close()
+
+	resp := request(t, http.MethodPut, server.URL+"/detectors/cpu", serveInitial)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Incorrect status for creation.\n  Expected: %d\n  Actual: %d", http.StatusCreated, resp.StatusCode)
+	}
+
+	var values valuesResponse
+	resp = request(t, http.MethodPost, server.URL+"/detectors/cpu/values", `{"values": [1, 5, 6]}`)
+	decode(t, resp, &values)
+	if values.StartIndex != 0 || fmt.Sprint(values.Signals) != "[0 1 1]" {
+		t.Fatalf("Incorrect signals.\n  Expected: %v\n  Actual: %+v", "[0 1 1]", values)
+	}
+	resp = request(t, http.MethodPost, server.URL+"/detectors/cpu/values", `{"values": [1]}`)
+	decode(t, resp, &values)
+	if values.StartIndex != 3 {
+		t.Fatalf("Incorrect start index.\n  Expected: %d\n  Actual: %d", 3, values.StartIndex)
+	}
+
+	var described detectorResponse
+	resp = request(t, http.MethodGet, server.URL+"/detectors/cpu", "")
+	decode(t, resp, &described)
+	if described.Name != "cpu" || described.Count != 4 || described.Config.Lag != 5 {
+		t.Fatalf("Incorrect description.\n  Actual: %+v", described)
+	}
+
+	var names []string
+	resp = request(t, http.MethodGet, server.URL+"/detectors", "")
+	decode(t, resp, &names)
+	if fmt.Sprint(names) != "[cpu]" {
+		t.Fatalf("Incorrect names.\n  Expected: %v\n  Actual: %v", "[cpu]", names)
+	}
+
+	resp = request(t, http.MethodDelete, server.URL+"/detectors/cpu", "")
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("Incorrect status for deletion.\n  Expected: %d\n  Actual: %d", http.StatusNoContent, resp.StatusCode)
+	}
+	resp = request(t, http.MethodPost, server.URL+"/detectors/cpu/values", `{"values": [1]}`)
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("Incorrect status for a deleted detector.\n  Expected: %d\n  Actual: %d", http.StatusNotFound, resp.StatusCode)
+	}
+}
+
+func TestServe_Stream(t *testing.T) {
+	server := httptest.NewServer(newServer())
+	defer server.Close()
+	request(t, http.MethodPut, server.URL+"/detectors/cpu", serveInitial)
+
+	// Each event is read before the next value is written, so the events must be streamed.
+	reader, writer := io.Pipe()
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/detectors/cpu/stream", reader)
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to create request.", err)
+	}
+	respCh := make(chan *http.Response)
+	go func() {
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Errorf(logFmt, "Failed to stream.", err)
+			close(respCh)
+			return
+		}
+		respCh <- resp
+	}()
+
+	_, _ = io.WriteString(writer, "1\n")
+	resp, ok := <-respCh
+	if !ok {
+		t.FailNow()
+	}
+	defer resp.Body.Close()
+	lines := bufio.NewReader(resp.Body)
+	for i, line := range []string{"", `{"value": 5}` + "\n", "6\n"} {
+		if line != "" {
+			_, _ = io.WriteString(writer, line)
+		}
+		text, err := lines.ReadString('\n')
+		if err != nil {
+			t.Fatalf(logFmt, "Failed to read event.", err)
+		}
+		var e event
+		err = json.Unmarshal([]byte(text), &e)
+		if err != nil {
+			t.Fatalf(logFmt, "Failed to decode event.", err)
+		}
+		expected := []event{{Index: 0, Signal: 0, Value: 1}, {Index: 1, Signal: 1, Value: 5}, {Index: 2, Signal: 1, Value: 6}}[i]
+		if e != expected {
+			t.Fatalf("Incorrect event.\n  Expected: %+v\n  Actual: %+v", expected, e)
+		}
+	}
+
+	_, _ = io.WriteString(writer, "oops\n")
+	text, _ := lines.ReadString('\n')
+	if !strings.Contains(text, "error") {
+		t.Fatalf("An invalid line did not produce an error.\n  Actual: %s", text)
+	}
+	_ = writer.Close()
+}
+
+func TestServe_Errors(t *testing.T) {
+	server := httptest.NewServer(newServer())
+	defer server.Close()
+
+	for _, tc := range []struct {
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{method: http.MethodPut, path: "/detectors/cpu", body: `{"config": {"influence": 2}, "initialValues": [1, 2]}`, status: http.StatusBadRequest},
+		{method: http.MethodPut, path: "/detectors/cpu", body: `{`, status: http.StatusBadRequest},
+		{method: http.MethodGet, path: "/detectors/missing", status: http.StatusNotFound},
+		{method: http.MethodGet, path: "/other", status: http.StatusNotFound},
+		{method: http.MethodPatch, path: "/detectors/cpu", status: http.StatusNotFound},
+	} {
+		resp := request(t, tc.method, server.URL+tc.path, tc.body)
+		if resp.StatusCode != tc.status {
+			t.Fatalf("Incorrect status for %s %s.\n  Expected: %d\n  Actual: %d", tc.method, tc.path, tc.status, resp.StatusCode)
+		}
+	}
+}
+
+func TestRun_ServeUsage(t *testing.T) {
+	err := run([]string{"serve", "extra"}, strings.NewReader(""), io.Discard, io.Discard)
+	if !errors.Is(err, errUsage) {
+		t.Fatalf("Invalid usage did not produce the correct error.\n  Expected: %s\n  Actual: %v", errUsage, err)
+	}
+}
+
+func request(t *testing.T, method, url, body string) *http.Response {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to create request.", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to send request.", err)
+	}
+	t.Cleanup(func() {
+		_ = resp.Body.Close()
+	})
+	return resp
+}
+
+func decode(t *testing.T, resp *http.Response, v any) {
+	err := json.NewDecoder(resp.Body).Decode(v)
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to decode response.", err)
+	}
+}
@@ -0,0 +1,225 @@
+package peakdetect
+
+import (
+	"sync"
+)
+
+// concurrentPeakDetector guards every method of a PeakDetector with a mutex.
+type concurrentPeakDetector struct {
+	detector PeakDetector
+	mux      sync.Mutex
+}
+
+// NewConcurrentPeakDetector creates a new PeakDetector that is safe for concurrent use, so multiple producer goroutines
+// can push values into one detector. Every method holds a mutex for its duration, so a call to NextBatch processes all
+// of its values before any other values. The order in which concurrent calls are processed is not defined. It must be
+// initialized before use.
+//
+// Values returned by the PeakDetector that share memory with it, such as the Labels map and the Histogram, are not
+// guarded.
+func NewConcurrentPeakDetector() PeakDetector {
+	return &concurrentPeakDetector{
+		detector: NewPeakDetector(),
+	}
+}
+
+func (c *concurrentPeakDetector) Initialize(influence, threshold float64, initialValues []float64) error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.detector.Initialize(influence, threshold, initialValues)
+}
+
+func (c *concurrentPeakDetector) InitializeWithConfig(cfg Config, initialValues []float64) error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.detector.InitializeWithConfig(cfg, initialValues)
+}
+
+func (c *concurrentPeakDetector) InitializeFromStats(mean, stdDev float64, lag uint, cfg Config) error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.detector.InitializeFromStats(mean, stdDev, lag, cfg)
+}
+
+func (c *concurrentPeakDetector) Stats() (mean, stdDev float64, lag uint) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.detector.Stats()
+}
+
+func (c *concurrentPeakDetector) InitializeAndDetect(cfg Config, values []float64) ([]Signal, error) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.detector.InitializeAndDetect(cfg, values)
+}
+
+func (c *concurrentPeakDetector) InitialOutliers() []int {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.detector.InitialOutliers()
+}
+
+func (c *concurrentPeakDetector) Next(value float64) Signal {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.detector.Next(value)
+}
+
+func (c *concurrentPeakDetector) NextMaybe(value float64, present bool) (Signal, error) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.detector.NextMaybe(value, present)
+}
+
+func (c *concurrentPeakDetector) NextBatch(values []float64) []Signal {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.detector.NextBatch(values)
+}
+
+func (c *concurrentPeakDetector) NextDetailed(value float64) Result {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.detector.NextDetailed(value)
+}
+
+func (c *concurrentPeakDetector) NextBatchInto(dst []Signal, values []float64) []Signal {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.detector.NextBatchInto(dst, values)
+}
+
+func (c *concurrentPeakDetector) NextBatchDetailed(values []float64) []Result {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.detector.NextBatchDetailed(values)
+}
+
+func (c *concurrentPeakDetector) Config() Config {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.detector.Config()
+}
+
+func (c *concurrentPeakDetector) Reconfigure(cfg Config) error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.detector.Reconfigure(cfg)
+}
+
+func (c *concurrentPeakDetector) SetInfluence(influence float64) error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.detector.SetInfluence(influence)
+}
+
+func (c *concurrentPeakDetector) SetThreshold(threshold float64) error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.detector.SetThreshold(threshold)
+}
+
+func (c *concurrentPeakDetector) Evaluate(cfg Config) ([]Signal, error) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.detector.Evaluate(cfg)
+}
+
+func (c *concurrentPeakDetector) Window() []float64 {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.detector.Window()
+}
+
+func (c *concurrentPeakDetector) Summary() WindowSummary {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.detector.Summary()
+}
+
+func (c *concurrentPeakDetector) SetMetrics(m Metrics) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.detector.SetMetrics(m)
+}
+
+func (c *concurrentPeakDetector) SetPreprocessor(preprocessor Preprocessor) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.detector.SetPreprocessor(preprocessor)
+}
+
+func (c *concurrentPeakDetector) SetHistogram(h *Histogram) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.detector.SetHistogram(h)
+}
+
+func (c *concurrentPeakDetector) SetLabels(labels map[string]string) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.detector.SetLabels(labels)
+}
+
+func (c *concurrentPeakDetector) Labels() map[string]string {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.detector.Labels()
+}
+
+func (c *concurrentPeakDetector) MemoryFootprint() uintptr {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.detector.MemoryFootprint()
+}
+
+func (c *concurrentPeakDetector) MarshalBinary() ([]byte, error) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.detector.MarshalBinary()
+}
+
+func (c *concurrentPeakDetector) UnmarshalBinary(data []byte) error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.detector.UnmarshalBinary(data)
+}
+
+func (c *concurrentPeakDetector) MarshalJSON() ([]byte, error) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.detector.MarshalJSON()
+}
+
+func (c *concurrentPeakDetector) UnmarshalJSON(data []byte) error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.detector.UnmarshalJSON(data)
+}
+
+func (c *concurrentPeakDetector) Reset() {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.detector.Reset()
+}
+
+// Clone returns a deep copy that is also safe for concurrent use, with its own mutex.
+func (c *concurrentPeakDetector) Clone() PeakDetector {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return &concurrentPeakDetector{
+		detector: c.detector.Clone(),
+	}
+}
+
+func (c *concurrentPeakDetector) OnSignal(direction Direction, handler func(event SignalEvent)) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.detector.OnSignal(direction, handler)
+}
+
+func (c *concurrentPeakDetector) Explain() Explanation {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.detector.Explain()
+}
@@ -0,0 +1,39 @@
+package peakdetect_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/MicahParks/peakdetect"
+)
+
+func TestConcurrentPeakDetector(t *testing.T) {
+	const producers = 8
+
+	detector := peakdetect.NewConcurrentPeakDetector()
+	err := detector.Initialize(exampleInfluence, exampleThreshold, exampleInputs[0:exampleLag])
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+
+	// Each producer pushes neutral values, so the signals do not depend on the order.
+	var wg sync.WaitGroup
+	for i := 0; i < producers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, v := range exampleInputs[:exampleLag] {
+				if signal := detector.Next(v); signal != peakdetect.SignalNeutral {
+					t.Errorf("Unexpected signal.\n  Actual: %d", signal)
+				}
+				detector.Summary()
+			}
+		}()
+	}
+	wg.Wait()
+
+	signals := detector.NextBatch(exampleInputs[exampleLag:])
+	if signals[15] != peakdetect.SignalPositive {
+		t.Fatalf("Detector did not signal after concurrent use.\n  Actual: %v", signals)
+	}
+}
@@ -0,0 +1,213 @@
+package peakdetect
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	// DirectionBoth signals for values on either side of the moving mean. This is the default.
+	DirectionBoth Direction = iota
+	// DirectionPositive only signals for values above the moving mean.
+	DirectionPositive
+	// DirectionNegative only signals for values below the moving mean.
+	DirectionNegative
+)
+
+// Direction is a set of enums that indicates which side of the moving mean can generate signals.
+type Direction uint8
+
+// ErrInvalidConfig indicates that the configuration provided is not valid.
+var ErrInvalidConfig = errors.New("the configuration provided is invalid")
+
+// Config is the configuration for a PeakDetector. See PeakDetector.Initialize for a description of Influence, Lag, and
+// Threshold. The remaining fields enable optional behavior and are disabled by their zero value.
+type Config struct {
+	Influence float64
+	Lag       uint
+	Threshold float64
+
+	// MinCoefficientOfVariation suppresses signals while the coefficient of variation of the lag window, which is its
+	// standard deviation divided by the absolute value of its mean, is below this floor. After a constant stretch, the
+	// standard deviation collapses and a trivially small move would otherwise be a signal. Values suppressed this way
+	// are neutral and are reported as low variance by PeakDetector.Explain.
+	MinCoefficientOfVariation float64
+
+	// InitialOutliers determines how outliers within the initial values are handled before the starting mean and
+	// standard deviation are computed. The trimmed values are also what is retained in the lag window. See
+	// PeakDetector.InitialOutliers for which values were trimmed.
+	InitialOutliers OutlierPolicy
+	// InitialOutlierThreshold is the number of scaled median absolute deviations from the median of the initial values
+	// beyond which a value is an outlier. If it is zero, 3.5 is used.
+	InitialOutlierThreshold float64
+
+	// TrackMoments tracks the rolling skewness and kurtosis of the lag window, which are then included in the
+	// WindowSummary.
+	TrackMoments bool
+	// SkewnessBound is the magnitude of the skewness of the lag window beyond which a meta-signal is included in the
+	// WindowSummary. It requires TrackMoments. Zero disables the meta-signal.
+	SkewnessBound float64
+	// KurtosisBound is the magnitude of the excess kurtosis of the lag window beyond which a meta-signal is included in
+	// the WindowSummary. It requires TrackMoments. Zero disables the meta-signal.
+	KurtosisBound float64
+
+	// MinStdDev is a floor on the standard deviation of the lag window used to determine signals. When the lag window is
+	// constant, the standard deviation collapses to zero and a trivially small move, such as from 5.00 to 4.88, would
+	// otherwise be a signal. Unlike MinCoefficientOfVariation, it is in the units of the data, so it does not depend on
+	// the mean. See Deadband for a floor on the deviation itself.
+	MinStdDev float64
+	// QuantizationStep is the step size of quantized inputs, such as 1 for integer sensors. The standard deviation of the
+	// lag window is floored at the standard deviation of the quantization error, QuantizationStep/sqrt(12). Without it,
+	// a coarse sensor that reads the same value for a while produces a tiny standard deviation, and a change of a single
+	// step is a huge z-score.
+	QuantizationStep float64
+	// Deadband is the absolute deviation from the moving mean at or below which a value never signals, regardless of the
+	// standard deviation. It is typically set to QuantizationStep to ignore a change of one step.
+	Deadband float64
+
+	// NegativeThreshold is the threshold for values below the moving mean, so data with a tight floor but a heavy upper
+	// tail can use a different threshold for each direction. Threshold applies to values above the moving mean, and to
+	// both directions if NegativeThreshold is zero.
+	NegativeThreshold float64
+	// NegativeInfluence is the influence of negative signals. Influence applies to positive signals, and to both
+	// directions if NegativeInfluence is nil. It is a pointer because an influence of zero is common.
+	NegativeInfluence *float64
+
+	// ReleaseThreshold enables hysteresis. Once a value signals, following values on the same side of the moving mean
+	// keep signaling until they are no more than ReleaseThreshold standard deviations from the moving mean, instead of
+	// Threshold. This prevents a noisy peak from flickering between a signal and neutral. It must not be greater than
+	// Threshold or NegativeThreshold.
+	ReleaseThreshold float64
+
+	// Percentile replaces the threshold with an order statistic of the lag window, for data that is far from normally
+	// distributed. A value signals when it is greater than the Percentile-th percentile of the values in the lag window,
+	// such as 99 for above p99, or less than the (100-Percentile)-th percentile, such as p1. The percentiles are linearly
+	// interpolated between the closest ranks. Threshold, NegativeThreshold, and the standard deviation floors are not
+	// used, but Deadband and MinCoefficientOfVariation are. It must be in the range (50, 100] and cannot be combined with
+	// ReleaseThreshold.
+	Percentile float64
+
+	// Persistence is the number of values that must exceed the threshold on the same side of the moving mean before a
+	// signal is reported, so a single value glitch does not signal without raising the threshold. The exceedances are
+	// counted over the most recent PersistenceWindow values, including the value itself, which must exceed the
+	// threshold. Exceedances waiting to persist are neutral, but they are still influence adjusted. Zero and one disable
+	// it.
+	Persistence uint
+	// PersistenceWindow is the number of most recent values that exceedances are counted over for Persistence, such as
+	// 5 to require 3 of the last 5 values. If it is zero, Persistence is used, so the exceedances must be consecutive. It
+	// must not be less than Persistence.
+	PersistenceWindow uint
+	// PersistenceBackfill reports a signal for the earlier exceedances that were waiting to persist once a signal is
+	// confirmed, so the signal starts at the first exceeded value. Values cannot be changed after they are returned by
+	// Next, so the backfilled signals are only reported by the handlers of PeakDetector.OnSignal and in the output of
+	// the calls that process many values, such as NextBatch and Detect.
+	PersistenceBackfill bool
+
+	// RefractoryPeriod is the number of values after a signal for which further signals are suppressed, so a slowly
+	// curving peak signals once instead of for every value above the threshold. The suppressed values are still influence
+	// adjusted, as they exceed the threshold. Values suppressed this way are neutral and are reported by
+	// PeakDetector.Explain.
+	RefractoryPeriod uint
+
+	// Direction determines which side of the moving mean can generate signals. Values beyond the threshold on the other
+	// side are neutral, but they are still influence adjusted, so excursions that are noise for the use case do not drag
+	// the moving mean and standard deviation with them.
+	Direction Direction
+
+	// DerivativeOrder runs the algorithm on the discrete derivative of this order of the values, such as the first
+	// difference for an order of one, instead of the values themselves. Peaks in data with long plateaus joined by curves
+	// are often clearer in its derivative. The first DerivativeOrder initial values are only used to start
+	// differencing, so the lag is that many fewer than the length of the initial values. The derivative is what the
+	// algorithm stores, summarizes, and explains.
+	DerivativeOrder uint
+
+	// ResyncInterval is the number of values after which the moving mean and standard deviation are recomputed from the
+	// lag window. The incremental updates use compensated summation, but some floating point error still accumulates
+	// over very long streams. The recomputation has a cost proportional to the lag. Zero disables it. It has no effect on
+	// a robust PeakDetector, whose statistics are exact.
+	ResyncInterval uint
+
+	// Missing determines how values that are missing, NaN, or ±Inf are handled. See PeakDetector.NextMaybe.
+	Missing MissingPolicy
+
+	// Baseline replaces the moving mean and standard deviation, or the median and median absolute deviation of a robust
+	// PeakDetector, with a custom estimate of the center and spread of the lag window. The rest of the algorithm is
+	// unchanged. The PeakDetector uses a Clone of it, so a Config can initialize many PeakDetectors. It is not encoded
+	// with the Config, and a PeakDetector that uses one cannot be serialized.
+	Baseline BaselineEstimator `json:"-"`
+}
+
+// allows determines if the Direction allows the non-neutral signal.
+func (d Direction) allows(signal Signal) bool {
+	switch d {
+	case DirectionPositive:
+		return signal == SignalPositive
+	case DirectionNegative:
+		return signal == SignalNegative
+	}
+	return true
+}
+
+// sided returns the threshold and influence for values on the side of the moving mean of the signal.
+func (c Config) sided(side Signal) (threshold, influence float64) {
+	threshold, influence = c.Threshold, c.Influence
+	if side == SignalNegative {
+		if c.NegativeThreshold != 0 {
+			threshold = c.NegativeThreshold
+		}
+		if c.NegativeInfluence != nil {
+			influence = *c.NegativeInfluence
+		}
+	}
+	return threshold, influence
+}
+
+// Validate checks that the Config is in range. Influence must be in the range [0, 1], Threshold and the optional
+// floors must not be negative, ReleaseThreshold must not be greater than either threshold, and PersistenceWindow must
+// not be less than Persistence. NegativeInfluence must be in the same range as Influence if it is set. Percentile must
+// be in the range (50, 100] if it is set.
+func (c Config) Validate() error {
+	if !(c.Influence >= 0 && c.Influence <= 1) {
+		return fmt.Errorf("the influence %f must be in the range [0, 1]: %w", c.Influence, ErrInvalidConfig)
+	}
+	if c.NegativeInfluence != nil && !(*c.NegativeInfluence >= 0 && *c.NegativeInfluence <= 1) {
+		return fmt.Errorf("the negative influence %f must be in the range [0, 1]: %w", *c.NegativeInfluence, ErrInvalidConfig)
+	}
+	for _, field := range []struct {
+		name  string
+		value float64
+	}{
+		{name: "threshold", value: c.Threshold},
+		{name: "negative threshold", value: c.NegativeThreshold},
+		{name: "release threshold", value: c.ReleaseThreshold},
+		{name: "minimum coefficient of variation", value: c.MinCoefficientOfVariation},
+		{name: "initial outlier threshold", value: c.InitialOutlierThreshold},
+		{name: "skewness bound", value: c.SkewnessBound},
+		{name: "kurtosis bound", value: c.KurtosisBound},
+		{name: "minimum standard deviation", value: c.MinStdDev},
+		{name: "quantization step", value: c.QuantizationStep},
+		{name: "deadband", value: c.Deadband},
+	} {
+		if !(field.value >= 0) {
+			return fmt.Errorf("the %s %f must not be negative: %w", field.name, field.value, ErrInvalidConfig)
+		}
+	}
+	if c.Percentile != 0 {
+		if !(c.Percentile > 50 && c.Percentile <= 100) {
+			return fmt.Errorf("the percentile %f must be in the range (50, 100]: %w", c.Percentile, ErrInvalidConfig)
+		}
+		if c.ReleaseThreshold != 0 {
+			return fmt.Errorf("a percentile cannot be combined with a release threshold: %w", ErrInvalidConfig)
+		}
+	}
+	if c.PersistenceWindow != 0 && c.PersistenceWindow < c.Persistence {
+		return fmt.Errorf("the persistence window %d must not be less than the persistence %d: %w", c.PersistenceWindow, c.Persistence, ErrInvalidConfig)
+	}
+	for _, side := range []Signal{SignalPositive, SignalNegative} {
+		threshold, _ := c.sided(side)
+		if c.ReleaseThreshold > threshold {
+			return fmt.Errorf("the release threshold %f must not be greater than the threshold %f: %w", c.ReleaseThreshold, threshold, ErrInvalidConfig)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package peakdetect
+
+import (
+	"context"
+	"time"
+)
+
+// SignalEvent is the signal for a value along with where the value came from.
+type SignalEvent struct {
+	// Index is the index of the value, counting every value given to the PeakDetector by the producer of the event,
+	// starting at zero.
+	Index uint64
+	// Labels are the labels attached to the PeakDetector that produced the event.
+	Labels map[string]string
+	// Signal is the signal for the value.
+	Signal Signal
+	// Time is the timestamp of the value. It is the zero value if the value did not have a timestamp.
+	Time time.Time
+	// Value is the value that was processed.
+	Value float64
+}
+
+// Consume feeds the detector from a channel of arbitrary items. The extract function returns the value and timestamp
+// of an item, or false for ok if the item should be skipped. Skipped items are not counted in the index of the events.
+//
+// A goroutine is started to process the items. The returned channel receives an event for every value processed and
+// is closed after the input channel is closed and drained. The detector must be initialized and must not be used
+// elsewhere until the returned channel is closed.
+func Consume[T any](detector PeakDetector, in <-chan T, extract func(item T) (value float64, t time.Time, ok bool)) <-chan SignalEvent {
+	out := make(chan SignalEvent)
+	go func() {
+		defer close(out)
+		var index uint64
+		for item := range in {
+			value, t, ok := extract(item)
+			if !ok {
+				continue
+			}
+			out <- SignalEvent{
+				Index:  index,
+				Labels: detector.Labels(),
+				Signal: detector.Next(value),
+				Time:   t,
+				Value:  value,
+			}
+			index++
+		}
+	}()
+	return out
+}
+
+// Stream feeds the detector from a channel of values until the input channel is closed or the context is done. The Time
+// of each event is when its value was received.
+//
+// A goroutine is started to process the values. The returned channel receives an event for every value processed and
+// is closed when the goroutine exits. Once the context is done, the goroutine exits without sending any further events,
+// even if the input channel still has values. The detector must be initialized and must not be used elsewhere until
+// the returned channel is closed.
+func Stream(ctx context.Context, detector PeakDetector, in <-chan float64) <-chan SignalEvent {
+	out := make(chan SignalEvent)
+	go func() {
+		defer close(out)
+		var index uint64
+		for {
+			var value float64
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-in:
+				if !ok {
+					return
+				}
+				value = v
+			}
+			event := SignalEvent{
+				Index:  index,
+				Labels: detector.Labels(),
+				Signal: detector.Next(value),
+				Time:   time.Now(),
+				Value:  value,
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case out <- event:
+			}
+			index++
+		}
+	}()
+	return out
+}
@@ -0,0 +1,116 @@
+package peakdetect_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/MicahParks/peakdetect"
+)
+
+type consumeItem struct {
+	reading float64
+	t       time.Time
+	valid   bool
+}
+
+func TestConsume(t *testing.T) {
+	detector := peakdetect.NewPeakDetector()
+	detector.SetLabels(map[string]string{"series": "consume"})
+	err := detector.Initialize(exampleInfluence, exampleThreshold, exampleInputs[0:exampleLag])
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	in := make(chan consumeItem)
+	go func() {
+		defer close(in)
+		for i, v := range exampleInputs[exampleLag:] {
+			in <- consumeItem{valid: false}
+			in <- consumeItem{
+				reading: v,
+				t:       start.Add(time.Duration(i) * time.Second),
+				valid:   true,
+			}
+		}
+	}()
+
+	extract := func(item consumeItem) (float64, time.Time, bool) {
+		return item.reading, item.t, item.valid
+	}
+
+	var count int
+	for event := range peakdetect.Consume(detector, in, extract) {
+		expected := exampleOutputs[int(event.Index)+exampleLag]
+		if event.Signal != expected {
+			t.Fatalf("Consumed signal did not match example signal.\n  Example: %d\n  Actual: %d", expected, event.Signal)
+		}
+		if event.Labels["series"] != "consume" {
+			t.Fatalf("Consumed event did not have the detector labels.\n  Actual: %v", event.Labels)
+		}
+		if !event.Time.Equal(start.Add(time.Duration(event.Index) * time.Second)) {
+			t.Fatalf("Consumed event did not have the extracted time.\n  Actual: %s", event.Time)
+		}
+		count++
+	}
+
+	if count != len(exampleInputs)-exampleLag {
+		t.Fatalf("Unexpected number of events.\n  Expected: %d\n  Actual: %d", len(exampleInputs)-exampleLag, count)
+	}
+}
+
+func TestStream(t *testing.T) {
+	detector := peakdetect.NewPeakDetector()
+	err := detector.Initialize(exampleInfluence, exampleThreshold, exampleInputs[0:exampleLag])
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+
+	in := make(chan float64, len(exampleInputs))
+	for _, v := range exampleInputs[exampleLag:] {
+		in <- v
+	}
+	close(in)
+
+	var count int
+	for event := range peakdetect.Stream(context.Background(), detector, in) {
+		expected := exampleOutputs[int(event.Index)+exampleLag]
+		if event.Signal != expected {
+			t.Fatalf("Streamed signal did not match example signal.\n  Example: %d\n  Actual: %d", expected, event.Signal)
+		}
+		if event.Value != exampleInputs[int(event.Index)+exampleLag] || event.Time.IsZero() {
+			t.Fatalf("Streamed event did not have the value and time.\n  Actual: %+v", event)
+		}
+		count++
+	}
+
+	if count != len(exampleInputs)-exampleLag {
+		t.Fatalf("Unexpected number of events.\n  Expected: %d\n  Actual: %d", len(exampleInputs)-exampleLag, count)
+	}
+}
+
+func TestStream_Cancel(t *testing.T) {
+	detector := peakdetect.NewPeakDetector()
+	err := detector.Initialize(exampleInfluence, exampleThreshold, exampleInputs[0:exampleLag])
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan float64)
+	out := peakdetect.Stream(ctx, detector, in)
+
+	in <- exampleInputs[exampleLag]
+	<-out
+	cancel()
+
+	select {
+	case _, ok := <-out:
+		if ok {
+			t.Fatalf("No events should be sent after the context is done.")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("The output channel was not closed after the context was done.")
+	}
+}
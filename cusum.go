@@ -0,0 +1,127 @@
+package peakdetect
+
+import (
+	"fmt"
+	"math"
+)
+
+// CUSUMDetector detects sustained shifts in the level of realtime timeseries data using a two-sided cumulative sum
+// (CUSUM) control chart. A PeakDetector signals for values that deviate from the moving mean, so a small but
+// sustained shift goes unnoticed. The CUSUM accumulates small deviations until they are significant.
+//
+// Deviations are standardized by the reference mean and standard deviation. The upper sum accumulates standardized
+// deviations above the drift and the lower sum accumulates those below the negative drift, neither dropping below zero.
+// When either sum exceeds the threshold, a signal is produced, both sums are reset, and the reference mean and standard
+// deviation are re-estimated from the next lag values, during which no signals are produced. A sustained shift
+// therefore produces one signal.
+type CUSUMDetector interface {
+	// Initialize initializes the CUSUMDetector with its configuration and initialValues. The mean and population
+	// standard deviation of the initialValues are the reference, and their length is the lag used to re-estimate the
+	// reference after a signal. The initialValues must not all be equal.
+	//
+	// drift is the number of standard deviations a value may deviate from the reference mean without accumulating,
+	// typically half of the smallest shift that should be detected. threshold is the number of accumulated standard
+	// deviations that produces a signal, typically 4 or 5.
+	Initialize(drift, threshold float64, initialValues []float64) error
+	// Next processes the next value and determines its signal. SignalPositive is an upward shift and SignalNegative is
+	// a downward shift.
+	Next(value float64) Signal
+	// NextBatch processes the next values and determines their signals. Their signals will be returned in a slice equal
+	// to the length of the input.
+	NextBatch(values []float64) []Signal
+	// Sums returns the current upper and lower cumulative sums.
+	Sums() (upper, lower float64)
+	// MemoryFootprint returns the approximate number of bytes used by the CUSUMDetector.
+	MemoryFootprint() uintptr
+}
+
+type cusumDetector struct {
+	drift     float64
+	lower     float64
+	mean      float64
+	stdDev    float64
+	threshold float64
+	upper     float64
+	warming   bool
+	warmup    []float64
+}
+
+// NewCUSUMDetector creates a new CUSUMDetector. It must be initialized before use.
+func NewCUSUMDetector() CUSUMDetector {
+	return &cusumDetector{}
+}
+
+func (c *cusumDetector) Initialize(drift, threshold float64, initialValues []float64) error {
+	if len(initialValues) == 0 {
+		return fmt.Errorf("the length of the initial values is zero, the length is used as the lag for the algorithm: %w", ErrInvalidInitialValues)
+	}
+	if !(drift >= 0) || !(threshold > 0) {
+		return fmt.Errorf("the drift %f must not be negative and the threshold %f must be positive: %w", drift, threshold, ErrInvalidConfig)
+	}
+	mean, stdDev := meanStdDev(initialValues)
+	if stdDev == 0 {
+		return fmt.Errorf("the initial values must not all be equal: %w", ErrInvalidInitialValues)
+	}
+	*c = cusumDetector{
+		drift:     drift,
+		mean:      mean,
+		stdDev:    stdDev,
+		threshold: threshold,
+		warmup:    make([]float64, 0, len(initialValues)),
+	}
+	return nil
+}
+
+func (c *cusumDetector) Next(value float64) Signal {
+	if c.warming {
+		c.rebaseline(value)
+		return SignalNeutral
+	}
+
+	z := (value - c.mean) / c.stdDev
+	c.upper = math.Max(0, c.upper+z-c.drift)
+	c.lower = math.Max(0, c.lower-z-c.drift)
+
+	var signal Signal
+	switch {
+	case c.upper > c.threshold:
+		signal = SignalPositive
+	case c.lower > c.threshold:
+		signal = SignalNegative
+	default:
+		return SignalNeutral
+	}
+
+	c.upper, c.lower = 0, 0
+	c.warming = true
+	c.rebaseline(value)
+	return signal
+}
+
+// rebaseline adds the value to the values used to re-estimate the reference. Once there are lag values, the reference
+// is replaced. The standard deviation is kept if the values are all equal.
+func (c *cusumDetector) rebaseline(value float64) {
+	c.warmup = append(c.warmup, value)
+	if len(c.warmup) < cap(c.warmup) {
+		return
+	}
+	mean, stdDev := meanStdDev(c.warmup)
+	c.mean = mean
+	if stdDev > 0 {
+		c.stdDev = stdDev
+	}
+	c.warmup = c.warmup[:0]
+	c.warming = false
+}
+
+func (c *cusumDetector) NextBatch(values []float64) []Signal {
+	signals := make([]Signal, len(values))
+	for i, v := range values {
+		signals[i] = c.Next(v)
+	}
+	return signals
+}
+
+func (c *cusumDetector) Sums() (upper, lower float64) {
+	return c.upper, c.lower
+}
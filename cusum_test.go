@@ -0,0 +1,57 @@
+package peakdetect_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/MicahParks/peakdetect"
+)
+
+func TestCUSUMDetector_Initialize(t *testing.T) {
+	detector := peakdetect.NewCUSUMDetector()
+	err := detector.Initialize(0.5, 5, []float64{1, 1, 1})
+	if !errors.Is(err, peakdetect.ErrInvalidInitialValues) {
+		t.Fatalf("Constant initial values did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidInitialValues, err)
+	}
+	err = detector.Initialize(-1, 5, exampleInputs[:exampleLag])
+	if !errors.Is(err, peakdetect.ErrInvalidConfig) {
+		t.Fatalf("Negative drift did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidConfig, err)
+	}
+}
+
+func TestCUSUMDetector_NextBatch(t *testing.T) {
+	// Shifts of half of the range of the noise, which never exceed a z-score threshold on their own.
+	noise := []float64{0.1, -0.1, 0, 0.05, -0.05}
+	var data []float64
+	for _, level := range []float64{1, 1.1, 1} {
+		for i := 0; i < 40; i++ {
+			data = append(data, level+noise[i%len(noise)])
+		}
+	}
+
+	const lag = 20
+	detector := peakdetect.NewCUSUMDetector()
+	err := detector.Initialize(0.5, 5, data[:lag])
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+
+	var indices []int
+	var signals []peakdetect.Signal
+	for i, signal := range detector.NextBatch(data[lag:]) {
+		if signal != peakdetect.SignalNeutral {
+			indices = append(indices, lag+i)
+			signals = append(signals, signal)
+		}
+	}
+
+	if len(signals) != 2 || signals[0] != peakdetect.SignalPositive || signals[1] != peakdetect.SignalNegative {
+		t.Fatalf("Expected one upward and one downward shift.\n  Actual: %v at %v", signals, indices)
+	}
+	if indices[0] < 40 || indices[0] > 45 || indices[1] < 80 || indices[1] > 85 {
+		t.Fatalf("Shifts were not detected promptly.\n  Actual: %v", indices)
+	}
+	if upper, lower := detector.Sums(); upper < 0 || lower < 0 {
+		t.Fatalf("Sums must not be negative.\n  Actual: %f, %f", upper, lower)
+	}
+}
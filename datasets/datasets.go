@@ -0,0 +1,105 @@
+// Package datasets provides representative sample datasets with ground truth peaks for tests, examples, benchmarks, and
+// tuning.
+package datasets
+
+import (
+	"bytes"
+	"embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// ECG is a synthetic electrocardiogram snippet sampled at 250 Hz. Its peaks are the R waves.
+	ECG = "ecg"
+	// FlatRamp is a flat series that turns into a linear ramp, sampled every minute, with one spike on each part.
+	FlatRamp = "flat-ramp"
+	// Seasonal is two weeks of hourly data with a daily cycle and two spikes.
+	Seasonal = "seasonal"
+	// StackOverflow is the example data from the R example of the algorithm's author, sampled every second.
+	// https://stackoverflow.com/a/54507329/14797322
+	StackOverflow = "stackoverflow"
+)
+
+// ErrNotFound indicates that no dataset has the requested name.
+var ErrNotFound = errors.New("the dataset was not found")
+
+//go:embed data/*.csv
+var data embed.FS
+
+// Dataset is a timeseries with ground truth peaks.
+type Dataset struct {
+	// Name is the name of the dataset.
+	Name string
+	// Peaks are the indices of the ground truth peaks, in ascending order.
+	Peaks []int
+	// Times are the timestamps of the values.
+	Times []time.Time
+	// Values are the values of the timeseries.
+	Values []float64
+}
+
+// Load loads the dataset with the given name. Each call returns new slices that may be modified.
+func Load(name string) (Dataset, error) {
+	raw, err := data.ReadFile("data/" + name + ".csv")
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return Dataset{}, fmt.Errorf("dataset %q: %w", name, ErrNotFound)
+		}
+		return Dataset{}, fmt.Errorf("failed to read dataset %q: %w", name, err)
+	}
+
+	reader := csv.NewReader(bytes.NewReader(raw))
+	_, err = reader.Read()
+	if err != nil {
+		return Dataset{}, fmt.Errorf("failed to read header of dataset %q: %w", name, err)
+	}
+
+	dataset := Dataset{
+		Name: name,
+	}
+	for i := 0; ; i++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return Dataset{}, fmt.Errorf("failed to read record of dataset %q: %w", name, err)
+		}
+
+		t, err := time.Parse(time.RFC3339Nano, record[0])
+		if err != nil {
+			return Dataset{}, fmt.Errorf("failed to parse time of dataset %q at index %d: %w", name, i, err)
+		}
+		value, err := strconv.ParseFloat(record[1], 64)
+		if err != nil {
+			return Dataset{}, fmt.Errorf("failed to parse value of dataset %q at index %d: %w", name, i, err)
+		}
+
+		dataset.Times = append(dataset.Times, t)
+		dataset.Values = append(dataset.Values, value)
+		if record[2] == "1" {
+			dataset.Peaks = append(dataset.Peaks, i)
+		}
+	}
+
+	return dataset, nil
+}
+
+// Names returns the names of all available datasets in alphabetical order.
+func Names() []string {
+	entries, _ := data.ReadDir("data")
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, strings.TrimSuffix(entry.Name(), ".csv"))
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,40 @@
+package datasets_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/MicahParks/peakdetect/datasets"
+)
+
+func TestLoad(t *testing.T) {
+	names := datasets.Names()
+	expected := []string{datasets.ECG, datasets.FlatRamp, datasets.Seasonal, datasets.StackOverflow}
+	if len(names) != len(expected) {
+		t.Fatalf("Unexpected number of datasets.\n  Expected: %d\n  Actual: %d", len(expected), len(names))
+	}
+
+	for i, name := range names {
+		if name != expected[i] {
+			t.Fatalf("Dataset name did not match.\n  Expected: %s\n  Actual: %s", expected[i], name)
+		}
+
+		dataset, err := datasets.Load(name)
+		if err != nil {
+			t.Fatalf("Failed to load dataset %q.\nError: %s", name, err)
+		}
+		if len(dataset.Values) == 0 || len(dataset.Values) != len(dataset.Times) || len(dataset.Peaks) == 0 {
+			t.Fatalf("Dataset %q is incomplete.", name)
+		}
+		for j := 1; j < len(dataset.Times); j++ {
+			if !dataset.Times[j].After(dataset.Times[j-1]) {
+				t.Fatalf("Dataset %q times are not ascending at index %d.", name, j)
+			}
+		}
+	}
+
+	_, err := datasets.Load("missing")
+	if !errors.Is(err, datasets.ErrNotFound) {
+		t.Fatalf("Missing dataset did not produce error.\n  Expected: %s\n  Actual: %s", datasets.ErrNotFound, err)
+	}
+}
@@ -0,0 +1,177 @@
+package peakdetect
+
+import (
+	"fmt"
+)
+
+const (
+	// DecimationMean uses the mean of the values in each bucket. It smooths noise, but it also flattens narrow peaks.
+	DecimationMean Decimation = iota
+	// DecimationMax uses the maximum of the values in each bucket, so the apex of every positive peak is kept intact.
+	DecimationMax
+	// DecimationMinMax uses both the minimum and the maximum of the values in each bucket, in the order they occurred,
+	// so the apexes of both positive and negative peaks are kept intact. Each bucket produces two values.
+	DecimationMinMax
+)
+
+// Decimation is a set of enums that indicates how a Decimator aggregates the values in each bucket.
+type Decimation uint8
+
+// DecimatedValue is a value produced by a Decimator along with its signal.
+type DecimatedValue struct {
+	// Index is the index of the input value that the value represents, counting every value given to the Decimator
+	// starting at zero. It is the index of the minimum or maximum for DecimationMax and DecimationMinMax, which is the
+	// location of the apex of a peak. It is the index of the first value of the bucket for DecimationMean.
+	Index uint64
+	// Signal is the signal for the value.
+	Signal Signal
+	// Value is the aggregated value that was given to the PeakDetector.
+	Value float64
+}
+
+// Decimator downsamples a high rate stream in front of a PeakDetector, so throughput can be traded for resolution
+// without writing an aggregation. Every factor values are aggregated into a bucket, and the aggregate is given to the
+// PeakDetector. The PeakDetector must already be initialized with values at the decimated rate, such as from Decimate.
+type Decimator struct {
+	count      uint
+	decimation Decimation
+	detector   PeakDetector
+	factor     uint
+	index      uint64
+	max        float64
+	maxIndex   uint64
+	min        float64
+	minIndex   uint64
+	start      uint64
+	sum        float64
+}
+
+// NewDecimator creates a new Decimator that aggregates every factor values for the detector. The factor must be greater
+// than zero.
+func NewDecimator(detector PeakDetector, factor uint, decimation Decimation) (*Decimator, error) {
+	if factor == 0 {
+		return nil, fmt.Errorf("the factor for a decimator must be greater than zero: %w", ErrInvalidConfig)
+	}
+	return &Decimator{
+		decimation: decimation,
+		detector:   detector,
+		factor:     factor,
+	}, nil
+}
+
+// Decimate aggregates every factor values of a complete series, such as to produce the initial values of the
+// PeakDetector for a Decimator. An incomplete final bucket is aggregated from the values it has. A factor of zero is
+// treated as one.
+func Decimate(values []float64, factor uint, decimation Decimation) []float64 {
+	if factor == 0 {
+		factor = 1
+	}
+	decimated := make([]float64, 0, (uint(len(values))+factor-1)/factor)
+	var d Decimator
+	d.factor = factor
+	d.decimation = decimation
+	for _, v := range values {
+		if d.add(v) {
+			decimated = d.appendValues(decimated)
+		}
+	}
+	if d.count > 0 {
+		decimated = d.appendValues(decimated)
+	}
+	return decimated
+}
+
+// Next adds the value to the current bucket. When the value completes the bucket, its aggregate is given to the
+// PeakDetector and returned with its signal. Otherwise, nothing is returned.
+func (d *Decimator) Next(value float64) []DecimatedValue {
+	if !d.add(value) {
+		return nil
+	}
+	return d.flush(nil)
+}
+
+// NextBatch adds the values like Next and returns the aggregates of every bucket they completed.
+func (d *Decimator) NextBatch(values []float64) []DecimatedValue {
+	decimated := make([]DecimatedValue, 0, (uint(len(values))/d.factor+1)*2)
+	for _, v := range values {
+		if d.add(v) {
+			decimated = d.flush(decimated)
+		}
+	}
+	return decimated
+}
+
+// Flush gives the aggregate of the current incomplete bucket to the PeakDetector, if it has any values, and returns it
+// with its signal. It is used at the end of a stream.
+func (d *Decimator) Flush() []DecimatedValue {
+	if d.count == 0 {
+		return nil
+	}
+	return d.flush(nil)
+}
+
+// Detector returns the PeakDetector that the aggregates are given to.
+func (d *Decimator) Detector() PeakDetector {
+	return d.detector
+}
+
+// add adds the value to the current bucket and reports if the bucket is complete.
+func (d *Decimator) add(value float64) bool {
+	index := d.index
+	d.index++
+	if d.count == 0 {
+		d.max, d.maxIndex = value, index
+		d.min, d.minIndex = value, index
+		d.start = index
+		d.sum = 0
+	}
+	d.count++
+	d.sum += value
+	if value > d.max {
+		d.max, d.maxIndex = value, index
+	}
+	if value < d.min {
+		d.min, d.minIndex = value, index
+	}
+	return d.count == d.factor
+}
+
+// aggregate returns the aggregates of the current bucket and resets it.
+func (d *Decimator) aggregate() (values [2]DecimatedValue, n int) {
+	switch d.decimation {
+	case DecimationMax:
+		values[0] = DecimatedValue{Index: d.maxIndex, Value: d.max}
+		n = 1
+	case DecimationMinMax:
+		values[0] = DecimatedValue{Index: d.minIndex, Value: d.min}
+		values[1] = DecimatedValue{Index: d.maxIndex, Value: d.max}
+		if d.maxIndex < d.minIndex {
+			values[0], values[1] = values[1], values[0]
+		}
+		n = 2
+	default:
+		values[0] = DecimatedValue{Index: d.start, Value: d.sum / float64(d.count)}
+		n = 1
+	}
+	d.count = 0
+	return values, n
+}
+
+// appendValues appends the aggregates of the current bucket without detection.
+func (d *Decimator) appendValues(dst []float64) []float64 {
+	values, n := d.aggregate()
+	for _, v := range values[:n] {
+		dst = append(dst, v.Value)
+	}
+	return dst
+}
+
+// flush gives the aggregates of the current bucket to the PeakDetector and appends them to dst.
+func (d *Decimator) flush(dst []DecimatedValue) []DecimatedValue {
+	values, n := d.aggregate()
+	for _, v := range values[:n] {
+		v.Signal = d.detector.Next(v.Value)
+		dst = append(dst, v)
+	}
+	return dst
+}
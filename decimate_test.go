@@ -0,0 +1,111 @@
+package peakdetect_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/MicahParks/peakdetect"
+)
+
+func TestNewDecimator(t *testing.T) {
+	_, err := peakdetect.NewDecimator(peakdetect.NewPeakDetector(), 0, peakdetect.DecimationMean)
+	if !errors.Is(err, peakdetect.ErrInvalidConfig) {
+		t.Fatalf("Invalid factor did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidConfig, err)
+	}
+}
+
+func TestDecimate(t *testing.T) {
+	values := []float64{1, 3, 2, 8, 4, 0, 5}
+	testCases := map[peakdetect.Decimation][]float64{
+		peakdetect.DecimationMean:   {2, 4, 5},
+		peakdetect.DecimationMax:    {3, 8, 5},
+		peakdetect.DecimationMinMax: {1, 3, 8, 0, 5, 5},
+	}
+	for decimation, expected := range testCases {
+		actual := peakdetect.Decimate(values, 3, decimation)
+		if len(actual) != len(expected) {
+			t.Fatalf("Incorrect number of decimated values for decimation %d.\n  Expected: %v\n  Actual: %v", decimation, expected, actual)
+		}
+		for i, v := range expected {
+			if actual[i] != v {
+				t.Fatalf("Incorrect decimated value at index %d for decimation %d.\n  Expected: %v\n  Actual: %v", i, decimation, expected, actual)
+			}
+		}
+	}
+}
+
+func TestDecimator_Next(t *testing.T) {
+	const factor = 10
+	noise := []float64{0.3, -0.2, 0.1, -0.3, 0.2, -0.1, 0}
+	data := make([]float64, 1000)
+	for i := range data {
+		data[i] = 1 + noise[i%len(noise)]
+	}
+	// A narrow peak that is flattened by the mean of its bucket.
+	const apex = 853
+	data[apex] = 5
+
+	for decimation, expectSignal := range map[peakdetect.Decimation]bool{
+		peakdetect.DecimationMean:   false,
+		peakdetect.DecimationMax:    true,
+		peakdetect.DecimationMinMax: true,
+	} {
+		const initial = 500
+		detector, err := peakdetect.NewFromOptions(peakdetect.Decimate(data[:initial], factor, decimation), peakdetect.WithThreshold(exampleThreshold), peakdetect.WithMinStdDev(0.1))
+		if err != nil {
+			t.Fatalf(logFmt, "Failed to create peak detector.", err)
+		}
+		decimator, err := peakdetect.NewDecimator(detector, factor, decimation)
+		if err != nil {
+			t.Fatalf(logFmt, "Failed to create decimator.", err)
+		}
+
+		var decimated []peakdetect.DecimatedValue
+		for _, v := range data[initial:] {
+			decimated = append(decimated, decimator.Next(v)...)
+		}
+		expectedLen := (len(data) - initial) / factor
+		if decimation == peakdetect.DecimationMinMax {
+			expectedLen *= 2
+		}
+		if len(decimated) != expectedLen {
+			t.Fatalf("Incorrect number of decimated values for decimation %d.\n  Expected: %d\n  Actual: %d", decimation, expectedLen, len(decimated))
+		}
+
+		var signaled bool
+		for _, d := range decimated {
+			if d.Signal == peakdetect.SignalNeutral {
+				continue
+			}
+			signaled = true
+			// The indices of the decimator start after the initial values.
+			if d.Index+initial != apex || d.Value != 5 {
+				t.Fatalf("The signal was not at the apex for decimation %d.\n  Expected: %d\n  Actual: %+v", decimation, apex, d)
+			}
+		}
+		if signaled != expectSignal {
+			t.Fatalf("Incorrect detection of the peak for decimation %d.\n  Expected: %t\n  Actual: %t", decimation, expectSignal, signaled)
+		}
+	}
+}
+
+func TestDecimator_Flush(t *testing.T) {
+	detector, err := peakdetect.NewFromOptions([]float64{1, 1.1, 0.9, 1}, peakdetect.WithThreshold(exampleThreshold))
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to create peak detector.", err)
+	}
+	decimator, err := peakdetect.NewDecimator(detector, 4, peakdetect.DecimationMean)
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to create decimator.", err)
+	}
+
+	if decimated := decimator.NextBatch([]float64{1, 2, 3, 4, 5, 6}); len(decimated) != 1 || decimated[0].Value != 2.5 {
+		t.Fatalf("Incorrect decimated values.\n  Expected: %v\n  Actual: %+v", 2.5, decimated)
+	}
+	if decimated := decimator.Flush(); len(decimated) != 1 || decimated[0].Value != 5.5 || decimated[0].Index != 4 {
+		t.Fatalf("Incorrect flushed values.\n  Expected: %v\n  Actual: %+v", 5.5, decimated)
+	}
+	if decimated := decimator.Flush(); decimated != nil {
+		t.Fatalf("An empty bucket should not be flushed.\n  Actual: %+v", decimated)
+	}
+}
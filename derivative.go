@@ -0,0 +1,33 @@
+package peakdetect
+
+// differencer computes the discrete derivative of a series of the given order, such as the first difference for an
+// order of one.
+type differencer struct {
+	// prev holds the most recent value of the series and each of its derivatives below the order.
+	prev []float64
+	// seen is the number of levels of prev that hold a value.
+	seen int
+}
+
+func newDifferencer(order uint) *differencer {
+	return &differencer{
+		prev: make([]float64, order),
+	}
+}
+
+// next adds the value to the series and returns the next value of its derivative. ok is false until order+1 values
+// have been added.
+func (d *differencer) next(value float64) (derivative float64, ok bool) {
+	derivative = value
+	for k := range d.prev {
+		if k == d.seen {
+			d.prev[k] = derivative
+			d.seen++
+			return 0, false
+		}
+		next := derivative - d.prev[k]
+		d.prev[k] = derivative
+		derivative = next
+	}
+	return derivative, true
+}
@@ -0,0 +1,13 @@
+package peakdetect
+
+// Detect runs the algorithm over a complete series, such as one loaded from a CSV file. The first cfg.Lag values,
+// plus cfg.DerivativeOrder, are used for initialization, and their signals are neutral, so the returned signals line
+// up with the data. The peaks are the signals grouped by GroupPeaks, with indices into the data. See
+// PeakDetector.InitializeAndDetect.
+func Detect(data []float64, cfg Config) (signals []Signal, peaks []PeakEvent, err error) {
+	signals, err = NewPeakDetector().InitializeAndDetect(cfg, data)
+	if err != nil {
+		return nil, nil, err
+	}
+	return signals, GroupPeaks(signals, data), nil
+}
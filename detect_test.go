@@ -0,0 +1,39 @@
+package peakdetect_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/MicahParks/peakdetect"
+)
+
+func TestDetect(t *testing.T) {
+	signals, peaks, err := peakdetect.Detect(exampleInputs, peakdetect.Config{
+		Influence: exampleInfluence,
+		Lag:       exampleLag,
+		Threshold: exampleThreshold,
+	})
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to detect.", err)
+	}
+
+	if !reflect.DeepEqual(signals, exampleOutputs) {
+		t.Fatalf("Incorrect signals.\n  Expected: %v\n  Actual: %v", exampleOutputs, signals)
+	}
+	if len(peaks) != 4 {
+		t.Fatalf("Incorrect number of peaks.\n  Expected: %d\n  Actual: %d", 4, len(peaks))
+	}
+	if peaks[1].StartIndex != 47 || peaks[1].ApexIndex != 49 || peaks[1].ApexValue != exampleInputs[49] {
+		t.Fatalf("Peak indices should be into the data.\n  Actual: %+v", peaks[1])
+	}
+}
+
+func TestDetectInvalidLag(t *testing.T) {
+	for _, lag := range []uint{0, uint(len(exampleInputs)) + 1} {
+		_, _, err := peakdetect.Detect(exampleInputs, peakdetect.Config{Lag: lag})
+		if !errors.Is(err, peakdetect.ErrInvalidConfig) {
+			t.Fatalf("Invalid lag did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidConfig, err)
+		}
+	}
+}
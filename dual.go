@@ -0,0 +1,76 @@
+package peakdetect
+
+import (
+	"fmt"
+	"math"
+)
+
+// DualTimescaleDetector detects when the mean of a short moving window departs from the mean of a long moving window.
+// Comparing two means is much less sensitive to noise in a single value than the per-value test of a PeakDetector.
+type DualTimescaleDetector interface {
+	// Initialize initializes the DualTimescaleDetector with its configuration and initialValues. The length of the
+	// initialValues is the lag of the long window. The DualTimescaleDetector will never return any signals for the
+	// initialValues.
+	//
+	// shortLag is the lag of the short window. It must be greater than zero and less than the length of the
+	// initialValues.
+	//
+	// threshold is the number of standard deviations of the long window that the short window mean must be from the
+	// long window mean to be a signal.
+	Initialize(shortLag uint, threshold float64, initialValues []float64) error
+	// Next processes the next value and determines its signal.
+	Next(value float64) Signal
+	// NextBatch processes the next values and determines their signals. Their signals will be returned in a slice equal
+	// to the length of the input.
+	NextBatch(values []float64) []Signal
+}
+
+type dualTimescaleDetector struct {
+	long      *SlidingStats
+	short     *SlidingStats
+	threshold float64
+}
+
+// NewDualTimescaleDetector creates a new DualTimescaleDetector. It must be initialized before use.
+func NewDualTimescaleDetector() DualTimescaleDetector {
+	return &dualTimescaleDetector{
+		long:  &SlidingStats{},
+		short: &SlidingStats{},
+	}
+}
+
+func (d *dualTimescaleDetector) Initialize(shortLag uint, threshold float64, initialValues []float64) error {
+	longLag := uint(len(initialValues))
+	if longLag == 0 {
+		return fmt.Errorf("the length of the initial values is zero, the length is used as the long lag: %w", ErrInvalidInitialValues)
+	}
+	if shortLag == 0 || shortLag >= longLag {
+		return fmt.Errorf("the short lag %d must be greater than zero and less than the long lag %d: %w", shortLag, longLag, ErrInvalidConfig)
+	}
+	d.threshold = threshold
+	d.long.initialize(initialValues)
+	d.short.initialize(initialValues[longLag-shortLag:])
+	return nil
+}
+
+func (d *dualTimescaleDetector) Next(value float64) Signal {
+	longMean, longStdDev := d.long.next(value)
+	shortMean, _ := d.short.next(value)
+
+	deviation := shortMean - longMean
+	if math.Abs(deviation) > d.threshold*longStdDev {
+		if deviation > 0 {
+			return SignalPositive
+		}
+		return SignalNegative
+	}
+	return SignalNeutral
+}
+
+func (d *dualTimescaleDetector) NextBatch(values []float64) []Signal {
+	signals := make([]Signal, len(values))
+	for i, v := range values {
+		signals[i] = d.Next(v)
+	}
+	return signals
+}
@@ -0,0 +1,53 @@
+package peakdetect_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/MicahParks/peakdetect"
+)
+
+func TestDualTimescaleDetector_Initialize(t *testing.T) {
+	detector := peakdetect.NewDualTimescaleDetector()
+	err := detector.Initialize(1, 1, nil)
+	if !errors.Is(err, peakdetect.ErrInvalidInitialValues) {
+		t.Fatalf("Invalid initilization did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidInitialValues, err)
+	}
+	err = detector.Initialize(3, 1, []float64{1, 2, 3})
+	if !errors.Is(err, peakdetect.ErrInvalidConfig) {
+		t.Fatalf("Invalid short lag did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidConfig, err)
+	}
+}
+
+func TestDualTimescaleDetector_NextBatch(t *testing.T) {
+	noise := []float64{0.3, -0.3, 0.1, -0.1, 0.25, -0.25}
+	var data []float64
+	for i := 0; i < 150; i++ {
+		v := 1 + noise[i%len(noise)]
+		if i == 60 {
+			// A single noisy value should not be a signal.
+			v = 2
+		}
+		if i >= 100 {
+			v += 1
+		}
+		data = append(data, v)
+	}
+
+	detector := peakdetect.NewDualTimescaleDetector()
+	err := detector.Initialize(5, 1.5, data[:50])
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+
+	signals := detector.NextBatch(data[50:])
+	for i, signal := range signals {
+		index := i + 50
+		if index < 100 && signal != peakdetect.SignalNeutral {
+			t.Fatalf("Unexpected signal before the shift at index %d.\n  Actual: %d", index, signal)
+		}
+		if index >= 104 && index < 110 && signal != peakdetect.SignalPositive {
+			t.Fatalf("Shift was not detected at index %d.\n  Actual: %d", index, signal)
+		}
+	}
+}
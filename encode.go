@@ -0,0 +1,74 @@
+package peakdetect
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// SignalEncoder writes SignalEvents to an io.Writer as newline-delimited JSON, one record per event, as the events are
+// produced. It is suitable for piping detection output into log collectors.
+type SignalEncoder struct {
+	enc           *json.Encoder
+	flushOnSignal bool
+	w             io.Writer
+}
+
+// signalRecord is the JSON representation of a SignalEvent.
+type signalRecord struct {
+	Index  uint64            `json:"index"`
+	Labels map[string]string `json:"labels,omitempty"`
+	Signal Signal            `json:"signal"`
+	Time   *time.Time        `json:"time,omitempty"`
+	Value  float64           `json:"value"`
+}
+
+// EncodeTo creates a SignalEncoder that writes to w. If flushOnSignal is true and w has a Flush method, such as a
+// *bufio.Writer or an http.Flusher, it is flushed after every event with a non-neutral signal.
+func EncodeTo(w io.Writer, flushOnSignal bool) *SignalEncoder {
+	return &SignalEncoder{
+		enc:           json.NewEncoder(w),
+		flushOnSignal: flushOnSignal,
+		w:             w,
+	}
+}
+
+// Encode writes the event as a single line of JSON. The labels are omitted if there are none and the timestamp is
+// omitted if it is the zero value. Values that are NaN or infinite cannot be represented in JSON and produce an error.
+func (s *SignalEncoder) Encode(event SignalEvent) error {
+	record := signalRecord{
+		Index:  event.Index,
+		Labels: event.Labels,
+		Signal: event.Signal,
+		Value:  event.Value,
+	}
+	if !event.Time.IsZero() {
+		record.Time = &event.Time
+	}
+	err := s.enc.Encode(record)
+	if err != nil {
+		return err
+	}
+
+	if s.flushOnSignal && event.Signal != SignalNeutral {
+		switch f := s.w.(type) {
+		case interface{ Flush() error }:
+			return f.Flush()
+		case interface{ Flush() }:
+			f.Flush()
+		}
+	}
+	return nil
+}
+
+// EncodeAll writes every event received from the channel until it is closed, such as the channel returned by Consume.
+// If an error occurs, it is returned without draining the rest of the channel.
+func (s *SignalEncoder) EncodeAll(events <-chan SignalEvent) error {
+	for event := range events {
+		err := s.Encode(event)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package peakdetect_test
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MicahParks/peakdetect"
+)
+
+func TestSignalEncoder_Encode(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := bufio.NewWriter(buf)
+	encoder := peakdetect.EncodeTo(w, true)
+
+	err := encoder.Encode(peakdetect.SignalEvent{Index: 0, Signal: peakdetect.SignalNeutral, Value: 1})
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to encode event.", err)
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("Neutral signal should not flush the writer.\n  Actual: %q", buf.String())
+	}
+
+	err = encoder.Encode(peakdetect.SignalEvent{Index: 1, Labels: map[string]string{"series": "a"}, Signal: peakdetect.SignalPositive, Time: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), Value: 5.5})
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to encode event.", err)
+	}
+
+	expected := `{"index":0,"signal":0,"value":1}
+{"index":1,"labels":{"series":"a"},"signal":1,"time":"2021-01-01T00:00:00Z","value":5.5}
+`
+	if buf.String() != expected {
+		t.Fatalf("Encoded output did not match.\n  Expected: %q\n  Actual: %q", expected, buf.String())
+	}
+}
+
+func TestSignalEncoder_EncodeAll(t *testing.T) {
+	detector := peakdetect.NewPeakDetector()
+	err := detector.Initialize(exampleInfluence, exampleThreshold, exampleInputs[0:exampleLag])
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+
+	in := make(chan float64)
+	go func() {
+		defer close(in)
+		for _, v := range exampleInputs[exampleLag:] {
+			in <- v
+		}
+	}()
+	events := peakdetect.Consume(detector, in, func(v float64) (float64, time.Time, bool) {
+		return v, time.Time{}, true
+	})
+
+	buf := &bytes.Buffer{}
+	err = peakdetect.EncodeTo(buf, false).EncodeAll(events)
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to encode events.", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != len(exampleInputs)-exampleLag {
+		t.Fatalf("Unexpected number of records.\n  Expected: %d\n  Actual: %d", len(exampleInputs)-exampleLag, len(lines))
+	}
+}
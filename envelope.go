@@ -0,0 +1,102 @@
+package peakdetect
+
+import (
+	"fmt"
+)
+
+// EnvelopeDetector detects values that escape the upper and lower envelopes of the data. Each envelope holds the most
+// extreme value it has seen and decays toward new values, so the envelopes follow oscillating data far better than a
+// moving mean and standard deviation do.
+type EnvelopeDetector interface {
+	// Initialize initializes the EnvelopeDetector with its configuration and initialValues. The envelopes start at the
+	// minimum and maximum of the initialValues. The EnvelopeDetector will never return any signals for the
+	// initialValues.
+	//
+	// decay is the fraction of the distance to the current value that each envelope moves after every value that does
+	// not push it outward. It must be in the range [0, 1]. At 0, the envelopes hold the extremes forever. At 1, they
+	// collapse onto every value.
+	//
+	// margin is how far a value must be beyond an envelope to be a signal, as a fraction of the distance between the
+	// envelopes. It must not be negative.
+	Initialize(decay, margin float64, initialValues []float64) error
+	// Next processes the next value and determines its signal.
+	Next(value float64) Signal
+	// NextBatch processes the next values and determines their signals. Their signals will be returned in a slice equal
+	// to the length of the input.
+	NextBatch(values []float64) []Signal
+	// Envelope returns the current lower and upper envelopes.
+	Envelope() (lower, upper float64)
+}
+
+type envelopeDetector struct {
+	decay  float64
+	lower  float64
+	margin float64
+	upper  float64
+}
+
+// NewEnvelopeDetector creates a new EnvelopeDetector. It must be initialized before use.
+func NewEnvelopeDetector() EnvelopeDetector {
+	return &envelopeDetector{}
+}
+
+func (e *envelopeDetector) Initialize(decay, margin float64, initialValues []float64) error {
+	if len(initialValues) == 0 {
+		return fmt.Errorf("at least one initial value is required to start the envelopes: %w", ErrInvalidInitialValues)
+	}
+	if !(decay >= 0 && decay <= 1) {
+		return fmt.Errorf("the decay %f must be in the range [0, 1]: %w", decay, ErrInvalidConfig)
+	}
+	if !(margin >= 0) {
+		return fmt.Errorf("the margin %f must not be negative: %w", margin, ErrInvalidConfig)
+	}
+	e.decay = decay
+	e.margin = margin
+	e.lower, e.upper = initialValues[0], initialValues[0]
+	for _, v := range initialValues[1:] {
+		if v < e.lower {
+			e.lower = v
+		}
+		if v > e.upper {
+			e.upper = v
+		}
+	}
+	return nil
+}
+
+func (e *envelopeDetector) Next(value float64) (signal Signal) {
+	width := e.margin * (e.upper - e.lower)
+	switch {
+	case value > e.upper+width:
+		signal = SignalPositive
+	case value < e.lower-width:
+		signal = SignalNegative
+	default:
+		signal = SignalNeutral
+	}
+
+	if value > e.upper {
+		e.upper = value
+	} else {
+		e.upper -= e.decay * (e.upper - value)
+	}
+	if value < e.lower {
+		e.lower = value
+	} else {
+		e.lower += e.decay * (value - e.lower)
+	}
+
+	return signal
+}
+
+func (e *envelopeDetector) NextBatch(values []float64) []Signal {
+	signals := make([]Signal, len(values))
+	for i, v := range values {
+		signals[i] = e.Next(v)
+	}
+	return signals
+}
+
+func (e *envelopeDetector) Envelope() (lower, upper float64) {
+	return e.lower, e.upper
+}
@@ -0,0 +1,60 @@
+package peakdetect_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/MicahParks/peakdetect"
+)
+
+func TestEnvelopeDetector_Initialize(t *testing.T) {
+	detector := peakdetect.NewEnvelopeDetector()
+	err := detector.Initialize(0.1, 0.1, nil)
+	if !errors.Is(err, peakdetect.ErrInvalidInitialValues) {
+		t.Fatalf("Invalid initilization did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidInitialValues, err)
+	}
+	err = detector.Initialize(2, 0.1, []float64{1})
+	if !errors.Is(err, peakdetect.ErrInvalidConfig) {
+		t.Fatalf("Invalid decay did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidConfig, err)
+	}
+	err = detector.Initialize(0.1, -1, []float64{1})
+	if !errors.Is(err, peakdetect.ErrInvalidConfig) {
+		t.Fatalf("Invalid margin did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidConfig, err)
+	}
+}
+
+func TestEnvelopeDetector_NextBatch(t *testing.T) {
+	var data []float64
+	for i := 0; i < 200; i++ {
+		data = append(data, math.Sin(float64(i)*2*math.Pi/20))
+	}
+	data[150] = 3
+	data[175] = -3
+
+	detector := peakdetect.NewEnvelopeDetector()
+	err := detector.Initialize(0.01, 0.2, data[:20])
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+
+	signals := detector.NextBatch(data[20:])
+	for i, signal := range signals {
+		index := i + 20
+		expected := peakdetect.SignalNeutral
+		switch index {
+		case 150:
+			expected = peakdetect.SignalPositive
+		case 175:
+			expected = peakdetect.SignalNegative
+		}
+		if signal != expected {
+			t.Fatalf("Unexpected signal at index %d.\n  Expected: %d\n  Actual: %d", index, expected, signal)
+		}
+	}
+
+	lower, upper := detector.Envelope()
+	if lower >= -1 || upper <= 1 {
+		t.Fatalf("Envelopes should hold the spikes.\n  Lower: %f\n  Upper: %f", lower, upper)
+	}
+}
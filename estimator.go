@@ -0,0 +1,77 @@
+package peakdetect
+
+// BaselineEstimator estimates the center and spread of the values in the lag window in place of the moving mean and
+// standard deviation, such as with a trimmed mean or a Kalman filter. The spread is on the scale of a standard
+// deviation, so a value signals when it is more than the threshold multiplied by the spread from the center. See
+// Config.Baseline.
+type BaselineEstimator interface {
+	// Initialize replaces the state of the BaselineEstimator with the initial values, which are the lag window in
+	// chronological order, and returns their center and spread.
+	Initialize(initialValues []float64) (center, spread float64)
+	// Next adds the value to the lag window and returns the new center and spread. The removed value is the oldest value
+	// in the window, which the value replaces. The value is influence adjusted if it signaled, like every value stored
+	// in the lag window.
+	Next(value, removed float64) (center, spread float64)
+	// Clone returns a deep copy of the BaselineEstimator.
+	Clone() BaselineEstimator
+}
+
+// customBaseline adapts a BaselineEstimator from Config.Baseline to a baseline, keeping the lag window for it.
+type customBaseline struct {
+	cache     []float64
+	estimator BaselineEstimator
+	index     int
+	// replaced is the baseline of the PeakDetector before Config.Baseline was used, which is restored if a later
+	// initialization does not use one.
+	replaced baseline
+}
+
+// useBaseline makes the BaselineEstimator the baseline of the PeakDetector, or restores the replaced baseline if it is
+// nil.
+func (p *peakDetector) useBaseline(estimator BaselineEstimator) {
+	if custom, ok := p.baseline.(*customBaseline); ok {
+		p.baseline = custom.replaced
+	}
+	if estimator != nil {
+		p.baseline = &customBaseline{
+			estimator: estimator.Clone(),
+			replaced:  p.baseline,
+		}
+	}
+}
+
+func (c *customBaseline) initialize(initialValues []float64) (center, spread float64) {
+	c.cache = append(make([]float64, 0, len(initialValues)), initialValues...)
+	c.index = 0
+	return c.estimator.Initialize(append([]float64(nil), initialValues...))
+}
+
+func (c *customBaseline) next(value float64) (center, spread float64) {
+	removed := c.cache[c.index]
+	c.cache[c.index] = value
+	c.index++
+	if c.index == len(c.cache) {
+		c.index = 0
+	}
+	return c.estimator.Next(value, removed)
+}
+
+func (c *customBaseline) window() []float64 {
+	window := make([]float64, 0, len(c.cache))
+	window = append(window, c.cache[c.index:]...)
+	return append(window, c.cache[:c.index]...)
+}
+
+func (c *customBaseline) clone() baseline {
+	return &customBaseline{
+		cache:     append([]float64(nil), c.cache...),
+		estimator: c.estimator.Clone(),
+		index:     c.index,
+		replaced:  c.replaced.clone(),
+	}
+}
+
+func (c *customBaseline) reset() {
+	c.cache = nil
+	c.index = 0
+}
@@ -0,0 +1,183 @@
+package peakdetect_test
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/MicahParks/peakdetect"
+)
+
+// sumEstimator is the mean and population standard deviation from running sums, which is the default baseline computed
+// a different way.
+type sumEstimator struct {
+	n     float64
+	sum   float64
+	sumSq float64
+}
+
+func (s *sumEstimator) Initialize(initialValues []float64) (center, spread float64) {
+	*s = sumEstimator{
+		n: float64(len(initialValues)),
+	}
+	for _, v := range initialValues {
+		s.sum += v
+		s.sumSq += v * v
+	}
+	return s.stats()
+}
+
+func (s *sumEstimator) Next(value, removed float64) (center, spread float64) {
+	s.sum += value - removed
+	s.sumSq += value*value - removed*removed
+	return s.stats()
+}
+
+func (s *sumEstimator) Clone() peakdetect.BaselineEstimator {
+	clone := *s
+	return &clone
+}
+
+func (s *sumEstimator) stats() (center, spread float64) {
+	mean := s.sum / s.n
+	return mean, math.Sqrt(math.Max(0, s.sumSq/s.n-mean*mean))
+}
+
+// trimmedMeanEstimator is the mean of the window without its least and greatest values, with the standard deviation of
+// the same values.
+type trimmedMeanEstimator struct {
+	sorted []float64
+}
+
+func (t *trimmedMeanEstimator) Initialize(initialValues []float64) (center, spread float64) {
+	t.sorted = initialValues
+	sort.Float64s(t.sorted)
+	return t.stats()
+}
+
+func (t *trimmedMeanEstimator) Next(value, removed float64) (center, spread float64) {
+	i := sort.SearchFloat64s(t.sorted, removed)
+	t.sorted[i] = value
+	sort.Float64s(t.sorted)
+	return t.stats()
+}
+
+func (t *trimmedMeanEstimator) Clone() peakdetect.BaselineEstimator {
+	return &trimmedMeanEstimator{
+		sorted: append([]float64(nil), t.sorted...),
+	}
+}
+
+func (t *trimmedMeanEstimator) stats() (center, spread float64) {
+	return referenceMeanStdDev(t.sorted[1 : len(t.sorted)-1])
+}
+
+func TestBaselineEstimator(t *testing.T) {
+	expected, _, err := peakdetect.Detect(exampleInputs, peakdetect.Config{
+		Influence: exampleInfluence,
+		Lag:       exampleLag,
+		Threshold: exampleThreshold,
+	})
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to detect.", err)
+	}
+
+	estimator := &sumEstimator{}
+	actual, _, err := peakdetect.Detect(exampleInputs, peakdetect.Config{
+		Baseline:  estimator,
+		Influence: exampleInfluence,
+		Lag:       exampleLag,
+		Threshold: exampleThreshold,
+	})
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to detect with a custom baseline.", err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("A custom mean and standard deviation should match the default baseline.\n  Expected: %v\n  Actual: %v", expected, actual)
+	}
+	if *estimator != (sumEstimator{}) {
+		t.Fatalf("The BaselineEstimator of the Config should not be used directly.\n  Actual: %+v", *estimator)
+	}
+}
+
+func TestBaselineEstimator_TrimmedMean(t *testing.T) {
+	detector, err := peakdetect.NewFromConfig(peakdetect.Config{
+		Baseline:  &trimmedMeanEstimator{},
+		Influence: 0.5,
+		Threshold: exampleThreshold,
+	}, exampleInputs[:exampleLag])
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+
+	for i, v := range exampleInputs[exampleLag:] {
+		window := detector.Window()
+		sort.Float64s(window)
+		expectedMean, expectedStdDev := referenceMeanStdDev(window[1 : len(window)-1])
+
+		detector.Next(v)
+		explanation := detector.Explain()
+		if math.Abs(explanation.Mean-expectedMean) > 1e-12 || math.Abs(explanation.StdDev-expectedStdDev) > 1e-12 {
+			t.Fatalf("Incorrect trimmed mean or standard deviation at index %d.\n  Expected: %f, %f\n  Actual: %f, %f", exampleLag+i, expectedMean, expectedStdDev, explanation.Mean, explanation.StdDev)
+		}
+	}
+}
+
+func TestBaselineEstimator_Lifecycle(t *testing.T) {
+	const split = exampleLag + 20
+	cfg := peakdetect.Config{
+		Baseline:  &trimmedMeanEstimator{},
+		Influence: 0.5,
+		Threshold: exampleThreshold,
+	}
+	detector := peakdetect.NewRobustPeakDetector()
+	err := detector.InitializeWithConfig(cfg, exampleInputs[:exampleLag])
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+	detector.NextBatch(exampleInputs[exampleLag:split])
+
+	_, err = detector.MarshalBinary()
+	if !errors.Is(err, peakdetect.ErrInvalidConfig) {
+		t.Fatalf("A detector with a custom baseline should not be encoded.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidConfig, err)
+	}
+	_, err = detector.MarshalJSON()
+	if !errors.Is(err, peakdetect.ErrInvalidConfig) {
+		t.Fatalf("A detector with a custom baseline should not be encoded.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidConfig, err)
+	}
+
+	evaluated, err := detector.Evaluate(peakdetect.Config{Lag: 10, Influence: 0.5, Threshold: exampleThreshold})
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to evaluate.", err)
+	}
+	fresh, err := peakdetect.NewFromConfig(cfg, detector.Window()[:10])
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+	if expected := fresh.NextBatch(detector.Window()[10:]); !reflect.DeepEqual(evaluated, expected) {
+		t.Fatalf("Evaluate should use the custom baseline.\n  Expected: %v\n  Actual: %v", expected, evaluated)
+	}
+
+	clone := detector.Clone()
+	expected := detector.NextBatch(exampleInputs[split:])
+	if actual := clone.NextBatch(exampleInputs[split:]); !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("Clone signals do not match.\n  Expected: %v\n  Actual: %v", expected, actual)
+	}
+
+	// Without a Baseline, the detector returns to its own, which is robust.
+	err = detector.Initialize(exampleInfluence, exampleThreshold, exampleInputs[:exampleLag])
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+	_, err = detector.MarshalBinary()
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to encode the detector without a custom baseline.", err)
+	}
+	detector.Next(1)
+	median, _ := referenceMedianMAD(exampleInputs[:exampleLag])
+	if mean := detector.Explain().Mean; mean != median {
+		t.Fatalf("The detector should be robust again.\n  Expected: %f\n  Actual: %f", median, mean)
+	}
+}
@@ -0,0 +1,182 @@
+package peakdetect
+
+import (
+	"math"
+)
+
+const (
+	// ConditionCoefficientOfVariation is the name of the Condition that the coefficient of variation of the lag window
+	// is not below Config.MinCoefficientOfVariation. It is only checked when the option is enabled.
+	ConditionCoefficientOfVariation = "coefficient of variation above floor"
+	// ConditionExceedsDeadband is the name of the Condition that the absolute deviation of the value from the moving mean
+	// is greater than Config.Deadband. It is only checked when the option is enabled.
+	ConditionExceedsDeadband = "exceeds deadband"
+	// ConditionExceedsThreshold is the name of the Condition that the absolute deviation of the value from the moving
+	// mean is greater than the threshold multiplied by the moving standard deviation. With Config.Percentile, it is that
+	// the value is beyond the percentile.
+	ConditionExceedsThreshold = "exceeds threshold"
+	// ConditionAboveMean is the name of the Condition that the value is greater than the moving mean. It determines the
+	// direction of a signal. With Config.Percentile, it is that the value is greater than the median of the lag window.
+	ConditionAboveMean = "above mean"
+	// ConditionPersisted is the name of the Condition that the value exceeded the threshold and enough of the recent
+	// values did too on the same side of the moving mean. It is only checked when Config.Persistence is enabled.
+	ConditionPersisted = "persisted"
+	// ConditionDirection is the name of the Condition that the side of the moving mean the value is on is allowed by
+	// Config.Direction. It is only checked when the option is not DirectionBoth.
+	ConditionDirection = "direction allowed"
+	// ConditionOutsideRefractoryPeriod is the name of the Condition that the value is not within Config.RefractoryPeriod
+	// values of the previous signal. It is only checked when the option is enabled.
+	ConditionOutsideRefractoryPeriod = "outside refractory period"
+)
+
+// Condition is a single check made while determining the signal for a value.
+type Condition struct {
+	Name   string
+	Passed bool
+}
+
+// Explanation describes how the signal for a value was determined. It answers the question "why did this signal
+// fire?" using the same window statistics the PeakDetector used.
+type Explanation struct {
+	// Conditions are the checks made for the value, in the order they were made.
+	Conditions []Condition
+	// Influence is the influence for the side of the moving mean the value is on.
+	Influence float64
+	// Labels are the labels attached to the PeakDetector.
+	Labels map[string]string
+	// LowVariance is true if signaling was suppressed because the coefficient of variation of the lag window was below
+	// Config.MinCoefficientOfVariation.
+	LowVariance bool
+	// Mean is the moving mean of the window before the value was processed.
+	Mean float64
+	// Missing is true if the value was missing, NaN, or ±Inf, so it was handled according to Config.Missing.
+	Missing bool
+	// Refractory is true if the value was within Config.RefractoryPeriod values of the previous signal, so any signal
+	// for it was suppressed.
+	Refractory bool
+	// Signal is the signal that was determined for the value.
+	Signal Signal
+	// Strength is the exceedance ratio of the value. See Result.Strength.
+	Strength float64
+	// StdDev is the moving population standard deviation of the window before the value was processed. It includes the
+	// floors from Config.MinStdDev and Config.QuantizationStep.
+	StdDev float64
+	// Stored is the value that was stored in the window. It differs from Value when a signal is influence adjusted.
+	Stored float64
+	// Threshold is the threshold that applied to the value. It is Config.ReleaseThreshold while a signal is sustained by
+	// hysteresis, and Config.Threshold otherwise. With Config.Percentile, it is the distance from the moving mean to the
+	// percentile in standard deviations.
+	Threshold float64
+	// Value is the value that was processed.
+	Value float64
+	// ZScore is the number of standard deviations the value is from the moving mean.
+	ZScore float64
+}
+
+// lastValue holds what is needed to explain the most recently processed value. The center is what the deviation of the
+// value is measured from to determine its side and if it exceeds the bound. It is the moving mean, or the median of the
+// lag window for Config.Percentile.
+type lastValue struct {
+	backfilled  []uint
+	bound       float64
+	center      float64
+	lowVariance bool
+	mean        float64
+	missing     bool
+	persisted   bool
+	processed   bool
+	refractory  bool
+	signal      Signal
+	stdDev      float64
+	threshold   float64
+	value       float64
+}
+
+func (p *peakDetector) Explain() Explanation {
+	if !p.last.processed {
+		return Explanation{}
+	}
+	deviation := p.last.value - p.last.center
+	side := SignalNegative
+	if deviation > 0 {
+		side = SignalPositive
+	}
+
+	var conditions []Condition
+	if p.config.MinCoefficientOfVariation > 0 {
+		conditions = append(conditions, Condition{
+			Name:   ConditionCoefficientOfVariation,
+			Passed: !p.last.lowVariance,
+		})
+	}
+	if p.config.Deadband > 0 {
+		conditions = append(conditions, Condition{
+			Name:   ConditionExceedsDeadband,
+			Passed: math.Abs(p.last.value-p.last.mean) > p.config.Deadband,
+		})
+	}
+	conditions = append(conditions,
+		Condition{
+			Name:   ConditionExceedsThreshold,
+			Passed: math.Abs(deviation) > p.last.bound,
+		},
+		Condition{
+			Name:   ConditionAboveMean,
+			Passed: deviation > 0,
+		},
+	)
+	if p.config.Persistence > 1 {
+		conditions = append(conditions, Condition{
+			Name:   ConditionPersisted,
+			Passed: p.last.persisted,
+		})
+	}
+	if p.config.Direction != DirectionBoth {
+		conditions = append(conditions, Condition{
+			Name:   ConditionDirection,
+			Passed: p.config.Direction.allows(side),
+		})
+	}
+	if p.config.RefractoryPeriod > 0 {
+		conditions = append(conditions, Condition{
+			Name:   ConditionOutsideRefractoryPeriod,
+			Passed: !p.last.refractory,
+		})
+	}
+
+	_, influence := p.config.sided(side)
+
+	return Explanation{
+		Conditions:  conditions,
+		Influence:   influence,
+		Labels:      p.labels,
+		LowVariance: p.last.lowVariance,
+		Mean:        p.last.mean,
+		Missing:     p.last.missing,
+		Refractory:  p.last.refractory,
+		Signal:      p.last.signal,
+		Strength:    p.last.strength(),
+		StdDev:      p.last.stdDev,
+		Stored:      p.prevValue,
+		Threshold:   p.last.threshold,
+		Value:       p.last.value,
+		ZScore:      zScore(p.last.value-p.last.mean, p.last.stdDev),
+	}
+}
+
+// strength returns the exceedance ratio of the value for the threshold that applied to it.
+func (l lastValue) strength() float64 {
+	return zScore(math.Abs(l.value-l.center), l.bound)
+}
+
+// zScore divides the deviation by the standard deviation. A standard deviation of zero produces an infinite z-score
+// for any nonzero deviation instead of NaN.
+func zScore(deviation, stdDev float64) float64 {
+	if stdDev == 0 {
+		if deviation == 0 {
+			return 0
+		}
+		return math.Copysign(math.Inf(1), deviation)
+	}
+	return deviation / stdDev
+}
@@ -0,0 +1,212 @@
+package peakdetect
+
+import (
+	"fmt"
+	"math"
+)
+
+// ExtremaConfig is the configuration for FindExtrema and an ExtremaDetector. The filters are disabled by their zero
+// value, except MinHeight, which is disabled when it is nil.
+type ExtremaConfig struct {
+	// MinHeight is the least value of a peak. For valleys, it applies to the negated value, so a MinHeight of 2 keeps
+	// valleys at or below -2. It is a pointer because a height of zero is common.
+	MinHeight *float64
+	// MinProminence is the least prominence of an extremum. The prominence of a peak is how far it stands out from the
+	// surrounding baseline, which is the height of the peak above the higher of the lowest values on either side before
+	// a higher value is reached. It is what usually separates "the big peaks" from the noise.
+	MinProminence float64
+	// MinWidth is the least width of an extremum, in values, which is measured at half of its prominence. The edges of
+	// the width are linearly interpolated.
+	MinWidth float64
+	// Valleys also finds local minima, which are reported with SignalNegative.
+	Valleys bool
+	// Window limits the search for the prominence of an extremum to this many values on each side of it, like the wlen
+	// of SciPy. Without it, the search can span the whole series. It is required for an ExtremaDetector, where it is also
+	// the delay before an extremum is reported.
+	Window uint
+}
+
+// Extremum is a local maximum or minimum of a series.
+type Extremum struct {
+	// Index is the index of the extremum. For a flat extremum, it is the middle of the flat values, rounded down.
+	Index uint64
+	// LeftBase is the index of the lowest value on the left that determined the prominence. For a valley, it is the
+	// highest value.
+	LeftBase uint64
+	// Prominence is the prominence of the extremum. See ExtremaConfig.MinProminence.
+	Prominence float64
+	// RightBase is the index of the lowest value on the right that determined the prominence. For a valley, it is the
+	// highest value.
+	RightBase uint64
+	// Signal is SignalPositive for a peak and SignalNegative for a valley.
+	Signal Signal
+	// Value is the value of the extremum.
+	Value float64
+	// Width is the width of the extremum at half of its prominence, in values.
+	Width float64
+}
+
+// FindExtrema finds the local maxima of a complete series, and its local minima if cfg.Valleys is true, that pass the
+// filters of the cfg, like SciPy's find_peaks. A local maximum is a value, or a run of equal values, that is greater
+// than its neighbors on both sides, so the first and last values are never extrema. The extrema are in the order of
+// their indices.
+func FindExtrema(values []float64, cfg ExtremaConfig) []Extremum {
+	var extrema []Extremum
+	for i := 1; i < len(values)-1; i++ {
+		if values[i] == values[i-1] {
+			continue
+		}
+		extremum, ok := extremumAt(values, i, cfg)
+		if ok {
+			extrema = append(extrema, extremum)
+		}
+	}
+	return extrema
+}
+
+// ExtremaDetector finds local extrema in a stream. It keeps the most recent 2*ExtremaConfig.Window+1 values and reports
+// an extremum once Window values after it are known, so the results match FindExtrema with the same Window away from
+// the ends of the series. Flat extrema longer than the Window are not found.
+type ExtremaDetector struct {
+	cfg    ExtremaConfig
+	count  uint64
+	values []float64
+}
+
+// NewExtremaDetector creates a new ExtremaDetector. cfg.Window must be greater than zero.
+func NewExtremaDetector(cfg ExtremaConfig) (*ExtremaDetector, error) {
+	if cfg.Window == 0 {
+		return nil, fmt.Errorf("the window for an extrema detector must be greater than zero: %w", ErrInvalidConfig)
+	}
+	return &ExtremaDetector{
+		cfg:    cfg,
+		values: make([]float64, 0, 2*cfg.Window+1),
+	}, nil
+}
+
+// Next processes the next value. If it completes the window after an extremum that passes the filters, the extremum is
+// returned and ok is true. Indices count every value given to the ExtremaDetector, starting at zero.
+func (e *ExtremaDetector) Next(value float64) (extremum Extremum, ok bool) {
+	if len(e.values) == cap(e.values) {
+		copy(e.values, e.values[1:])
+		e.values = e.values[:len(e.values)-1]
+	}
+	e.values = append(e.values, value)
+	e.count++
+	if len(e.values) < cap(e.values) {
+		return Extremum{}, false
+	}
+
+	center := int(e.cfg.Window)
+	if e.values[center] == e.values[center-1] {
+		return Extremum{}, false
+	}
+	extremum, ok = extremumAt(e.values, center, e.cfg)
+	if !ok {
+		return Extremum{}, false
+	}
+	offset := e.count - uint64(len(e.values))
+	extremum.Index += offset
+	extremum.LeftBase += offset
+	extremum.RightBase += offset
+	return extremum, true
+}
+
+// NextBatch processes the next values and returns the extrema they complete.
+func (e *ExtremaDetector) NextBatch(values []float64) []Extremum {
+	var extrema []Extremum
+	for _, v := range values {
+		extremum, ok := e.Next(v)
+		if ok {
+			extrema = append(extrema, extremum)
+		}
+	}
+	return extrema
+}
+
+// extremumAt determines if the run of equal values starting at the index is an extremum that passes the filters.
+func extremumAt(values []float64, start int, cfg ExtremaConfig) (Extremum, bool) {
+	end := start
+	for end+1 < len(values) && values[end+1] == values[start] {
+		end++
+	}
+	if start == 0 || end == len(values)-1 {
+		return Extremum{}, false
+	}
+
+	var sign float64
+	switch {
+	case values[start-1] < values[start] && values[end+1] < values[start]:
+		sign = 1
+	case cfg.Valleys && values[start-1] > values[start] && values[end+1] > values[start]:
+		sign = -1
+	default:
+		return Extremum{}, false
+	}
+	index := (start + end) / 2
+	peak := sign * values[index]
+	if cfg.MinHeight != nil && peak < *cfg.MinHeight {
+		return Extremum{}, false
+	}
+
+	// The prominence is measured against the lowest value on each side before a higher value, within the window.
+	lowest, highest := 0, len(values)-1
+	if cfg.Window != 0 {
+		lowest = max(lowest, index-int(cfg.Window))
+		highest = min(highest, index+int(cfg.Window))
+	}
+	leftBase := index
+	for i := index - 1; i >= lowest && sign*values[i] <= peak; i-- {
+		if sign*values[i] < sign*values[leftBase] {
+			leftBase = i
+		}
+	}
+	rightBase := index
+	for i := index + 1; i <= highest && sign*values[i] <= peak; i++ {
+		if sign*values[i] < sign*values[rightBase] {
+			rightBase = i
+		}
+	}
+	base := math.Max(sign*values[leftBase], sign*values[rightBase])
+	prominence := peak - base
+	if prominence < cfg.MinProminence {
+		return Extremum{}, false
+	}
+
+	// The width is measured at half of the prominence, interpolating between the values on either side of it.
+	height := peak - prominence/2
+	left := float64(index)
+	for i := index; i > leftBase; i-- {
+		if sign*values[i-1] < height {
+			left = float64(i-1) + (height-sign*values[i-1])/(sign*values[i]-sign*values[i-1])
+			break
+		}
+		left = float64(i - 1)
+	}
+	right := float64(index)
+	for i := index; i < rightBase; i++ {
+		if sign*values[i+1] < height {
+			right = float64(i+1) - (height-sign*values[i+1])/(sign*values[i]-sign*values[i+1])
+			break
+		}
+		right = float64(i + 1)
+	}
+	width := right - left
+	if width < cfg.MinWidth {
+		return Extremum{}, false
+	}
+
+	signal := SignalPositive
+	if sign < 0 {
+		signal = SignalNegative
+	}
+	return Extremum{
+		Index:      uint64(index),
+		LeftBase:   uint64(leftBase),
+		Prominence: prominence,
+		RightBase:  uint64(rightBase),
+		Signal:     signal,
+		Value:      values[index],
+		Width:      width,
+	}, true
+}
@@ -0,0 +1,127 @@
+package peakdetect_test
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/MicahParks/peakdetect"
+)
+
+var extremaValues = []float64{0, 2, 1, 5, 1, 1, 3, 3, 3, 0, -4, 0}
+
+func TestFindExtrema(t *testing.T) {
+	height := 4.0
+	tests := map[string]struct {
+		cfg      peakdetect.ExtremaConfig
+		expected []peakdetect.Extremum
+	}{
+		"Peaks": {
+			cfg: peakdetect.ExtremaConfig{},
+			expected: []peakdetect.Extremum{
+				{Index: 1, LeftBase: 0, Prominence: 1, RightBase: 2, Signal: peakdetect.SignalPositive, Value: 2, Width: 0.75},
+				{Index: 3, LeftBase: 0, Prominence: 5, RightBase: 10, Signal: peakdetect.SignalPositive, Value: 5, Width: 1.25},
+				{Index: 7, LeftBase: 5, Prominence: 2, RightBase: 10, Signal: peakdetect.SignalPositive, Value: 3, Width: 17.0 / 6},
+			},
+		},
+		"MinProminence": {
+			cfg: peakdetect.ExtremaConfig{MinProminence: 1.5},
+			expected: []peakdetect.Extremum{
+				{Index: 3, LeftBase: 0, Prominence: 5, RightBase: 10, Signal: peakdetect.SignalPositive, Value: 5, Width: 1.25},
+				{Index: 7, LeftBase: 5, Prominence: 2, RightBase: 10, Signal: peakdetect.SignalPositive, Value: 3, Width: 17.0 / 6},
+			},
+		},
+		"MinWidth": {
+			cfg: peakdetect.ExtremaConfig{MinWidth: 2},
+			expected: []peakdetect.Extremum{
+				{Index: 7, LeftBase: 5, Prominence: 2, RightBase: 10, Signal: peakdetect.SignalPositive, Value: 3, Width: 17.0 / 6},
+			},
+		},
+		"MinHeight": {
+			cfg: peakdetect.ExtremaConfig{MinHeight: &height},
+			expected: []peakdetect.Extremum{
+				{Index: 3, LeftBase: 0, Prominence: 5, RightBase: 10, Signal: peakdetect.SignalPositive, Value: 5, Width: 1.25},
+			},
+		},
+		"Valleys": {
+			cfg: peakdetect.ExtremaConfig{MinProminence: 2, Valleys: true},
+			expected: []peakdetect.Extremum{
+				{Index: 3, LeftBase: 0, Prominence: 5, RightBase: 10, Signal: peakdetect.SignalPositive, Value: 5, Width: 1.25},
+				{Index: 4, LeftBase: 3, Prominence: 2, RightBase: 6, Signal: peakdetect.SignalNegative, Value: 1, Width: 1.75},
+				{Index: 7, LeftBase: 5, Prominence: 2, RightBase: 10, Signal: peakdetect.SignalPositive, Value: 3, Width: 17.0 / 6},
+				{Index: 10, LeftBase: 3, Prominence: 4, RightBase: 11, Signal: peakdetect.SignalNegative, Value: -4, Width: 1},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual := peakdetect.FindExtrema(extremaValues, tc.cfg)
+			if len(actual) != len(tc.expected) {
+				t.Fatalf("Incorrect number of extrema.\n  Expected: %+v\n  Actual: %+v", tc.expected, actual)
+			}
+			for i, expected := range tc.expected {
+				a := actual[i]
+				a.Width = math.Round(a.Width*1e9) / 1e9
+				expected.Width = math.Round(expected.Width*1e9) / 1e9
+				if a != expected {
+					t.Fatalf("Incorrect extremum at index %d.\n  Expected: %+v\n  Actual: %+v", i, expected, actual[i])
+				}
+			}
+		})
+	}
+}
+
+func TestNewExtremaDetector(t *testing.T) {
+	_, err := peakdetect.NewExtremaDetector(peakdetect.ExtremaConfig{})
+	if !errors.Is(err, peakdetect.ErrInvalidConfig) {
+		t.Fatalf("Zero window did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidConfig, err)
+	}
+}
+
+func TestExtremaDetector_Next(t *testing.T) {
+	const window = 5
+	r := rand.New(rand.NewSource(1))
+	values := make([]float64, 500)
+	for i := range values {
+		values[i] = math.Round(r.NormFloat64() * 4)
+	}
+
+	cfg := peakdetect.ExtremaConfig{
+		MinProminence: 2,
+		Valleys:       true,
+		Window:        window,
+	}
+	var expected []peakdetect.Extremum
+	for _, e := range peakdetect.FindExtrema(values, cfg) {
+		if e.Index >= window && e.Index < uint64(len(values)-window) {
+			expected = append(expected, e)
+		}
+	}
+
+	detector, err := peakdetect.NewExtremaDetector(cfg)
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to create extrema detector.", err)
+	}
+	var actual []peakdetect.Extremum
+	for _, e := range detector.NextBatch(values) {
+		// Flat extrema are found from where they start, which can be before the window for FindExtrema.
+		if e.Index >= window {
+			actual = append(actual, e)
+		}
+	}
+
+	if len(expected) == 0 || len(actual) != len(expected) {
+		t.Fatalf("Incorrect number of extrema.\n  Expected: %d\n  Actual: %d", len(expected), len(actual))
+	}
+	for i := range expected {
+		a := actual[i]
+		// The width is interpolated from indices relative to the retained values.
+		if math.Abs(a.Width-expected[i].Width) < 1e-9 {
+			a.Width = expected[i].Width
+		}
+		if a != expected[i] {
+			t.Fatalf("Incorrect extremum at index %d.\n  Expected: %+v\n  Actual: %+v", i, expected[i], actual[i])
+		}
+	}
+}
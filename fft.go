@@ -0,0 +1,103 @@
+package peakdetect
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// fft computes the discrete Fourier transform of x in place. If inverse is true, the inverse transform is computed,
+// including the 1/n scaling. Lengths that are a power of two use the iterative radix-2 Cooley-Tukey algorithm. Other
+// lengths use Bluestein's algorithm, so every length is O(n log n).
+func fft(x []complex128, inverse bool) {
+	n := len(x)
+	if n <= 1 {
+		return
+	}
+	if n&(n-1) == 0 {
+		radix2(x, inverse)
+	} else {
+		bluestein(x, inverse)
+	}
+	if inverse {
+		scale := complex(1/float64(n), 0)
+		for i := range x {
+			x[i] *= scale
+		}
+	}
+}
+
+// radix2 computes the unscaled discrete Fourier transform of x in place. The length of x must be a power of two.
+func radix2(x []complex128, inverse bool) {
+	n := len(x)
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			x[i], x[j] = x[j], x[i]
+		}
+	}
+
+	sign := -1.0
+	if inverse {
+		sign = 1
+	}
+	for size := 2; size <= n; size <<= 1 {
+		step := cmplx.Rect(1, sign*2*math.Pi/float64(size))
+		for start := 0; start < n; start += size {
+			w := complex(1, 0)
+			for k := 0; k < size/2; k++ {
+				even, odd := x[start+k], x[start+k+size/2]*w
+				x[start+k] = even + odd
+				x[start+k+size/2] = even - odd
+				w *= step
+			}
+		}
+	}
+}
+
+// bluestein computes the unscaled discrete Fourier transform of x in place for any length by expressing it as a
+// convolution, which is computed with power of two transforms.
+func bluestein(x []complex128, inverse bool) {
+	n := len(x)
+	m := 1
+	for m < 2*n-1 {
+		m <<= 1
+	}
+
+	sign := -1.0
+	if inverse {
+		sign = 1
+	}
+	chirp := make([]complex128, n)
+	for k := range chirp {
+		// k*k is reduced modulo 2n to keep the angle accurate for large k.
+		angle := sign * math.Pi * float64((k*k)%(2*n)) / float64(n)
+		chirp[k] = cmplx.Rect(1, angle)
+	}
+
+	a := make([]complex128, m)
+	b := make([]complex128, m)
+	for k := 0; k < n; k++ {
+		a[k] = x[k] * chirp[k]
+	}
+	b[0] = cmplx.Conj(chirp[0])
+	for k := 1; k < n; k++ {
+		b[k] = cmplx.Conj(chirp[k])
+		b[m-k] = b[k]
+	}
+
+	radix2(a, false)
+	radix2(b, false)
+	for i := range a {
+		a[i] *= b[i]
+	}
+	radix2(a, true)
+
+	scale := complex(1/float64(m), 0)
+	for k := 0; k < n; k++ {
+		x[k] = a[k] * scale * chirp[k]
+	}
+}
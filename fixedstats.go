@@ -0,0 +1,144 @@
+package peakdetect
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// maxFixedValue is the greatest magnitude of a value of a FixedSlidingStats. The sums of the values and their squares
+// are kept in 128 bits, which is exact for windows of up to 2^31 values of this magnitude.
+const maxFixedValue = 1<<31 - 1
+
+// FixedSlidingStats tracks the mean and population standard deviation of a sliding window of int64 values using only
+// integer arithmetic, for targets without a fast floating point unit. The values can be in any fixed-point scale, such
+// as thousandths of a unit, and the mean and standard deviation are in the same scale, rounded toward zero.
+//
+// The sum of the values and the sum of their squares are kept exactly, so unlike SlidingStats, no error accumulates
+// over long streams. The window is a ring buffer that is allocated once, when the FixedSlidingStats is created. Each
+// update takes O(1) time and does not allocate. The standard deviation is an integer square root by Newton's method,
+// which converges in a handful of iterations. The magnitude of every value must be less than 2^31.
+type FixedSlidingStats struct {
+	cache   []int64
+	index   int
+	sum     int64
+	sumSqHi uint64
+	sumSqLo uint64
+}
+
+// NewFixedSlidingStats creates a new FixedSlidingStats. The length of the initialValues is the size of the window, which
+// must be in the range [1, 2^31). The magnitude of every value must be less than 2^31.
+func NewFixedSlidingStats(initialValues []int64) (*FixedSlidingStats, error) {
+	if len(initialValues) == 0 || uint64(len(initialValues)) > maxFixedValue {
+		return nil, fmt.Errorf("the length of the initial values %d is used as the window size and must be in the range [1, 2^31): %w", len(initialValues), ErrInvalidInitialValues)
+	}
+	f := &FixedSlidingStats{
+		cache: make([]int64, len(initialValues)),
+	}
+	for i, v := range initialValues {
+		if v > maxFixedValue || v < -maxFixedValue {
+			return nil, fmt.Errorf("the magnitude of the initial value %d must be less than 2^31: %w", v, ErrInvalidInitialValues)
+		}
+		f.cache[i] = v
+		f.add(v)
+	}
+	return f, nil
+}
+
+// Next adds the value to the window, removing the oldest value, and returns the new mean and population standard
+// deviation of the window. The magnitude of the value must be less than 2^31, or the statistics are undefined.
+func (f *FixedSlidingStats) Next(value int64) (mean, stdDev int64) {
+	f.remove(f.cache[f.index])
+	f.cache[f.index] = value
+	f.add(value)
+	f.index++
+	if f.index == len(f.cache) {
+		f.index = 0
+	}
+	return f.Mean(), f.StdDev()
+}
+
+// Mean returns the mean of the window, rounded toward zero.
+func (f *FixedSlidingStats) Mean() int64 {
+	return f.sum / int64(len(f.cache))
+}
+
+// Variance returns the population variance of the window, rounded down. It is in the square of the scale of the values.
+func (f *FixedSlidingStats) Variance() uint64 {
+	// The variance is (n*sumSq - sum^2) / n^2, which is computed in 128 bits.
+	n := uint64(len(f.cache))
+	hi, lo := bits.Mul64(n, f.sumSqLo)
+	hi += n * f.sumSqHi
+	sum := uint64(f.sum)
+	if f.sum < 0 {
+		sum = uint64(-f.sum)
+	}
+	sqHi, sqLo := bits.Mul64(sum, sum)
+	lo, borrow := bits.Sub64(lo, sqLo, 0)
+	hi, _ = bits.Sub64(hi, sqHi, borrow)
+	hi, lo = div128(hi, lo, n)
+	_, lo = div128(hi, lo, n)
+	return lo
+}
+
+// StdDev returns the population standard deviation of the window, rounded down.
+func (f *FixedSlidingStats) StdDev() int64 {
+	return int64(isqrt(f.Variance()))
+}
+
+// Len returns the size of the window.
+func (f *FixedSlidingStats) Len() uint {
+	return uint(len(f.cache))
+}
+
+// Window returns a copy of the values in the window in chronological order.
+func (f *FixedSlidingStats) Window() []int64 {
+	window := make([]int64, 0, len(f.cache))
+	window = append(window, f.cache[f.index:]...)
+	return append(window, f.cache[:f.index]...)
+}
+
+// Clone returns an independent deep copy of the FixedSlidingStats.
+func (f *FixedSlidingStats) Clone() *FixedSlidingStats {
+	clone := *f
+	clone.cache = append([]int64(nil), f.cache...)
+	return &clone
+}
+
+func (f *FixedSlidingStats) add(value int64) {
+	f.sum += value
+	sq := uint64(value * value)
+	var carry uint64
+	f.sumSqLo, carry = bits.Add64(f.sumSqLo, sq, 0)
+	f.sumSqHi += carry
+}
+
+func (f *FixedSlidingStats) remove(value int64) {
+	f.sum -= value
+	sq := uint64(value * value)
+	var borrow uint64
+	f.sumSqLo, borrow = bits.Sub64(f.sumSqLo, sq, 0)
+	f.sumSqHi -= borrow
+}
+
+// div128 divides the 128-bit number by the divisor, which must not be zero.
+func div128(hi, lo, divisor uint64) (quoHi, quoLo uint64) {
+	quoHi, rem := hi/divisor, hi%divisor
+	quoLo, _ = bits.Div64(rem, lo, divisor)
+	return quoHi, quoLo
+}
+
+// isqrt returns the integer square root of the value, rounded down.
+func isqrt(value uint64) uint64 {
+	if value < 2 {
+		return value
+	}
+	// Newton's method from an estimate at or above the root converges down to it.
+	x := uint64(1) << ((bits.Len64(value) + 1) / 2)
+	for {
+		y := (x + value/x) / 2
+		if y >= x {
+			return x
+		}
+		x = y
+	}
+}
@@ -1,5 +1,7 @@
 module github.com/MicahParks/peakdetect
 
-go 1.13
+go 1.21
 
 retract v0.0.6 // Improper initilization for lag value of 1. Use v0.1.0 or higher.
+
+require google.golang.org/protobuf v1.34.2
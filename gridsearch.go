@@ -0,0 +1,128 @@
+package peakdetect
+
+import (
+	"sort"
+)
+
+// Evaluation is the performance of a Config on a sample. See GridSearch.
+type Evaluation struct {
+	// Config is the candidate that was evaluated.
+	Config Config
+	// Err is the error from running the Config, if any. The other fields are zero if it is not nil.
+	Err error
+	// Signals is the number of values with a signal that is not neutral.
+	Signals int
+	// Peaks is the number of peaks, which are the signals grouped by GroupPeaks.
+	Peaks int
+	// TruePositives is the number of peaks that matched an expected peak.
+	TruePositives int
+	// Precision is the fraction of the peaks that matched an expected peak. It is zero if there are no peaks.
+	Precision float64
+	// Recall is the fraction of the expected peaks that were matched by a peak.
+	Recall float64
+	// F1 is the harmonic mean of the Precision and Recall. It is zero if both are zero.
+	F1 float64
+}
+
+// Grid returns every combination of the lags, thresholds, and influences as candidates for GridSearch.
+func Grid(lags []uint, thresholds, influences []float64) []Config {
+	candidates := make([]Config, 0, len(lags)*len(thresholds)*len(influences))
+	for _, lag := range lags {
+		for _, threshold := range thresholds {
+			for _, influence := range influences {
+				candidates = append(candidates, Config{
+					Influence: influence,
+					Lag:       lag,
+					Threshold: threshold,
+				})
+			}
+		}
+	}
+	return candidates
+}
+
+// GridSearch runs each candidate over the sample like Detect and reports how it performed, in the order of the
+// candidates. Sort the evaluations by F1 to rank the candidates.
+//
+// The expectedPeaks are the indices of the labeled peaks in the sample, such as the Peaks of a dataset from the
+// datasets package. A peak matches an expected peak if the expected peak is within tolerance values of the start or end
+// of the peak. Each expected peak is matched at most once. If there are no expectedPeaks, only Signals and Peaks are
+// reported.
+func GridSearch(sample []float64, expectedPeaks []int, tolerance uint, candidates []Config) []Evaluation {
+	evaluations := make([]Evaluation, len(candidates))
+	for i, cfg := range candidates {
+		evaluations[i] = evaluate(sample, expectedPeaks, tolerance, cfg)
+	}
+	return evaluations
+}
+
+// evaluate runs the cfg over the sample and scores its peaks against the expected peaks.
+func evaluate(sample []float64, expected []int, tolerance uint, cfg Config) Evaluation {
+	evaluation := Evaluation{
+		Config: cfg,
+	}
+	signals, peaks, err := Detect(sample, cfg)
+	if err != nil {
+		evaluation.Err = err
+		return evaluation
+	}
+	for _, signal := range signals {
+		if signal != SignalNeutral {
+			evaluation.Signals++
+		}
+	}
+	evaluation.Peaks = len(peaks)
+	if len(expected) == 0 {
+		return evaluation
+	}
+
+	for _, match := range MatchPeaks(peaks, expected, tolerance) {
+		if match != -1 {
+			evaluation.TruePositives++
+		}
+	}
+
+	if evaluation.Peaks != 0 {
+		evaluation.Precision = float64(evaluation.TruePositives) / float64(evaluation.Peaks)
+	}
+	evaluation.Recall = float64(evaluation.TruePositives) / float64(len(expected))
+	if evaluation.Precision+evaluation.Recall != 0 {
+		evaluation.F1 = 2 * evaluation.Precision * evaluation.Recall / (evaluation.Precision + evaluation.Recall)
+	}
+	return evaluation
+}
+
+// MatchPeaks matches the detected peaks against the expectedPeaks, which are the indices of labeled peaks, and returns
+// the index into expectedPeaks of the match for each peak, or -1 if it did not match. It is how GridSearch scores
+// peaks, so other scoring can agree with it.
+//
+// The peaks are matched in order. A peak matches the earliest expected peak that is not yet matched and is within
+// tolerance values of the start or end of the peak. Each expected peak is matched at most once. The expectedPeaks do
+// not need to be sorted.
+func MatchPeaks(peaks []PeakEvent, expectedPeaks []int, tolerance uint) []int {
+	order := make([]int, len(expectedPeaks))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return expectedPeaks[order[a]] < expectedPeaks[order[b]]
+	})
+
+	matched := make([]bool, len(order))
+	matches := make([]int, len(peaks))
+	for i, peak := range peaks {
+		matches[i] = -1
+		start, end := int(peak.StartIndex)-int(tolerance), int(peak.EndIndex)+int(tolerance)
+		j := sort.Search(len(order), func(j int) bool {
+			return expectedPeaks[order[j]] >= start
+		})
+		for ; j < len(order) && expectedPeaks[order[j]] <= end; j++ {
+			if !matched[j] {
+				matched[j] = true
+				matches[i] = order[j]
+				break
+			}
+		}
+	}
+	return matches
+}
@@ -0,0 +1,82 @@
+package peakdetect_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/MicahParks/peakdetect"
+)
+
+func TestGridSearch(t *testing.T) {
+	var expected []int
+	for _, peak := range peakdetect.GroupPeaks(exampleOutputs, exampleInputs) {
+		expected = append(expected, int(peak.ApexIndex))
+	}
+
+	candidates := peakdetect.Grid([]uint{exampleLag}, []float64{exampleThreshold, 1000}, []float64{exampleInfluence})
+	candidates = append(candidates, peakdetect.Config{Influence: 2, Lag: exampleLag, Threshold: exampleThreshold})
+	evaluations := peakdetect.GridSearch(exampleInputs, expected, 0, candidates)
+	if len(evaluations) != len(candidates) {
+		t.Fatalf("Incorrect number of evaluations.\n  Expected: %d\n  Actual: %d", len(candidates), len(evaluations))
+	}
+
+	exact := evaluations[0]
+	if exact.Err != nil || exact.Peaks != len(expected) || exact.TruePositives != len(expected) || exact.F1 != 1 {
+		t.Fatalf("The example config should match every expected peak.\n  Actual: %+v", exact)
+	}
+	var signals int
+	for _, signal := range exampleOutputs {
+		if signal != peakdetect.SignalNeutral {
+			signals++
+		}
+	}
+	if exact.Signals != signals {
+		t.Fatalf("Incorrect number of signals.\n  Expected: %d\n  Actual: %d", signals, exact.Signals)
+	}
+
+	insensitive := evaluations[1]
+	if insensitive.Peaks != 0 || insensitive.Precision != 0 || insensitive.Recall != 0 || insensitive.F1 != 0 {
+		t.Fatalf("A threshold that is too high should not match any peaks.\n  Actual: %+v", insensitive)
+	}
+
+	if !errors.Is(evaluations[2].Err, peakdetect.ErrInvalidConfig) {
+		t.Fatalf("Invalid config did not produce the correct error.\n  Expected: %s\n  Actual: %v", peakdetect.ErrInvalidConfig, evaluations[2].Err)
+	}
+}
+
+func TestGridSearch_Tolerance(t *testing.T) {
+	candidates := []peakdetect.Config{{Influence: exampleInfluence, Lag: exampleLag, Threshold: exampleThreshold}}
+	peaks := peakdetect.GroupPeaks(exampleOutputs, exampleInputs)
+	shifted := []int{int(peaks[0].StartIndex) - 2}
+
+	evaluation := peakdetect.GridSearch(exampleInputs, shifted, 1, candidates)[0]
+	if evaluation.TruePositives != 0 {
+		t.Fatalf("A peak outside the tolerance was matched.\n  Actual: %+v", evaluation)
+	}
+	evaluation = peakdetect.GridSearch(exampleInputs, shifted, 2, candidates)[0]
+	if evaluation.TruePositives != 1 || evaluation.Recall != 1 {
+		t.Fatalf("A peak within the tolerance was not matched.\n  Actual: %+v", evaluation)
+	}
+
+	evaluation = peakdetect.GridSearch(exampleInputs, nil, 0, candidates)[0]
+	if evaluation.Peaks != len(peaks) || evaluation.F1 != 0 {
+		t.Fatalf("Without expected peaks only the counts should be reported.\n  Actual: %+v", evaluation)
+	}
+}
+
+func TestMatchPeaks(t *testing.T) {
+	peaks := []peakdetect.PeakEvent{
+		{StartIndex: 10, EndIndex: 12},
+		{StartIndex: 20, EndIndex: 20},
+		{StartIndex: 22, EndIndex: 23},
+		{StartIndex: 50, EndIndex: 50},
+	}
+	// The expected peaks are unsorted, and the peak at 20 is within the tolerance of both 18 and 21.
+	matches := peakdetect.MatchPeaks(peaks, []int{21, 13, 18}, 2)
+	expected := []int{1, 2, 0, -1}
+	for i, match := range matches {
+		if match != expected[i] {
+			t.Fatalf("Incorrect match for peak %d.\n  Expected: %v\n  Actual: %v", i, expected, matches)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package peakdetect
+
+import (
+	"fmt"
+	"math/cmplx"
+)
+
+// HilbertEnvelope computes the amplitude envelope of the values, which is the magnitude of their analytic signal. The
+// analytic signal is computed with an FFT based Hilbert transform over the whole slice, so this is an offline
+// utility. The envelope is the same length as the values.
+//
+// For oscillatory data such as audio or vibration, individual samples cross zero constantly and their peaks are
+// meaningless. The envelope instead rises and falls with the bursts of oscillation.
+func HilbertEnvelope(values []float64) []float64 {
+	n := len(values)
+	analytic := make([]complex128, n)
+	for i, v := range values {
+		analytic[i] = complex(v, 0)
+	}
+	fft(analytic, false)
+
+	// Keep the zero and Nyquist frequencies, double the positive frequencies, and remove the negative frequencies.
+	for i := 1; i < n; i++ {
+		switch {
+		case 2*i < n:
+			analytic[i] *= 2
+		case 2*i > n:
+			analytic[i] = 0
+		}
+	}
+	fft(analytic, true)
+
+	envelope := make([]float64, n)
+	for i, z := range analytic {
+		envelope[i] = cmplx.Abs(z)
+	}
+	return envelope
+}
+
+// DetectHilbertEnvelope runs peak detection on the amplitude envelope of the values. See HilbertEnvelope. The first
+// cfg.Lag values of the envelope, plus cfg.DerivativeOrder, initialize the PeakDetector and have neutral signals. The
+// signals and the envelope are both the same length as the values.
+func DetectHilbertEnvelope(values []float64, cfg Config) (signals []Signal, envelope []float64, err error) {
+	if cfg.Lag+cfg.DerivativeOrder > uint(len(values)) {
+		return nil, nil, fmt.Errorf("the lag %d is longer than the %d values: %w", cfg.Lag, len(values), ErrInvalidInitialValues)
+	}
+	envelope = HilbertEnvelope(values)
+
+	signals, err = NewPeakDetector().InitializeAndDetect(cfg, envelope)
+	if err != nil {
+		return nil, nil, err
+	}
+	return signals, envelope, nil
+}
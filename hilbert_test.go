@@ -0,0 +1,62 @@
+package peakdetect_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/MicahParks/peakdetect"
+)
+
+func TestHilbertEnvelope(t *testing.T) {
+	// The envelope of a sinusoid with an integer number of periods is its amplitude. Both a power of two length and
+	// another length are tested.
+	for _, n := range []int{256, 300} {
+		values := make([]float64, n)
+		for i := range values {
+			values[i] = 2 * math.Sin(2*math.Pi*10*float64(i)/float64(n))
+		}
+
+		for i, v := range peakdetect.HilbertEnvelope(values) {
+			if math.Abs(v-2) > 1e-9 {
+				t.Fatalf("Envelope did not match amplitude for length %d at index %d.\n  Expected: %f\n  Actual: %f", n, i, 2.0, v)
+			}
+		}
+	}
+}
+
+func TestDetectHilbertEnvelope(t *testing.T) {
+	const n = 1000
+	values := make([]float64, n)
+	for i := range values {
+		amplitude := 0.1
+		if i >= 600 && i < 700 {
+			amplitude = 1
+		}
+		values[i] = amplitude * math.Sin(2*math.Pi*float64(i)/8)
+	}
+
+	cfg := peakdetect.Config{Influence: 0, Lag: 100, Threshold: 5}
+	signals, envelope, err := peakdetect.DetectHilbertEnvelope(values, cfg)
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to detect peaks on the envelope.", err)
+	}
+	if len(signals) != n || len(envelope) != n {
+		t.Fatalf("Unexpected output lengths.\n  Signals: %d\n  Envelope: %d", len(signals), len(envelope))
+	}
+	for i := 620; i < 680; i++ {
+		if signals[i] != peakdetect.SignalPositive {
+			t.Fatalf("Burst was not detected at index %d.\n  Actual: %d", i, signals[i])
+		}
+	}
+	for i := 100; i < 550; i++ {
+		if signals[i] != peakdetect.SignalNeutral {
+			t.Fatalf("Unexpected signal before the burst at index %d.\n  Actual: %d", i, signals[i])
+		}
+	}
+
+	_, _, err = peakdetect.DetectHilbertEnvelope(values[:10], cfg)
+	if !errors.Is(err, peakdetect.ErrInvalidInitialValues) {
+		t.Fatalf("Lag longer than the values did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidInitialValues, err)
+	}
+}
@@ -0,0 +1,132 @@
+package peakdetect
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// ErrInvalidBuckets indicates that the histogram bucket bounds provided are not valid.
+var ErrInvalidBuckets = errors.New("the histogram bucket bounds provided are invalid")
+
+// Histogram is a fixed bucket histogram of the most recent values in a sliding window. Changes in the shape of the
+// distribution are often the earliest sign that the parameters of a detector need to be tuned.
+//
+// Buckets are defined by their inclusive upper bounds, like Prometheus histograms. An implicit final bucket holds
+// values greater than the last bound.
+type Histogram struct {
+	bounds []float64
+	counts []uint64
+	filled bool
+	index  uint
+	sum    float64
+	values []float64
+}
+
+// HistogramSnapshot is the state of a Histogram at a point in time.
+type HistogramSnapshot struct {
+	// Bounds are the inclusive upper bounds of the buckets, in ascending order.
+	Bounds []float64
+	// Counts are the number of values in each bucket. It has one more element than Bounds for the values greater than
+	// the last bound.
+	Counts []uint64
+	// Count is the number of values in the window.
+	Count uint64
+	// Sum is the sum of the values in the window.
+	Sum float64
+}
+
+// NewHistogram creates a new Histogram of the most recent window values with buckets defined by the given upper
+// bounds. The bounds must be in strictly ascending order. See LinearBuckets and ExponentialBuckets for creating bounds.
+func NewHistogram(window uint, bounds []float64) (*Histogram, error) {
+	if window == 0 {
+		return nil, fmt.Errorf("the window size for a histogram must be greater than zero: %w", ErrInvalidWindow)
+	}
+	if len(bounds) == 0 {
+		return nil, fmt.Errorf("at least one bucket bound is required: %w", ErrInvalidBuckets)
+	}
+	for i := 1; i < len(bounds); i++ {
+		if !(bounds[i] > bounds[i-1]) {
+			return nil, fmt.Errorf("bucket bounds must be in strictly ascending order: %w", ErrInvalidBuckets)
+		}
+	}
+	return &Histogram{
+		bounds: append([]float64(nil), bounds...),
+		counts: make([]uint64, len(bounds)+1),
+		values: make([]float64, 0, window),
+	}, nil
+}
+
+// LinearBuckets creates count bucket bounds, each width apart, with the first bound being start.
+func LinearBuckets(start, width float64, count int) []float64 {
+	bounds := make([]float64, count)
+	for i := range bounds {
+		bounds[i] = start + float64(i)*width
+	}
+	return bounds
+}
+
+// ExponentialBuckets creates count bucket bounds, each factor times the previous, with the first bound being start.
+func ExponentialBuckets(start, factor float64, count int) []float64 {
+	bounds := make([]float64, count)
+	for i := range bounds {
+		bounds[i] = start * math.Pow(factor, float64(i))
+	}
+	return bounds
+}
+
+// Next adds the value to the window. The oldest value is removed once the window is full.
+func (h *Histogram) Next(value float64) {
+	if h.filled {
+		oldest := h.values[h.index]
+		h.counts[h.bucket(oldest)]--
+		h.sum -= oldest
+		h.values[h.index] = value
+	} else {
+		h.values = append(h.values, value)
+	}
+	h.index++
+	if h.index == uint(cap(h.values)) {
+		h.index = 0
+		h.filled = true
+	}
+
+	h.counts[h.bucket(value)]++
+	h.sum += value
+}
+
+// clone returns a deep copy of the Histogram.
+func (h *Histogram) clone() *Histogram {
+	clone := *h
+	clone.counts = append([]uint64(nil), h.counts...)
+	clone.values = append(make([]float64, 0, cap(h.values)), h.values...)
+	return &clone
+}
+
+// Snapshot returns a copy of the current state of the Histogram.
+func (h *Histogram) Snapshot() HistogramSnapshot {
+	return HistogramSnapshot{
+		Bounds: append([]float64(nil), h.bounds...),
+		Counts: append([]uint64(nil), h.counts...),
+		Count:  uint64(len(h.values)),
+		Sum:    h.sum,
+	}
+}
+
+// bucket returns the index of the bucket the value belongs in.
+func (h *Histogram) bucket(value float64) int {
+	return sort.SearchFloat64s(h.bounds, value)
+}
+
+// CumulativeBuckets returns the cumulative count of values less than or equal to each bound. Along with Count and Sum,
+// this is the form Prometheus expects for a constant histogram, such as one created with prometheus.NewConstHistogram.
+func (s HistogramSnapshot) CumulativeBuckets() map[float64]uint64 {
+	buckets := make(map[float64]uint64, len(s.Bounds))
+	var cumulative uint64
+	for i, bound := range s.Bounds {
+		cumulative += s.Counts[i]
+		buckets[bound] = cumulative
+	}
+	return buckets
+}
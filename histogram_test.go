@@ -0,0 +1,73 @@
+package peakdetect_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/MicahParks/peakdetect"
+)
+
+func TestNewHistogram(t *testing.T) {
+	_, err := peakdetect.NewHistogram(0, []float64{1})
+	if !errors.Is(err, peakdetect.ErrInvalidWindow) {
+		t.Fatalf("Invalid window did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidWindow, err)
+	}
+	_, err = peakdetect.NewHistogram(1, []float64{2, 1})
+	if !errors.Is(err, peakdetect.ErrInvalidBuckets) {
+		t.Fatalf("Invalid buckets did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidBuckets, err)
+	}
+}
+
+func TestHistogram_Next(t *testing.T) {
+	histogram, err := peakdetect.NewHistogram(4, peakdetect.LinearBuckets(1, 1, 3))
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to create histogram.", err)
+	}
+
+	for _, v := range []float64{0.5, 1, 2.5, 3, 10, 2} {
+		histogram.Next(v)
+	}
+
+	snapshot := histogram.Snapshot()
+	expectedCounts := []uint64{0, 1, 2, 1}
+	for i, count := range snapshot.Counts {
+		if count != expectedCounts[i] {
+			t.Fatalf("Bucket count did not match at index %d.\n  Expected: %d\n  Actual: %d", i, expectedCounts[i], count)
+		}
+	}
+	if snapshot.Count != 4 || snapshot.Sum != 17.5 {
+		t.Fatalf("Histogram count or sum did not match.\n  Actual: %+v", snapshot)
+	}
+
+	cumulative := snapshot.CumulativeBuckets()
+	expectedCumulative := map[float64]uint64{1: 0, 2: 1, 3: 3}
+	for bound, count := range expectedCumulative {
+		if cumulative[bound] != count {
+			t.Fatalf("Cumulative bucket did not match for bound %f.\n  Expected: %d\n  Actual: %d", bound, count, cumulative[bound])
+		}
+	}
+}
+
+func TestPeakDetector_SetHistogram(t *testing.T) {
+	histogram, err := peakdetect.NewHistogram(10, peakdetect.ExponentialBuckets(1, 2, 4))
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to create histogram.", err)
+	}
+
+	detector := peakdetect.NewPeakDetector()
+	err = detector.Initialize(exampleInfluence, exampleThreshold, exampleInputs[0:exampleLag])
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+	if detector.Summary().Histogram != nil {
+		t.Fatalf("Summary should not include a histogram before one is set.")
+	}
+
+	detector.SetHistogram(histogram)
+	detector.NextBatch(exampleInputs[exampleLag:])
+
+	snapshot := detector.Summary().Histogram
+	if snapshot == nil || snapshot.Count != 10 {
+		t.Fatalf("Summary should include the histogram of the last 10 values.\n  Actual: %+v", snapshot)
+	}
+}
@@ -0,0 +1,29 @@
+package peakdetect
+
+// signalHandler is a handler registered with OnSignal.
+type signalHandler struct {
+	direction Direction
+	handle    func(event SignalEvent)
+}
+
+func (p *peakDetector) OnSignal(direction Direction, handler func(event SignalEvent)) {
+	p.handlers = append(p.handlers, signalHandler{
+		direction: direction,
+		handle:    handler,
+	})
+}
+
+// emit calls the handlers that allow the signal with an event for the value at the index.
+func (p *peakDetector) emit(index uint64, signal Signal, value float64) {
+	for _, h := range p.handlers {
+		if !h.direction.allows(signal) {
+			continue
+		}
+		h.handle(SignalEvent{
+			Index:  index,
+			Labels: p.labels,
+			Signal: signal,
+			Value:  value,
+		})
+	}
+}
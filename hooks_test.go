@@ -0,0 +1,68 @@
+package peakdetect_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/MicahParks/peakdetect"
+)
+
+func TestPeakDetector_OnSignal(t *testing.T) {
+	var expected, expectedPositive []peakdetect.SignalEvent
+	for i, signal := range exampleOutputs[exampleLag:] {
+		if signal == peakdetect.SignalNeutral {
+			continue
+		}
+		event := peakdetect.SignalEvent{
+			Index:  uint64(i),
+			Signal: signal,
+			Value:  exampleInputs[exampleLag+i],
+		}
+		expected = append(expected, event)
+		if signal == peakdetect.SignalPositive {
+			expectedPositive = append(expectedPositive, event)
+		}
+	}
+
+	for name, process := range map[string]func(detector peakdetect.PeakDetector){
+		"Next": func(detector peakdetect.PeakDetector) {
+			for _, v := range exampleInputs[exampleLag:] {
+				detector.Next(v)
+			}
+		},
+		"NextBatch": func(detector peakdetect.PeakDetector) {
+			detector.NextBatch(exampleInputs[exampleLag:])
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			detector := peakdetect.NewPeakDetector()
+			var events, positive []peakdetect.SignalEvent
+			detector.OnSignal(peakdetect.DirectionBoth, func(event peakdetect.SignalEvent) {
+				events = append(events, event)
+			})
+			detector.OnSignal(peakdetect.DirectionPositive, func(event peakdetect.SignalEvent) {
+				positive = append(positive, event)
+			})
+
+			err := detector.Initialize(exampleInfluence, exampleThreshold, exampleInputs[:exampleLag])
+			if err != nil {
+				t.Fatalf(logFmt, "Error during initilization.", err)
+			}
+			clone := detector.Clone()
+			process(detector)
+
+			if !reflect.DeepEqual(events, expected) {
+				t.Fatalf("Incorrect events.\n  Expected: %+v\n  Actual: %+v", expected, events)
+			}
+			if !reflect.DeepEqual(positive, expectedPositive) {
+				t.Fatalf("Incorrect events for the positive handler.\n  Expected: %+v\n  Actual: %+v", expectedPositive, positive)
+			}
+
+			events, positive = nil, nil
+			process(clone)
+			if len(events) != 0 || len(positive) != 0 {
+				t.Fatalf("The handlers should not be copied by Clone.")
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package peakdetect
+
+import (
+	"fmt"
+)
+
+// MedianFilter is a streaming despiking filter that replaces each value with the median of the most recent k values.
+// Use it as a pre-filter stage before a detector, so single value impulses, such as ADC glitches, are removed before
+// they cause false signals or corrupt the lag window. A filter of size k removes impulses up to (k-1)/2 values long and
+// delays edges by (k-1)/2 values.
+type MedianFilter struct {
+	index   uint
+	scratch []float64
+	values  []float64
+}
+
+// NewMedianFilter creates a new MedianFilter of size k. k must be odd. A k of 3 is usually enough for single value
+// impulses.
+func NewMedianFilter(k uint) (*MedianFilter, error) {
+	if k%2 == 0 {
+		return nil, fmt.Errorf("the size of a median filter must be odd: %w", ErrInvalidWindow)
+	}
+	return &MedianFilter{
+		scratch: make([]float64, 0, k),
+		values:  make([]float64, 0, k),
+	}, nil
+}
+
+// Next processes the next value and returns the median of the most recent k values. Until k values have been
+// processed, the median of the values so far is returned.
+func (m *MedianFilter) Next(value float64) float64 {
+	if len(m.values) < cap(m.values) {
+		m.values = append(m.values, value)
+	} else {
+		m.values[m.index] = value
+		m.index++
+		if m.index == uint(len(m.values)) {
+			m.index = 0
+		}
+	}
+	m.scratch = append(m.scratch[:0], m.values...)
+	return medianSelect(m.scratch)
+}
+
+// NextBatch processes the next values and returns their filtered values.
+func (m *MedianFilter) NextBatch(values []float64) []float64 {
+	filtered := make([]float64, len(values))
+	for i, v := range values {
+		filtered[i] = m.Next(v)
+	}
+	return filtered
+}
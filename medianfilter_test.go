@@ -0,0 +1,58 @@
+package peakdetect_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/MicahParks/peakdetect"
+)
+
+func TestNewMedianFilter(t *testing.T) {
+	_, err := peakdetect.NewMedianFilter(4)
+	if !errors.Is(err, peakdetect.ErrInvalidWindow) {
+		t.Fatalf("Invalid size did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidWindow, err)
+	}
+}
+
+func TestMedianFilter_NextBatch(t *testing.T) {
+	filter, err := peakdetect.NewMedianFilter(3)
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to create median filter.", err)
+	}
+
+	filtered := filter.NextBatch([]float64{1, 1, 1000, 1, 1, 5, 5, 5, -1000, 5})
+	expected := []float64{1, 1, 1, 1, 1, 1, 5, 5, 5, 5}
+	for i, v := range filtered {
+		if v != expected[i] {
+			t.Fatalf("Filtered value did not match at index %d.\n  Expected: %f\n  Actual: %f", i, expected[i], v)
+		}
+	}
+}
+
+func TestMedianFilter_PreFilter(t *testing.T) {
+	noise := []float64{0.3, -0.2, 0.1, -0.3, 0.2, -0.1, 0}
+	var data []float64
+	for i := 0; i < 50; i++ {
+		data = append(data, 1+noise[i%len(noise)])
+	}
+	data[40] = 100
+
+	filter, err := peakdetect.NewMedianFilter(3)
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to create median filter.", err)
+	}
+	filtered := filter.NextBatch(data)
+
+	const lag = 30
+	detector := peakdetect.NewPeakDetector()
+	err = detector.Initialize(0, 3, filtered[:lag])
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+	signals := detector.NextBatch(filtered[lag:])
+	for i, signal := range signals {
+		if signal != peakdetect.SignalNeutral {
+			t.Fatalf("Impulse was not removed at index %d.\n  Actual: %d", i+lag, signal)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package peakdetect
+
+import (
+	"unsafe"
+)
+
+// MemoryFootprint returns the approximate number of bytes used by the MovingMinMax, including its deques.
+func (m *MovingMinMax) MemoryFootprint() uintptr {
+	return unsafe.Sizeof(*m) + m.maxes.memoryFootprint() + m.mins.memoryFootprint()
+}
+
+// MemoryFootprint returns the approximate number of bytes used by the Histogram, including its buckets and window.
+func (h *Histogram) MemoryFootprint() uintptr {
+	return unsafe.Sizeof(*h) +
+		uintptr(cap(h.bounds))*unsafe.Sizeof(float64(0)) +
+		uintptr(cap(h.counts))*unsafe.Sizeof(uint64(0)) +
+		uintptr(cap(h.values))*unsafe.Sizeof(float64(0))
+}
+
+func (p *peakDetector) MemoryFootprint() uintptr {
+	size := unsafe.Sizeof(*p) + p.baseline.memoryFootprint() +
+		uintptr(cap(p.persistence))*unsafe.Sizeof(persistenceEntry{}) +
+		uintptr(cap(p.last.backfilled))*unsafe.Sizeof(uint(0)) +
+		uintptr(cap(p.initialOutliers))*unsafe.Sizeof(int(0)) +
+		uintptr(cap(p.handlers))*unsafe.Sizeof(signalHandler{})
+	for key, value := range p.labels {
+		// The overhead of the map itself is not known, so only its keys and values are counted.
+		size += 2*unsafe.Sizeof("") + uintptr(len(key)+len(value))
+	}
+	if p.movingMinMax != nil {
+		size += p.movingMinMax.MemoryFootprint()
+	}
+	if p.histogram != nil {
+		size += p.histogram.MemoryFootprint()
+	}
+	if p.moments != nil {
+		size += p.moments.MemoryFootprint()
+	}
+	if p.quantiles != nil {
+		size += unsafe.Sizeof(*p.quantiles) + uintptr(cap(p.quantiles.cache)+cap(p.quantiles.sorted))*unsafe.Sizeof(float64(0))
+	}
+	if p.differencer != nil {
+		size += unsafe.Sizeof(*p.differencer) + uintptr(cap(p.differencer.prev))*unsafe.Sizeof(float64(0))
+	}
+	return size
+}
+
+// MemoryFootprint returns the approximate number of bytes used by the MultiPeakDetector, which is the sum of the
+// MemoryFootprint of every channel. Use the MemoryFootprint of each Channel for the bytes used by a single channel.
+func (m *MultiPeakDetector) MemoryFootprint() uintptr {
+	size := unsafe.Sizeof(*m) + uintptr(cap(m.detectors))*unsafe.Sizeof(PeakDetector(nil))
+	for _, detector := range m.detectors {
+		size += detector.MemoryFootprint()
+	}
+	return size
+}
+
+// MemoryFootprint returns the approximate number of bytes used by the MovingMoments, including its window.
+func (m *MovingMoments) MemoryFootprint() uintptr {
+	return unsafe.Sizeof(*m) + uintptr(cap(m.values))*unsafe.Sizeof(float64(0))
+}
+
+func (s *stepDetector) MemoryFootprint() uintptr {
+	size := unsafe.Sizeof(*s) + uintptr(cap(s.warmup))*unsafe.Sizeof(float64(0))
+	if s.pre != nil {
+		size += s.pre.memoryFootprint() + s.post.memoryFootprint()
+	}
+	return size
+}
+
+func (d *monotonicDeque) memoryFootprint() uintptr {
+	return uintptr(cap(d.buf)) * unsafe.Sizeof(indexedValue{})
+}
+
+func (m *SlidingStats) memoryFootprint() uintptr {
+	return unsafe.Sizeof(*m) + uintptr(cap(m.cache))*unsafe.Sizeof(float64(0))
+}
+
+// memoryFootprint does not include the BaselineEstimator, whose size is unknown.
+func (c *customBaseline) memoryFootprint() uintptr {
+	return unsafe.Sizeof(*c) + uintptr(cap(c.cache))*unsafe.Sizeof(float64(0)) + c.replaced.memoryFootprint()
+}
+
+func (m *movingMedianMAD) memoryFootprint() uintptr {
+	return unsafe.Sizeof(*m) + uintptr(cap(m.cache)+cap(m.sorted))*unsafe.Sizeof(float64(0))
+}
+
+func (c *cusumDetector) MemoryFootprint() uintptr {
+	return unsafe.Sizeof(*c) + uintptr(cap(c.warmup))*unsafe.Sizeof(float64(0))
+}
@@ -0,0 +1,81 @@
+package peakdetect_test
+
+import (
+	"testing"
+
+	"github.com/MicahParks/peakdetect"
+)
+
+func TestPeakDetector_MemoryFootprint(t *testing.T) {
+	small := peakdetect.NewPeakDetector()
+	err := small.Initialize(exampleInfluence, exampleThreshold, exampleInputs[:10])
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+	large := peakdetect.NewPeakDetector()
+	err = large.Initialize(exampleInfluence, exampleThreshold, exampleInputs[:20])
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+
+	// Each value of lag adds a float64 to the window and an entry to each of the two deques for the minimum and maximum.
+	difference := large.MemoryFootprint() - small.MemoryFootprint()
+	const expected = 10 * (8 + 2*16)
+	if difference != expected {
+		t.Fatalf("Unexpected memory difference for 10 more values of lag.\n  Expected: %d\n  Actual: %d", expected, difference)
+	}
+
+	histogram, err := peakdetect.NewHistogram(100, []float64{1})
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to create histogram.", err)
+	}
+	before := large.MemoryFootprint()
+	large.SetHistogram(histogram)
+	if large.MemoryFootprint() != before+histogram.MemoryFootprint() {
+		t.Fatalf("Memory footprint did not include the histogram.")
+	}
+
+	// Each label adds the headers of its key and value strings and their bytes.
+	before = large.MemoryFootprint()
+	large.SetLabels(map[string]string{"series": "memory"})
+	if difference, expected := large.MemoryFootprint()-before, uintptr(2*16+len("series")+len("memory")); difference != expected {
+		t.Fatalf("Unexpected memory difference for a label.\n  Expected: %d\n  Actual: %d", expected, difference)
+	}
+
+	persistent := peakdetect.NewPeakDetector()
+	err = persistent.InitializeWithConfig(peakdetect.Config{
+		Influence:   exampleInfluence,
+		Threshold:   exampleThreshold,
+		Persistence: 3,
+	}, exampleInputs[:20])
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+	before = persistent.MemoryFootprint()
+	persistent.NextBatch(exampleInputs[20:])
+	if persistent.MemoryFootprint() <= before {
+		t.Fatalf("Memory footprint did not include the values tracked for persistence.")
+	}
+}
+
+func TestStepDetector_MemoryFootprint(t *testing.T) {
+	detector := peakdetect.NewStepDetector()
+	err := detector.Initialize(5, 10)
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+	if detector.MemoryFootprint() == 0 {
+		t.Fatalf("Memory footprint should not be zero.")
+	}
+}
+
+func TestMultiPeakDetector_MemoryFootprint(t *testing.T) {
+	detector := newExampleMultiPeakDetector(t)
+	var sum uintptr
+	for i := 0; i < detector.Channels(); i++ {
+		sum += detector.Channel(i).MemoryFootprint()
+	}
+	if total := detector.MemoryFootprint(); total <= sum {
+		t.Fatalf("Memory footprint should include every channel.\n  Expected: more than %d\n  Actual: %d", sum, total)
+	}
+}
@@ -0,0 +1,15 @@
+package peakdetect
+
+// Metrics receives instrumentation from a PeakDetector, such as to export counters of signals, gauges of the moving mean
+// and standard deviation, and a histogram of z-scores to a monitoring system. See the peakdetectmetrics package for a
+// ready-made implementation with Prometheus and expvar output.
+type Metrics interface {
+	// Observe is called with the labels of the PeakDetector and the Result for every value processed. It is called
+	// synchronously, so it should be fast, and it must not call the PeakDetector. Values that are missing and not
+	// processed, as described by Config.Missing, are not observed.
+	Observe(labels map[string]string, result Result)
+}
+
+func (p *peakDetector) SetMetrics(m Metrics) {
+	p.metrics = m
+}
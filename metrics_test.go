@@ -0,0 +1,52 @@
+package peakdetect_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/MicahParks/peakdetect"
+)
+
+type recordingMetrics struct {
+	labels  []map[string]string
+	results []peakdetect.Result
+}
+
+func (r *recordingMetrics) Observe(labels map[string]string, result peakdetect.Result) {
+	r.labels = append(r.labels, labels)
+	r.results = append(r.results, result)
+}
+
+func TestPeakDetector_SetMetrics(t *testing.T) {
+	labels := map[string]string{"series": "metrics"}
+	expected := peakdetect.NewPeakDetector()
+	detector := peakdetect.NewPeakDetector()
+	metrics := &recordingMetrics{}
+	detector.SetLabels(labels)
+	detector.SetMetrics(metrics)
+	for _, d := range []peakdetect.PeakDetector{expected, detector} {
+		err := d.Initialize(exampleInfluence, exampleThreshold, exampleInputs[:exampleLag])
+		if err != nil {
+			t.Fatalf(logFmt, "Error during initilization.", err)
+		}
+	}
+
+	signals := detector.NextBatch(exampleInputs[exampleLag:])
+	if !reflect.DeepEqual(signals, exampleOutputs[exampleLag:]) {
+		t.Fatalf("Metrics changed the signals.\n  Expected: %v\n  Actual: %v", exampleOutputs[exampleLag:], signals)
+	}
+	results := expected.NextBatchDetailed(exampleInputs[exampleLag:])
+	if !reflect.DeepEqual(metrics.results, results) {
+		t.Fatalf("Incorrect observed results.\n  Expected: %+v\n  Actual: %+v", results, metrics.results)
+	}
+	if !reflect.DeepEqual(metrics.labels[0], labels) {
+		t.Fatalf("Incorrect observed labels.\n  Expected: %v\n  Actual: %v", labels, metrics.labels[0])
+	}
+
+	metrics.results = nil
+	detector.SetMetrics(nil)
+	detector.Next(1)
+	if len(metrics.results) != 0 {
+		t.Fatalf("Metrics were observed after they were removed.")
+	}
+}
@@ -0,0 +1,136 @@
+package peakdetect
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidWindow indicates that the window size provided is not valid.
+var ErrInvalidWindow = errors.New("the window size provided is invalid")
+
+// MovingMinMax tracks the minimum and maximum of the most recent values in a sliding window. Both are maintained with
+// monotonic deques, so each update is amortized O(1) regardless of the window size.
+//
+// The minimum of the window can be used as a baseline for subtraction. The height of a value above the recent floor is
+// a simple peak criterion that is often more useful than a z-score for data such as queue depths.
+type MovingMinMax struct {
+	count  uint64
+	maxes  monotonicDeque
+	mins   monotonicDeque
+	window uint64
+}
+
+// NewMovingMinMax creates a new MovingMinMax with the given window size. The window size must be greater than zero.
+func NewMovingMinMax(window uint) (*MovingMinMax, error) {
+	if window == 0 {
+		return nil, fmt.Errorf("the window size for a moving minimum and maximum must be greater than zero: %w", ErrInvalidWindow)
+	}
+	return newMovingMinMax(window), nil
+}
+
+func newMovingMinMax(window uint) *MovingMinMax {
+	return &MovingMinMax{
+		maxes:  newMonotonicDeque(window),
+		mins:   newMonotonicDeque(window),
+		window: uint64(window),
+	}
+}
+
+// clone returns a deep copy of the MovingMinMax.
+func (m *MovingMinMax) clone() *MovingMinMax {
+	clone := *m
+	clone.maxes.buf = append([]indexedValue(nil), m.maxes.buf...)
+	clone.mins.buf = append([]indexedValue(nil), m.mins.buf...)
+	return &clone
+}
+
+// reset empties the window, keeping the allocated deques.
+func (m *MovingMinMax) reset() {
+	m.count = 0
+	m.maxes.head, m.maxes.length = 0, 0
+	m.mins.head, m.mins.length = 0, 0
+}
+
+// Next adds the value to the window and returns the minimum and maximum of the window, including the new value.
+func (m *MovingMinMax) Next(value float64) (min, max float64) {
+	m.count++
+	if m.count > m.window {
+		expired := m.count - m.window
+		m.mins.popFrontThrough(expired)
+		m.maxes.popFrontThrough(expired)
+	}
+
+	m.mins.pushBack(m.count, value, func(back float64) bool { return back >= value })
+	m.maxes.pushBack(m.count, value, func(back float64) bool { return back <= value })
+
+	return m.Min(), m.Max()
+}
+
+// NextHeight adds the value to the window and returns its height above the minimum of the window. This is the baseline
+// subtraction stage for a "height above the recent floor" peak criterion.
+func (m *MovingMinMax) NextHeight(value float64) float64 {
+	min, _ := m.Next(value)
+	return value - min
+}
+
+// Min returns the minimum of the window. It returns zero if no values have been added.
+func (m *MovingMinMax) Min() float64 {
+	return m.mins.front()
+}
+
+// Max returns the maximum of the window. It returns zero if no values have been added.
+func (m *MovingMinMax) Max() float64 {
+	return m.maxes.front()
+}
+
+// indexedValue is a value and the position it was added at.
+type indexedValue struct {
+	position uint64
+	value    float64
+}
+
+// monotonicDeque is a fixed capacity double-ended queue backed by a ring buffer. The owner keeps it monotonic by
+// evicting values from the back before pushing.
+type monotonicDeque struct {
+	buf    []indexedValue
+	head   int
+	length int
+}
+
+func newMonotonicDeque(capacity uint) monotonicDeque {
+	return monotonicDeque{
+		buf: make([]indexedValue, capacity),
+	}
+}
+
+// pushBack removes values from the back of the deque while evict returns true, then adds the value to the back.
+func (d *monotonicDeque) pushBack(position uint64, value float64, evict func(back float64) bool) {
+	for d.length > 0 && evict(d.buf[d.backIndex()].value) {
+		d.length--
+	}
+	d.length++
+	d.buf[d.backIndex()] = indexedValue{
+		position: position,
+		value:    value,
+	}
+}
+
+// popFrontThrough removes values from the front of the deque whose position is less than or equal to the given
+// position.
+func (d *monotonicDeque) popFrontThrough(position uint64) {
+	for d.length > 0 && d.buf[d.head].position <= position {
+		d.head = (d.head + 1) % len(d.buf)
+		d.length--
+	}
+}
+
+func (d *monotonicDeque) front() float64 {
+	if d.length == 0 {
+		return 0
+	}
+	return d.buf[d.head].value
+}
+
+func (d *monotonicDeque) backIndex() int {
+	return (d.head + d.length - 1) % len(d.buf)
+}
@@ -0,0 +1,62 @@
+package peakdetect_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/MicahParks/peakdetect"
+)
+
+func TestNewMovingMinMax(t *testing.T) {
+	_, err := peakdetect.NewMovingMinMax(0)
+	if !errors.Is(err, peakdetect.ErrInvalidWindow) {
+		t.Fatalf("Invalid window did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidWindow, err)
+	}
+}
+
+func TestMovingMinMax_Next(t *testing.T) {
+	const window = 7
+
+	minMax, err := peakdetect.NewMovingMinMax(window)
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to create moving minimum and maximum.", err)
+	}
+
+	for i, v := range exampleInputs {
+		min, max := minMax.Next(v)
+
+		start := 0
+		if i+1 > window {
+			start = i + 1 - window
+		}
+		expectedMin, expectedMax := exampleInputs[start], exampleInputs[start]
+		for _, w := range exampleInputs[start : i+1] {
+			if w < expectedMin {
+				expectedMin = w
+			}
+			if w > expectedMax {
+				expectedMax = w
+			}
+		}
+
+		if min != expectedMin || max != expectedMax {
+			t.Fatalf("Window extrema did not match at index %d.\n  Expected: %f, %f\n  Actual: %f, %f", i, expectedMin, expectedMax, min, max)
+		}
+	}
+}
+
+func TestMovingMinMax_NextHeight(t *testing.T) {
+	minMax, err := peakdetect.NewMovingMinMax(3)
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to create moving minimum and maximum.", err)
+	}
+
+	data := []float64{5, 4, 6, 10, 7, 7}
+	expected := []float64{0, 0, 2, 6, 1, 0}
+	for i, v := range data {
+		height := minMax.NextHeight(v)
+		if height != expected[i] {
+			t.Fatalf("Height above floor did not match at index %d.\n  Expected: %f\n  Actual: %f", i, expected[i], height)
+		}
+	}
+}
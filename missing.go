@@ -0,0 +1,73 @@
+package peakdetect
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+const (
+	// MissingSkip skips missing values, leaving the state of the detector unchanged. This is the default.
+	MissingSkip MissingPolicy = iota
+	// MissingRepeat replaces a missing value with the previous value.
+	MissingRepeat
+	// MissingReject skips missing values like MissingSkip, but reports them with an error wrapping ErrMissingValue where
+	// an error can be returned.
+	MissingReject
+	// MissingNeutral treats a missing value as neutral. The moving mean is stored in its place, so the lag window still
+	// advances without the value moving the mean.
+	MissingNeutral
+)
+
+// ErrMissingValue indicates that a value is missing or is not a finite number.
+var ErrMissingValue = errors.New("the value is missing or not finite")
+
+// MissingPolicy is a set of enums that indicates how missing values, such as NaN, ±Inf, or SQL NULLs, are handled. A
+// single NaN given to the algorithm would otherwise poison the moving mean and standard deviation forever.
+type MissingPolicy uint8
+
+func (p *peakDetector) NextMaybe(value float64, present bool) (Signal, error) {
+	p.count++
+	if present && !math.IsNaN(value) && !math.IsInf(value, 0) {
+		return p.next(value), nil
+	}
+
+	switch p.config.Missing {
+	case MissingRepeat:
+		signal := p.next(p.prevInput)
+		p.last.missing = true
+		return signal, nil
+	case MissingNeutral:
+		p.advance()
+		p.last = lastValue{
+			center:    p.prevMean,
+			mean:      p.prevMean,
+			missing:   true,
+			processed: true,
+			stdDev:    math.Max(p.prevStdDev, p.stdDevFloor()),
+			threshold: p.config.Threshold,
+			value:     p.prevMean,
+		}
+		p.last.bound = p.last.threshold * p.last.stdDev
+		p.store(p.prevMean)
+		return SignalNeutral, nil
+	}
+
+	p.last = lastValue{
+		center:    p.prevMean,
+		mean:      p.prevMean,
+		missing:   true,
+		processed: true,
+		stdDev:    math.Max(p.prevStdDev, p.stdDevFloor()),
+		threshold: p.config.Threshold,
+		value:     value,
+	}
+	p.last.bound = p.last.threshold * p.last.stdDev
+	if p.config.Missing == MissingReject {
+		if !present {
+			return SignalNeutral, fmt.Errorf("the value is missing: %w", ErrMissingValue)
+		}
+		return SignalNeutral, fmt.Errorf("the value %f is not finite: %w", value, ErrMissingValue)
+	}
+	return SignalNeutral, nil
+}
@@ -0,0 +1,112 @@
+package peakdetect_test
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/MicahParks/peakdetect"
+)
+
+func newMissingDetector(t *testing.T, policy peakdetect.MissingPolicy) peakdetect.PeakDetector {
+	detector := peakdetect.NewPeakDetector()
+	err := detector.InitializeWithConfig(peakdetect.Config{
+		Influence: exampleInfluence,
+		Lag:       exampleLag,
+		Threshold: exampleThreshold,
+		Missing:   policy,
+	}, exampleInputs[:exampleLag])
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+	return detector
+}
+
+func TestPeakDetector_MissingSkip(t *testing.T) {
+	detector := newMissingDetector(t, peakdetect.MissingSkip)
+	window := detector.Window()
+
+	for _, value := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		signal := detector.Next(value)
+		if signal != peakdetect.SignalNeutral {
+			t.Fatalf("Non-finite value did not produce a neutral signal.\n  Expected: %d\n  Actual: %d", peakdetect.SignalNeutral, signal)
+		}
+		if !detector.Explain().Missing {
+			t.Fatalf("Non-finite value was not explained as missing.")
+		}
+	}
+	signal, err := detector.NextMaybe(1, false)
+	if err != nil {
+		t.Fatalf(logFmt, "Skipped value produced an error.", err)
+	}
+	if signal != peakdetect.SignalNeutral {
+		t.Fatalf("Missing value did not produce a neutral signal.\n  Expected: %d\n  Actual: %d", peakdetect.SignalNeutral, signal)
+	}
+
+	if !reflect.DeepEqual(window, detector.Window()) {
+		t.Fatalf("Skipped values changed the window.\n  Expected: %v\n  Actual: %v", window, detector.Window())
+	}
+
+	signals := detector.NextBatch(exampleInputs[exampleLag:])
+	if !reflect.DeepEqual(signals, exampleOutputs[exampleLag:]) {
+		t.Fatalf("Skipped values changed the signals.\n  Expected: %v\n  Actual: %v", exampleOutputs[exampleLag:], signals)
+	}
+}
+
+func TestPeakDetector_MissingReject(t *testing.T) {
+	detector := newMissingDetector(t, peakdetect.MissingReject)
+
+	_, err := detector.NextMaybe(math.NaN(), true)
+	if !errors.Is(err, peakdetect.ErrMissingValue) {
+		t.Fatalf("NaN value did not produce the correct error.\n  Expected: %s\n  Actual: %v", peakdetect.ErrMissingValue, err)
+	}
+	_, err = detector.NextMaybe(0, false)
+	if !errors.Is(err, peakdetect.ErrMissingValue) {
+		t.Fatalf("Missing value did not produce the correct error.\n  Expected: %s\n  Actual: %v", peakdetect.ErrMissingValue, err)
+	}
+
+	_, err = detector.NextMaybe(exampleInputs[exampleLag], true)
+	if err != nil {
+		t.Fatalf(logFmt, "Present value produced an error.", err)
+	}
+}
+
+func TestPeakDetector_MissingRepeat(t *testing.T) {
+	detector := newMissingDetector(t, peakdetect.MissingRepeat)
+	expected := newMissingDetector(t, peakdetect.MissingSkip)
+
+	previous := exampleInputs[exampleLag-1]
+	for _, value := range exampleInputs[exampleLag:] {
+		_, err := detector.NextMaybe(0, false)
+		if err != nil {
+			t.Fatalf(logFmt, "Repeated value produced an error.", err)
+		}
+		expected.Next(previous)
+		detector.Next(value)
+		expected.Next(value)
+		previous = value
+	}
+
+	if !reflect.DeepEqual(detector.Window(), expected.Window()) {
+		t.Fatalf("Repeated values did not match the previous values.\n  Expected: %v\n  Actual: %v", expected.Window(), detector.Window())
+	}
+}
+
+func TestPeakDetector_MissingNeutral(t *testing.T) {
+	detector := newMissingDetector(t, peakdetect.MissingNeutral)
+	mean := detector.Summary().Mean
+
+	signal, err := detector.NextMaybe(math.NaN(), true)
+	if err != nil {
+		t.Fatalf(logFmt, "Neutral value produced an error.", err)
+	}
+	if signal != peakdetect.SignalNeutral {
+		t.Fatalf("Missing value did not produce a neutral signal.\n  Expected: %d\n  Actual: %d", peakdetect.SignalNeutral, signal)
+	}
+
+	window := detector.Window()
+	if window[len(window)-1] != mean {
+		t.Fatalf("The mean was not stored in place of the missing value.\n  Expected: %f\n  Actual: %f", mean, window[len(window)-1])
+	}
+}
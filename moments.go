@@ -0,0 +1,119 @@
+package peakdetect
+
+import (
+	"fmt"
+	"math"
+)
+
+// Moments are the rolling statistics of a MovingMoments window.
+type Moments struct {
+	// Kurtosis is the population excess kurtosis, which is zero for normally distributed data.
+	Kurtosis float64
+	// KurtosisSignal is SignalPositive or SignalNegative when the kurtosis is beyond its bound.
+	KurtosisSignal Signal
+	Mean           float64
+	// Skewness is the population skewness.
+	Skewness float64
+	// SkewnessSignal is SignalPositive or SignalNegative when the skewness is beyond its bound.
+	SkewnessSignal Signal
+	// Variance is the population variance.
+	Variance float64
+}
+
+// MovingMoments tracks the first four moments of the most recent values in a sliding window, so the rolling skewness
+// and kurtosis can be monitored. Changes in the shape of the distribution precede many incidents before any change in
+// its mean does. Each update is O(1).
+type MovingMoments struct {
+	index         uint
+	kurtosisBound float64
+	shift         float64
+	skewnessBound float64
+	sums          [4]float64
+	values        []float64
+}
+
+// NewMovingMoments creates a new MovingMoments over the given window size. A meta-signal is produced when the magnitude
+// of the skewness or excess kurtosis exceeds its bound. A bound of zero disables its meta-signal.
+func NewMovingMoments(window uint, skewnessBound, kurtosisBound float64) (*MovingMoments, error) {
+	if window == 0 {
+		return nil, fmt.Errorf("the window size for moving moments must be greater than zero: %w", ErrInvalidWindow)
+	}
+	return &MovingMoments{
+		kurtosisBound: kurtosisBound,
+		skewnessBound: skewnessBound,
+		values:        make([]float64, 0, window),
+	}, nil
+}
+
+// Next adds the value to the window and returns the moments of the window, including the new value.
+//
+// The power sums of the window are shifted by the first value to reduce cancellation error.
+func (m *MovingMoments) Next(value float64) Moments {
+	if len(m.values) == 0 {
+		m.shift = value
+	}
+	if len(m.values) < cap(m.values) {
+		m.values = append(m.values, value)
+	} else {
+		m.add(m.values[m.index], -1)
+		m.values[m.index] = value
+		m.index++
+		if m.index == uint(len(m.values)) {
+			m.index = 0
+		}
+	}
+	m.add(value, 1)
+	return m.Moments()
+}
+
+// Moments returns the moments of the window.
+func (m *MovingMoments) Moments() Moments {
+	n := float64(len(m.values))
+	if n == 0 {
+		return Moments{}
+	}
+	mean := m.sums[0] / n
+	e2 := m.sums[1] / n
+	e3 := m.sums[2] / n
+	e4 := m.sums[3] / n
+
+	// Central moments from the raw moments of the shifted values.
+	m2 := math.Max(e2-mean*mean, 0)
+	m3 := e3 - 3*mean*e2 + 2*mean*mean*mean
+	m4 := e4 - 4*mean*e3 + 6*mean*mean*e2 - 3*mean*mean*mean*mean
+
+	moments := Moments{
+		Mean:     mean + m.shift,
+		Variance: m2,
+	}
+	if m2 > 0 {
+		moments.Skewness = m3 / math.Pow(m2, 1.5)
+		moments.Kurtosis = m4/(m2*m2) - 3
+	}
+	moments.SkewnessSignal = boundSignal(moments.Skewness, m.skewnessBound)
+	moments.KurtosisSignal = boundSignal(moments.Kurtosis, m.kurtosisBound)
+	return moments
+}
+
+// add adds the powers of the shifted value, multiplied by sign, to the power sums.
+func (m *MovingMoments) add(value, sign float64) {
+	d := value - m.shift
+	power := sign
+	for i := range m.sums {
+		power *= d
+		m.sums[i] += power
+	}
+}
+
+// boundSignal returns the signal for a statistic that has a bound on its magnitude. A bound of zero is disabled.
+func boundSignal(statistic, bound float64) Signal {
+	switch {
+	case bound == 0:
+		return SignalNeutral
+	case statistic > bound:
+		return SignalPositive
+	case statistic < -bound:
+		return SignalNegative
+	}
+	return SignalNeutral
+}
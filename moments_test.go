@@ -0,0 +1,82 @@
+package peakdetect_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/MicahParks/peakdetect"
+)
+
+func TestNewMovingMoments(t *testing.T) {
+	_, err := peakdetect.NewMovingMoments(0, 0, 0)
+	if !errors.Is(err, peakdetect.ErrInvalidWindow) {
+		t.Fatalf("Invalid window did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidWindow, err)
+	}
+}
+
+func TestMovingMoments_Next(t *testing.T) {
+	const window = 10
+
+	moments, err := peakdetect.NewMovingMoments(window, 1, 0)
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to create moving moments.", err)
+	}
+
+	data := []float64{1000, 1001, 1000, 1002, 1000, 1001, 1000, 1003, 1000, 1001, 1000, 1000, 1000, 1000, 1000, 1000, 1000, 1000, 1000, 1020}
+	var m peakdetect.Moments
+	for i, v := range data {
+		m = moments.Next(v)
+
+		start := 0
+		if i+1 > window {
+			start = i + 1 - window
+		}
+		expected := referenceMoments(data[start : i+1])
+		if math.Abs(m.Mean-expected.Mean) > 1e-9 || math.Abs(m.Variance-expected.Variance) > 1e-9 || math.Abs(m.Skewness-expected.Skewness) > 1e-6 || math.Abs(m.Kurtosis-expected.Kurtosis) > 1e-6 {
+			t.Fatalf("Moments did not match at index %d.\n  Expected: %+v\n  Actual: %+v", i, expected, m)
+		}
+	}
+
+	if m.SkewnessSignal != peakdetect.SignalPositive || m.KurtosisSignal != peakdetect.SignalNeutral {
+		t.Fatalf("Unexpected meta-signals for a right skewed window.\n  Actual: %+v", m)
+	}
+}
+
+func TestPeakDetector_TrackMoments(t *testing.T) {
+	detector := peakdetect.NewPeakDetector()
+	err := detector.InitializeWithConfig(peakdetect.Config{TrackMoments: true, Threshold: exampleThreshold}, exampleInputs[:exampleLag])
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+	detector.NextBatch(exampleInputs[exampleLag:])
+
+	summary := detector.Summary()
+	expected := referenceMoments(detector.Window())
+	if summary.Moments == nil || math.Abs(summary.Moments.Skewness-expected.Skewness) > 1e-6 {
+		t.Fatalf("Summary moments did not match.\n  Expected: %+v\n  Actual: %+v", expected, summary.Moments)
+	}
+}
+
+func referenceMoments(values []float64) peakdetect.Moments {
+	n := float64(len(values))
+	var mean float64
+	for _, v := range values {
+		mean += v
+	}
+	mean /= n
+
+	var m2, m3, m4 float64
+	for _, v := range values {
+		d := v - mean
+		m2 += d * d / n
+		m3 += d * d * d / n
+		m4 += d * d * d * d / n
+	}
+	m := peakdetect.Moments{Mean: mean, Variance: m2}
+	if m2 > 0 {
+		m.Skewness = m3 / math.Pow(m2, 1.5)
+		m.Kurtosis = m4/(m2*m2) - 3
+	}
+	return m
+}
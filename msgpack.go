@@ -0,0 +1,404 @@
+package peakdetect
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// ErrInvalidMsgpack indicates that the MessagePack data provided could not be decoded.
+var ErrInvalidMsgpack = errors.New("the MessagePack data provided is invalid")
+
+// MessagePack format bytes. See https://github.com/msgpack/msgpack/blob/master/spec.md.
+const (
+	msgpackNil      = 0xc0
+	msgpackFloat32  = 0xca
+	msgpackFloat64  = 0xcb
+	msgpackUint8    = 0xcc
+	msgpackUint16   = 0xcd
+	msgpackUint32   = 0xce
+	msgpackUint64   = 0xcf
+	msgpackInt8     = 0xd0
+	msgpackInt16    = 0xd1
+	msgpackInt32    = 0xd2
+	msgpackInt64    = 0xd3
+	msgpackFixArray = 0x90
+	msgpackFixMap   = 0x80
+	msgpackFixStr   = 0xa0
+	msgpackStr8     = 0xd9
+	msgpackStr16    = 0xda
+	msgpackStr32    = 0xdb
+	msgpackMap16    = 0xde
+	msgpackMap32    = 0xdf
+)
+
+// AppendMsgpack appends the MessagePack encoding of the event to dst and returns the extended buffer.
+//
+// The event is encoded as an array of its index, signal, timestamp, and value, instead of a map, to keep it compact.
+// The timestamp is encoded as nanoseconds since the Unix epoch, or nil if it is the zero value. Values that can be
+// represented exactly as a float32 are encoded as one. If the event has labels, they are appended to the array as a map
+// of strings, sorted by key.
+func (e SignalEvent) AppendMsgpack(dst []byte) []byte {
+	if len(e.Labels) == 0 {
+		dst = append(dst, msgpackFixArray|4)
+	} else {
+		dst = append(dst, msgpackFixArray|5)
+	}
+	dst = appendMsgpackUint(dst, e.Index)
+	dst = appendMsgpackInt(dst, int64(e.Signal))
+	if e.Time.IsZero() {
+		dst = append(dst, msgpackNil)
+	} else {
+		dst = appendMsgpackInt(dst, e.Time.UnixNano())
+	}
+	dst = appendMsgpackFloat(dst, e.Value)
+	if len(e.Labels) != 0 {
+		dst = appendMsgpackLabels(dst, e.Labels)
+	}
+	return dst
+}
+
+// MarshalMsgpack returns the MessagePack encoding of the event. See AppendMsgpack for the format.
+func (e SignalEvent) MarshalMsgpack() ([]byte, error) {
+	return e.AppendMsgpack(nil), nil
+}
+
+// UnmarshalMsgpack decodes the MessagePack encoding of an event created by MarshalMsgpack. Timestamps are decoded in
+// UTC.
+func (e *SignalEvent) UnmarshalMsgpack(data []byte) error {
+	d := msgpackDecoder{data: data}
+	length := d.arrayHeader(4, 5)
+	index := d.uint()
+	signal := d.int()
+	var t time.Time
+	if !d.nil() {
+		t = time.Unix(0, d.int()).UTC()
+	}
+	value := d.float()
+	var labels map[string]string
+	if length == 5 {
+		labels = d.labels()
+	}
+	if err := d.finish(); err != nil {
+		return err
+	}
+	*e = SignalEvent{
+		Index:  index,
+		Labels: labels,
+		Signal: Signal(signal),
+		Time:   t,
+		Value:  value,
+	}
+	return nil
+}
+
+// AppendMsgpack appends the MessagePack encoding of the event to dst and returns the extended buffer. The event is
+// encoded as an array of its index, pre-level, post-level, signal, and t statistic.
+func (e StepEvent) AppendMsgpack(dst []byte) []byte {
+	dst = append(dst, msgpackFixArray|5)
+	dst = appendMsgpackUint(dst, e.Index)
+	dst = appendMsgpackFloat(dst, e.PreLevel)
+	dst = appendMsgpackFloat(dst, e.PostLevel)
+	dst = appendMsgpackInt(dst, int64(e.Signal))
+	return appendMsgpackFloat(dst, e.T)
+}
+
+// MarshalMsgpack returns the MessagePack encoding of the event. See AppendMsgpack for the format.
+func (e StepEvent) MarshalMsgpack() ([]byte, error) {
+	return e.AppendMsgpack(nil), nil
+}
+
+// UnmarshalMsgpack decodes the MessagePack encoding of an event created by MarshalMsgpack.
+func (e *StepEvent) UnmarshalMsgpack(data []byte) error {
+	d := msgpackDecoder{data: data}
+	d.arrayHeader(5, 5)
+	event := StepEvent{
+		Index:     d.uint(),
+		PreLevel:  d.float(),
+		PostLevel: d.float(),
+		Signal:    Signal(d.int()),
+		T:         d.float(),
+	}
+	if err := d.finish(); err != nil {
+		return err
+	}
+	*e = event
+	return nil
+}
+
+func appendMsgpackUint(dst []byte, v uint64) []byte {
+	switch {
+	case v <= 0x7f:
+		return append(dst, byte(v))
+	case v <= math.MaxUint8:
+		return append(dst, msgpackUint8, byte(v))
+	case v <= math.MaxUint16:
+		return appendBigEndian(append(dst, msgpackUint16), uint64(uint16(v)), 2)
+	case v <= math.MaxUint32:
+		return appendBigEndian(append(dst, msgpackUint32), uint64(uint32(v)), 4)
+	default:
+		return appendBigEndian(append(dst, msgpackUint64), v, 8)
+	}
+}
+
+func appendMsgpackInt(dst []byte, v int64) []byte {
+	switch {
+	case v >= 0:
+		return appendMsgpackUint(dst, uint64(v))
+	case v >= -32:
+		return append(dst, byte(v))
+	case v >= math.MinInt8:
+		return append(dst, msgpackInt8, byte(v))
+	case v >= math.MinInt16:
+		return appendBigEndian(append(dst, msgpackInt16), uint64(uint16(v)), 2)
+	case v >= math.MinInt32:
+		return appendBigEndian(append(dst, msgpackInt32), uint64(uint32(v)), 4)
+	default:
+		return appendBigEndian(append(dst, msgpackInt64), uint64(v), 8)
+	}
+}
+
+func appendMsgpackFloat(dst []byte, v float64) []byte {
+	if f := float32(v); float64(f) == v {
+		return appendBigEndian(append(dst, msgpackFloat32), uint64(math.Float32bits(f)), 4)
+	}
+	return appendBigEndian(append(dst, msgpackFloat64), math.Float64bits(v), 8)
+}
+
+func appendMsgpackString(dst []byte, s string) []byte {
+	n := len(s)
+	switch {
+	case n <= 31:
+		dst = append(dst, msgpackFixStr|byte(n))
+	case n <= math.MaxUint8:
+		dst = append(dst, msgpackStr8, byte(n))
+	case n <= math.MaxUint16:
+		dst = appendBigEndian(append(dst, msgpackStr16), uint64(n), 2)
+	default:
+		dst = appendBigEndian(append(dst, msgpackStr32), uint64(n), 4)
+	}
+	return append(dst, s...)
+}
+
+func appendMsgpackLabels(dst []byte, labels map[string]string) []byte {
+	n := len(labels)
+	switch {
+	case n <= 15:
+		dst = append(dst, msgpackFixMap|byte(n))
+	case n <= math.MaxUint16:
+		dst = appendBigEndian(append(dst, msgpackMap16), uint64(n), 2)
+	default:
+		dst = appendBigEndian(append(dst, msgpackMap32), uint64(n), 4)
+	}
+	for _, key := range sortedKeys(labels) {
+		dst = appendMsgpackString(dst, key)
+		dst = appendMsgpackString(dst, labels[key])
+	}
+	return dst
+}
+
+// appendBigEndian appends the n least significant bytes of v to dst in big-endian order.
+func appendBigEndian(dst []byte, v uint64, n int) []byte {
+	for i := n - 1; i >= 0; i-- {
+		dst = append(dst, byte(v>>(8*uint(i))))
+	}
+	return dst
+}
+
+// msgpackDecoder decodes the subset of MessagePack used by this package. The first error is kept and all later reads
+// return zero values, so it only needs to be checked once by calling finish.
+type msgpackDecoder struct {
+	data []byte
+	err  error
+}
+
+// arrayHeader reads the header of an array with a length between min and max, inclusive, and returns the length.
+func (d *msgpackDecoder) arrayHeader(min, max byte) byte {
+	b := d.byte()
+	length := b &^ msgpackFixArray
+	if d.err == nil && (b&0xf0 != msgpackFixArray || length < min || length > max) {
+		d.fail("expected an array with a length from %d to %d", min, max)
+	}
+	return length
+}
+
+func (d *msgpackDecoder) labels() map[string]string {
+	b := d.byte()
+	if d.err != nil {
+		return nil
+	}
+	var n uint64
+	switch {
+	case b&0xf0 == msgpackFixMap:
+		n = uint64(b &^ msgpackFixMap)
+	case b == msgpackMap16:
+		n = uint64(d.uint16())
+	case b == msgpackMap32:
+		n = uint64(d.uint32())
+	default:
+		d.fail("expected a map")
+		return nil
+	}
+	// Each entry is at least two bytes, so a length that the remaining data cannot hold is rejected before allocating.
+	if d.err == nil && n > uint64(len(d.data))/2 {
+		d.fail("a map of %d entries is longer than the remaining %d bytes", n, len(d.data))
+	}
+	if d.err != nil {
+		return nil
+	}
+
+	labels := make(map[string]string, n)
+	for i := uint64(0); i < n && d.err == nil; i++ {
+		key := d.string()
+		labels[key] = d.string()
+	}
+	return labels
+}
+
+func (d *msgpackDecoder) string() string {
+	b := d.byte()
+	if d.err != nil {
+		return ""
+	}
+	var n uint64
+	switch {
+	case b&0xe0 == msgpackFixStr:
+		n = uint64(b &^ msgpackFixStr)
+	case b == msgpackStr8:
+		n = uint64(d.byte())
+	case b == msgpackStr16:
+		n = uint64(d.uint16())
+	case b == msgpackStr32:
+		n = uint64(d.uint32())
+	default:
+		d.fail("expected a string")
+		return ""
+	}
+	return string(d.next(n))
+}
+
+func (d *msgpackDecoder) nil() bool {
+	if d.err != nil || len(d.data) == 0 || d.data[0] != msgpackNil {
+		return false
+	}
+	d.data = d.data[1:]
+	return true
+}
+
+func (d *msgpackDecoder) uint() uint64 {
+	format := d.byte()
+	if d.err != nil {
+		return 0
+	}
+	switch {
+	case format <= 0x7f:
+		return uint64(format)
+	case format == msgpackUint8:
+		return uint64(d.byte())
+	case format == msgpackUint16:
+		return uint64(d.uint16())
+	case format == msgpackUint32:
+		return uint64(d.uint32())
+	case format == msgpackUint64:
+		return d.uint64()
+	}
+	d.fail("expected an unsigned integer")
+	return 0
+}
+
+func (d *msgpackDecoder) int() int64 {
+	if d.err != nil || len(d.data) == 0 {
+		d.next(1)
+		return 0
+	}
+	switch format := d.data[0]; {
+	case format >= 0xe0:
+		d.next(1)
+		return int64(int8(format))
+	case format == msgpackInt8:
+		d.next(1)
+		return int64(int8(d.byte()))
+	case format == msgpackInt16:
+		d.next(1)
+		return int64(int16(d.uint16()))
+	case format == msgpackInt32:
+		d.next(1)
+		return int64(int32(d.uint32()))
+	case format == msgpackInt64:
+		d.next(1)
+		return int64(d.uint64())
+	}
+	return int64(d.uint())
+}
+
+func (d *msgpackDecoder) float() float64 {
+	format := d.byte()
+	if d.err != nil {
+		return 0
+	}
+	switch format {
+	case msgpackFloat32:
+		return float64(math.Float32frombits(d.uint32()))
+	case msgpackFloat64:
+		return math.Float64frombits(d.uint64())
+	}
+	d.fail("expected a float")
+	return 0
+}
+
+func (d *msgpackDecoder) byte() byte {
+	if b := d.next(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (d *msgpackDecoder) uint16() uint16 {
+	if b := d.next(2); b != nil {
+		return binary.BigEndian.Uint16(b)
+	}
+	return 0
+}
+
+func (d *msgpackDecoder) uint32() uint32 {
+	if b := d.next(4); b != nil {
+		return binary.BigEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (d *msgpackDecoder) uint64() uint64 {
+	if b := d.next(8); b != nil {
+		return binary.BigEndian.Uint64(b)
+	}
+	return 0
+}
+
+// next consumes and returns the next n bytes. The length is checked against the remaining data before it is used, so
+// a corrupt length cannot cause a large allocation. nil is returned after an error.
+func (d *msgpackDecoder) next(n uint64) []byte {
+	if d.err == nil && n > uint64(len(d.data)) {
+		d.fail("unexpected end of data")
+	}
+	if d.err != nil {
+		return nil
+	}
+	b := d.data[:n]
+	d.data = d.data[n:]
+	return b
+}
+
+func (d *msgpackDecoder) fail(format string, args ...interface{}) {
+	if d.err == nil {
+		d.err = fmt.Errorf(format+": %w", append(args, ErrInvalidMsgpack)...)
+	}
+}
+
+func (d *msgpackDecoder) finish() error {
+	if d.err == nil && len(d.data) != 0 {
+		d.fail("unexpected %d trailing bytes", len(d.data))
+	}
+	return d.err
+}
@@ -0,0 +1,123 @@
+package peakdetect_test
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MicahParks/peakdetect"
+)
+
+func TestSignalEvent_MarshalMsgpack(t *testing.T) {
+	events := []peakdetect.SignalEvent{
+		{},
+		{Index: 5, Signal: peakdetect.SignalNegative, Value: -1.5},
+		{Index: 300, Signal: peakdetect.SignalPositive, Time: time.Date(2021, 1, 1, 0, 0, 0, 1, time.UTC), Value: 0.1},
+		{Index: math.MaxUint64, Signal: peakdetect.SignalNeutral, Time: time.Date(1960, 1, 1, 0, 0, 0, 0, time.UTC), Value: math.Inf(-1)},
+		{Index: 1, Labels: map[string]string{"series": "queue-depth", "host": strings.Repeat("a", 300)}, Value: 2},
+	}
+
+	for _, event := range events {
+		data, err := event.MarshalMsgpack()
+		if err != nil {
+			t.Fatalf(logFmt, "Failed to marshal event.", err)
+		}
+
+		var decoded peakdetect.SignalEvent
+		err = decoded.UnmarshalMsgpack(data)
+		if err != nil {
+			t.Fatalf(logFmt, "Failed to unmarshal event.", err)
+		}
+		if !reflect.DeepEqual(decoded, event) {
+			t.Fatalf("Decoded event did not match.\n  Expected: %+v\n  Actual: %+v", event, decoded)
+		}
+	}
+
+	data, _ := events[1].MarshalMsgpack()
+	if len(data) != 9 {
+		t.Fatalf("Unexpected encoded length.\n  Expected: %d\n  Actual: %d", 9, len(data))
+	}
+
+	var decoded peakdetect.SignalEvent
+	err := decoded.UnmarshalMsgpack(data[:len(data)-1])
+	if !errors.Is(err, peakdetect.ErrInvalidMsgpack) {
+		t.Fatalf("Truncated data did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidMsgpack, err)
+	}
+}
+
+func TestStepEvent_MarshalMsgpack(t *testing.T) {
+	event := peakdetect.StepEvent{Index: 70000, PreLevel: 1.1, PostLevel: 5, Signal: peakdetect.SignalPositive, T: 12.25}
+	data, err := event.MarshalMsgpack()
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to marshal event.", err)
+	}
+
+	var decoded peakdetect.StepEvent
+	err = decoded.UnmarshalMsgpack(data)
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to unmarshal event.", err)
+	}
+	if decoded != event {
+		t.Fatalf("Decoded event did not match.\n  Expected: %+v\n  Actual: %+v", event, decoded)
+	}
+
+	err = decoded.UnmarshalMsgpack(append(data, 0))
+	if !errors.Is(err, peakdetect.ErrInvalidMsgpack) {
+		t.Fatalf("Trailing data did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidMsgpack, err)
+	}
+}
+
+func TestSignalEvent_UnmarshalMsgpackTruncated(t *testing.T) {
+	event := peakdetect.SignalEvent{Index: 300, Labels: map[string]string{"series": strings.Repeat("a", 70000)}, Signal: peakdetect.SignalPositive, Time: time.Unix(1, 0).UTC(), Value: 0.1}
+	data, _ := event.MarshalMsgpack()
+	for i := range data {
+		var decoded peakdetect.SignalEvent
+		err := decoded.UnmarshalMsgpack(data[:i])
+		if !errors.Is(err, peakdetect.ErrInvalidMsgpack) {
+			t.Fatalf("Data truncated to %d bytes did not produce error.\n  Expected: %s\n  Actual: %s", i, peakdetect.ErrInvalidMsgpack, err)
+		}
+	}
+
+	for _, data := range [][]byte{
+		{0x95, 0x00, 0x00, 0xc0, 0xca, 0, 0, 0, 0, 0xdf, 0xff, 0xff, 0xff, 0xff},
+		{0x95, 0x00, 0x00, 0xc0, 0xca, 0, 0, 0, 0, 0x81, 0xdb, 0xff, 0xff, 0xff, 0xff},
+	} {
+		var decoded peakdetect.SignalEvent
+		allocs := testing.AllocsPerRun(1, func() {
+			err := decoded.UnmarshalMsgpack(data)
+			if !errors.Is(err, peakdetect.ErrInvalidMsgpack) {
+				t.Fatalf("Oversized length did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidMsgpack, err)
+			}
+		})
+		if allocs > 10 {
+			t.Fatalf("Oversized length allocated too many times.\n  Expected: at most %d\n  Actual: %.0f", 10, allocs)
+		}
+	}
+}
+
+func FuzzSignalEvent_UnmarshalMsgpack(f *testing.F) {
+	for _, event := range []peakdetect.SignalEvent{
+		{Index: 5, Signal: peakdetect.SignalNegative, Value: -1.5},
+		{Index: 1, Labels: map[string]string{"series": "queue-depth"}, Time: time.Unix(1, 0).UTC(), Value: 2},
+	} {
+		data, _ := event.MarshalMsgpack()
+		f.Add(data)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var decoded peakdetect.SignalEvent
+		err := decoded.UnmarshalMsgpack(data)
+		if err != nil {
+			return
+		}
+		encoded, _ := decoded.MarshalMsgpack()
+		var again peakdetect.SignalEvent
+		err = again.UnmarshalMsgpack(encoded)
+		if err != nil {
+			t.Fatalf(logFmt, "Failed to unmarshal re-encoded event.", err)
+		}
+	})
+}
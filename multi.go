@@ -0,0 +1,129 @@
+package peakdetect
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrChannelCount indicates that the number of channels in the input does not match the number of channels of the
+// MultiPeakDetector.
+var ErrChannelCount = errors.New("the number of channels does not match")
+
+// MultiPeakDetector runs an independent PeakDetector for each channel of multichannel data, such as each column of a
+// dataframe.
+type MultiPeakDetector struct {
+	detectors []PeakDetector
+}
+
+// NewMultiPeakDetector creates a MultiPeakDetector with one channel for each slice of initialValues, in columnar layout.
+// Every channel is initialized with the same cfg and its own initial values. See PeakDetector.InitializeWithConfig.
+func NewMultiPeakDetector(cfg Config, initialValues [][]float64) (*MultiPeakDetector, error) {
+	cfgs := make([]Config, len(initialValues))
+	for i := range cfgs {
+		cfgs[i] = cfg
+	}
+	return NewMultiPeakDetectorWithConfigs(cfgs, initialValues)
+}
+
+// NewMultiPeakDetectorWithConfigs creates a MultiPeakDetector like NewMultiPeakDetector, but channel i is initialized
+// with cfgs[i], so each channel can be tuned separately.
+func NewMultiPeakDetectorWithConfigs(cfgs []Config, initialValues [][]float64) (*MultiPeakDetector, error) {
+	if len(cfgs) != len(initialValues) {
+		return nil, fmt.Errorf("got %d configs for %d channels: %w", len(cfgs), len(initialValues), ErrChannelCount)
+	}
+	detectors := make([]PeakDetector, len(initialValues))
+	for i, values := range initialValues {
+		detector := NewPeakDetector()
+		err := detector.InitializeWithConfig(cfgs[i], values)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize channel %d: %w", i, err)
+		}
+		detectors[i] = detector
+	}
+	return &MultiPeakDetector{
+		detectors: detectors,
+	}, nil
+}
+
+// Channels returns the number of channels.
+func (m *MultiPeakDetector) Channels() int {
+	return len(m.detectors)
+}
+
+// Channel returns the PeakDetector for the channel at the given index.
+func (m *MultiPeakDetector) Channel(index int) PeakDetector {
+	return m.detectors[index]
+}
+
+// NextBatchColumns processes the next values of every channel. The input is in columnar layout, so columns[i] holds the
+// values of channel i. The signals are returned in the same layout. Each column may have a different length.
+func (m *MultiPeakDetector) NextBatchColumns(columns [][]float64) ([][]Signal, error) {
+	if len(columns) != len(m.detectors) {
+		return nil, fmt.Errorf("got %d columns for %d channels: %w", len(columns), len(m.detectors), ErrChannelCount)
+	}
+	signals := make([][]Signal, len(columns))
+	for i, column := range columns {
+		signals[i] = m.detectors[i].NextBatch(column)
+	}
+	return signals, nil
+}
+
+// NextBatchStrided processes the next rows values of every channel from a flat slice in columnar layout. Value j of
+// channel i is at values[i*stride+j], so stride must be at least rows and any padding between columns is skipped. The
+// signals are returned densely in the same layout, so the signal for value j of channel i is at index i*rows+j.
+func (m *MultiPeakDetector) NextBatchStrided(values []float64, rows, stride int) ([]Signal, error) {
+	channels := len(m.detectors)
+	if rows < 0 || stride < rows {
+		return nil, fmt.Errorf("the stride %d must be at least the number of rows %d: %w", stride, rows, ErrChannelCount)
+	}
+	if channels != 0 && len(values) < (channels-1)*stride+rows {
+		return nil, fmt.Errorf("%d values are too few for %d channels of %d rows with a stride of %d: %w", len(values), channels, rows, stride, ErrChannelCount)
+	}
+
+	signals := make([]Signal, channels*rows)
+	for i, detector := range m.detectors {
+		column := values[i*stride : i*stride+rows]
+		detector.NextBatchInto(signals[i*rows:i*rows:(i+1)*rows], column)
+	}
+	return signals, nil
+}
+
+// NextVector processes one value for every channel, such as one sample of each metric, where values[i] is the next
+// value of channel i. The signals are returned in the same order.
+func (m *MultiPeakDetector) NextVector(values []float64) ([]Signal, error) {
+	return m.NextVectorInto(make([]Signal, len(m.detectors)), values)
+}
+
+// NextVectorInto is NextVector without allocation. The signals are written to dst, which must have a length of at
+// least the number of channels, and the first signals of dst are returned.
+func (m *MultiPeakDetector) NextVectorInto(dst []Signal, values []float64) ([]Signal, error) {
+	if len(values) != len(m.detectors) {
+		return nil, fmt.Errorf("got %d values for %d channels: %w", len(values), len(m.detectors), ErrChannelCount)
+	}
+	if len(dst) < len(m.detectors) {
+		return nil, fmt.Errorf("the destination of length %d is too short for %d channels: %w", len(dst), len(m.detectors), ErrChannelCount)
+	}
+	dst = dst[:len(m.detectors)]
+	for i, detector := range m.detectors {
+		dst[i] = detector.Next(values[i])
+	}
+	return dst, nil
+}
+
+// Reconfigure applies the cfg to every channel, keeping their lag windows. See PeakDetector.Reconfigure. The cfg is
+// checked against every channel before it is applied, so no channel is changed if an error is returned.
+func (m *MultiPeakDetector) Reconfigure(cfg Config) error {
+	for i, detector := range m.detectors {
+		err := validateReconfigure(detector.Config(), cfg)
+		if err != nil {
+			return fmt.Errorf("failed to reconfigure channel %d: %w", i, err)
+		}
+	}
+	for i, detector := range m.detectors {
+		err := detector.Reconfigure(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to reconfigure channel %d: %w", i, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package peakdetect_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/MicahParks/peakdetect"
+)
+
+func newExampleMultiPeakDetector(t *testing.T) *peakdetect.MultiPeakDetector {
+	initial := make([]float64, exampleLag)
+	for i := range initial {
+		initial[i] = -exampleInputs[i]
+	}
+	detector, err := peakdetect.NewMultiPeakDetector(peakdetect.Config{
+		Influence: exampleInfluence,
+		Threshold: exampleThreshold,
+	}, [][]float64{exampleInputs[:exampleLag], initial})
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to create multi peak detector.", err)
+	}
+	return detector
+}
+
+func TestNewMultiPeakDetector(t *testing.T) {
+	_, err := peakdetect.NewMultiPeakDetector(peakdetect.Config{}, [][]float64{{1}, nil})
+	if !errors.Is(err, peakdetect.ErrInvalidInitialValues) {
+		t.Fatalf("Invalid initilization did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidInitialValues, err)
+	}
+}
+
+func TestMultiPeakDetector_NextBatchColumns(t *testing.T) {
+	detector := newExampleMultiPeakDetector(t)
+
+	inverted := make([]float64, len(exampleInputs)-exampleLag)
+	for i, v := range exampleInputs[exampleLag:] {
+		inverted[i] = -v
+	}
+
+	_, err := detector.NextBatchColumns([][]float64{inverted})
+	if !errors.Is(err, peakdetect.ErrChannelCount) {
+		t.Fatalf("Wrong number of columns did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrChannelCount, err)
+	}
+
+	signals, err := detector.NextBatchColumns([][]float64{exampleInputs[exampleLag:], inverted})
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to process columns.", err)
+	}
+	for i := range inverted {
+		expected := exampleOutputs[i+exampleLag]
+		if signals[0][i] != expected || signals[1][i] != -expected {
+			t.Fatalf("Column signals did not match example signal at index %d.\n  Example: %d\n  Actual: %d, %d", i+exampleLag, expected, signals[0][i], signals[1][i])
+		}
+	}
+}
+
+func TestMultiPeakDetector_NextBatchStrided(t *testing.T) {
+	detector := newExampleMultiPeakDetector(t)
+
+	const (
+		padding = 3
+		rows    = 20
+	)
+	stride := rows + padding
+	values := make([]float64, stride+rows)
+	for i := 0; i < rows; i++ {
+		values[i] = exampleInputs[exampleLag+i]
+		values[stride+i] = -exampleInputs[exampleLag+i]
+	}
+
+	_, err := detector.NextBatchStrided(values[:len(values)-1], rows, stride)
+	if !errors.Is(err, peakdetect.ErrChannelCount) {
+		t.Fatalf("Too few values did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrChannelCount, err)
+	}
+
+	signals, err := detector.NextBatchStrided(values, rows, stride)
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to process strided values.", err)
+	}
+	for i := 0; i < rows; i++ {
+		expected := exampleOutputs[i+exampleLag]
+		if signals[i] != expected || signals[rows+i] != -expected {
+			t.Fatalf("Strided signals did not match example signal at index %d.\n  Example: %d\n  Actual: %d, %d", i+exampleLag, expected, signals[i], signals[rows+i])
+		}
+	}
+}
+
+func TestMultiPeakDetector_NextVector(t *testing.T) {
+	detector := newExampleMultiPeakDetector(t)
+
+	_, err := detector.NextVector([]float64{1})
+	if !errors.Is(err, peakdetect.ErrChannelCount) {
+		t.Fatalf("Wrong number of values did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrChannelCount, err)
+	}
+
+	dst := make([]peakdetect.Signal, detector.Channels())
+	for i, v := range exampleInputs[exampleLag:] {
+		signals, err := detector.NextVectorInto(dst, []float64{v, -v})
+		if err != nil {
+			t.Fatalf(logFmt, "Failed to process vector.", err)
+		}
+		expected := exampleOutputs[i+exampleLag]
+		if signals[0] != expected || signals[1] != -expected {
+			t.Fatalf("Vector signals did not match example signal at index %d.\n  Example: %d\n  Actual: %v", i+exampleLag, expected, signals)
+		}
+	}
+}
+
+func TestNewMultiPeakDetectorWithConfigs(t *testing.T) {
+	_, err := peakdetect.NewMultiPeakDetectorWithConfigs([]peakdetect.Config{{}}, [][]float64{{1}, {1}})
+	if !errors.Is(err, peakdetect.ErrChannelCount) {
+		t.Fatalf("Wrong number of configs did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrChannelCount, err)
+	}
+
+	// The second channel only signals for large spikes.
+	detector, err := peakdetect.NewMultiPeakDetectorWithConfigs([]peakdetect.Config{
+		{Influence: exampleInfluence, Threshold: exampleThreshold},
+		{Influence: exampleInfluence, Threshold: 100},
+	}, [][]float64{exampleInputs[:exampleLag], exampleInputs[:exampleLag]})
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to create multi peak detector.", err)
+	}
+
+	signals, err := detector.NextVector([]float64{1.5, 1.5})
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to process vector.", err)
+	}
+	if signals[0] != peakdetect.SignalPositive || signals[1] != peakdetect.SignalNeutral {
+		t.Fatalf("Channels did not use their own configs.\n  Actual: %v", signals)
+	}
+
+	err = detector.Reconfigure(peakdetect.Config{Influence: exampleInfluence, Threshold: exampleThreshold})
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to reconfigure.", err)
+	}
+	if detector.Channel(1).Config().Threshold != exampleThreshold {
+		t.Fatalf("Shared tuning was not applied.\n  Actual: %+v", detector.Channel(1).Config())
+	}
+}
+
+func TestMultiPeakDetector_ReconfigureAtomic(t *testing.T) {
+	detector, err := peakdetect.NewMultiPeakDetector(peakdetect.Config{
+		Influence: exampleInfluence,
+		Threshold: exampleThreshold,
+	}, [][]float64{exampleInputs[:exampleLag], exampleInputs[:exampleLag+1]})
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to create multi peak detector.", err)
+	}
+
+	// The lag only matches the first channel.
+	err = detector.Reconfigure(peakdetect.Config{Influence: 1, Lag: exampleLag, Threshold: 1})
+	if !errors.Is(err, peakdetect.ErrInvalidConfig) {
+		t.Fatalf("Mismatched lag did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidConfig, err)
+	}
+	if detector.Channel(0).Config().Threshold != exampleThreshold {
+		t.Fatalf("A failed reconfiguration modified a channel.\n  Actual: %+v", detector.Channel(0).Config())
+	}
+}
+
+func TestMultiPeakDetector_NextBatchStridedBackfill(t *testing.T) {
+	cfg := peakdetect.Config{Influence: exampleInfluence, Persistence: 2, PersistenceBackfill: true, Threshold: exampleThreshold}
+	reference := peakdetect.NewPeakDetector()
+	err := reference.InitializeWithConfig(cfg, exampleInputs[:exampleLag])
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+	expected := reference.NextBatch(exampleInputs[exampleLag:])
+
+	detector, err := peakdetect.NewMultiPeakDetector(cfg, [][]float64{exampleInputs[:exampleLag], exampleInputs[:exampleLag]})
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to create multi peak detector.", err)
+	}
+	rows := len(exampleInputs) - exampleLag
+	values := append(append([]float64(nil), exampleInputs[exampleLag:]...), exampleInputs[exampleLag:]...)
+	signals, err := detector.NextBatchStrided(values, rows, rows)
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to process strided values.", err)
+	}
+	for i := 0; i < rows; i++ {
+		if signals[i] != expected[i] || signals[rows+i] != expected[i] {
+			t.Fatalf("Backfilled signal did not match at index %d.\n  Expected: %d\n  Actual: %d, %d", exampleLag+i, expected[i], signals[i], signals[rows+i])
+		}
+	}
+}
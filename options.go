@@ -0,0 +1,88 @@
+package peakdetect
+
+// Option changes a Config. Options are applied in order by NewFromOptions.
+type Option func(cfg *Config)
+
+// WithInfluence sets Config.Influence.
+func WithInfluence(influence float64) Option {
+	return func(cfg *Config) {
+		cfg.Influence = influence
+	}
+}
+
+// WithThreshold sets Config.Threshold.
+func WithThreshold(threshold float64) Option {
+	return func(cfg *Config) {
+		cfg.Threshold = threshold
+	}
+}
+
+// WithNegativeThreshold sets Config.NegativeThreshold.
+func WithNegativeThreshold(threshold float64) Option {
+	return func(cfg *Config) {
+		cfg.NegativeThreshold = threshold
+	}
+}
+
+// WithNegativeInfluence sets Config.NegativeInfluence.
+func WithNegativeInfluence(influence float64) Option {
+	return func(cfg *Config) {
+		cfg.NegativeInfluence = &influence
+	}
+}
+
+// WithDirection sets Config.Direction.
+func WithDirection(direction Direction) Option {
+	return func(cfg *Config) {
+		cfg.Direction = direction
+	}
+}
+
+// WithMinStdDev sets Config.MinStdDev.
+func WithMinStdDev(minStdDev float64) Option {
+	return func(cfg *Config) {
+		cfg.MinStdDev = minStdDev
+	}
+}
+
+// WithRefractoryPeriod sets Config.RefractoryPeriod.
+func WithRefractoryPeriod(refractoryPeriod uint) Option {
+	return func(cfg *Config) {
+		cfg.RefractoryPeriod = refractoryPeriod
+	}
+}
+
+// WithPersistence sets Config.Persistence and Config.PersistenceWindow.
+func WithPersistence(persistence, window uint) Option {
+	return func(cfg *Config) {
+		cfg.Persistence = persistence
+		cfg.PersistenceWindow = window
+	}
+}
+
+// WithPercentile sets Config.Percentile.
+func WithPercentile(percentile float64) Option {
+	return func(cfg *Config) {
+		cfg.Percentile = percentile
+	}
+}
+
+// NewFromConfig creates a new PeakDetector and initializes it with the Config. See PeakDetector.InitializeWithConfig.
+func NewFromConfig(cfg Config, initialValues []float64) (PeakDetector, error) {
+	detector := NewPeakDetector()
+	err := detector.InitializeWithConfig(cfg, initialValues)
+	if err != nil {
+		return nil, err
+	}
+	return detector, nil
+}
+
+// NewFromOptions creates a new PeakDetector and initializes it with the Config produced by applying the options to the
+// zero Config. See PeakDetector.InitializeWithConfig.
+func NewFromOptions(initialValues []float64, options ...Option) (PeakDetector, error) {
+	var cfg Config
+	for _, option := range options {
+		option(&cfg)
+	}
+	return NewFromConfig(cfg, initialValues)
+}
@@ -0,0 +1,72 @@
+package peakdetect_test
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/MicahParks/peakdetect"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	testCases := map[string]peakdetect.Config{
+		"InfluenceAboveOne":  {Influence: 1.5},
+		"InfluenceNegative":  {Influence: -0.1},
+		"InfluenceNaN":       {Influence: math.NaN()},
+		"ThresholdNegative":  {Threshold: -1},
+		"MinStdDevNegative":  {MinStdDev: -1},
+		"DeadbandNegative":   {Deadband: -1},
+		"QuantizationNaN":    {QuantizationStep: math.NaN()},
+		"SkewnessBoundBelow": {SkewnessBound: -1},
+		"PercentileMedian":   {Percentile: 50},
+		"PercentileAbove":    {Percentile: 101},
+		"PercentileRelease":  {Threshold: 3, Percentile: 99, ReleaseThreshold: 1},
+	}
+	for name, cfg := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := cfg.Validate()
+			if !errors.Is(err, peakdetect.ErrInvalidConfig) {
+				t.Fatalf("Invalid config did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidConfig, err)
+			}
+			_, err = peakdetect.NewFromConfig(cfg, exampleInputs[:exampleLag])
+			if !errors.Is(err, peakdetect.ErrInvalidConfig) {
+				t.Fatalf("Invalid config did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidConfig, err)
+			}
+		})
+	}
+
+	err := peakdetect.Config{Influence: 1, Threshold: 3.5}.Validate()
+	if err != nil {
+		t.Fatalf(logFmt, "Valid config produced error.", err)
+	}
+}
+
+func TestNewFromOptions(t *testing.T) {
+	detector, err := peakdetect.NewFromOptions(exampleInputs[:exampleLag],
+		peakdetect.WithInfluence(exampleInfluence),
+		peakdetect.WithThreshold(exampleThreshold),
+		peakdetect.WithDirection(peakdetect.DirectionPositive),
+		peakdetect.WithMinStdDev(0.01),
+		peakdetect.WithRefractoryPeriod(0),
+	)
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to create detector.", err)
+	}
+
+	expected := peakdetect.Config{
+		Direction: peakdetect.DirectionPositive,
+		Influence: exampleInfluence,
+		Lag:       exampleLag,
+		MinStdDev: 0.01,
+		Threshold: exampleThreshold,
+	}
+	if cfg := detector.Config(); !reflect.DeepEqual(cfg, expected) {
+		t.Fatalf("Incorrect configuration.\n  Expected: %+v\n  Actual: %+v", expected, cfg)
+	}
+
+	signals := detector.NextBatch(exampleInputs[exampleLag:])
+	if !reflect.DeepEqual(signals, exampleOutputs[exampleLag:]) {
+		t.Fatalf("Incorrect signals.\n  Expected: %v\n  Actual: %v", exampleOutputs[exampleLag:], signals)
+	}
+}
@@ -0,0 +1,75 @@
+package peakdetect
+
+import (
+	"math"
+)
+
+const (
+	// OutlierKeep keeps outliers in the initial values. This is the default.
+	OutlierKeep OutlierPolicy = iota
+	// OutlierReplace replaces outliers in the initial values with the median of the initial values, which excludes them
+	// from the starting statistics without changing the lag.
+	OutlierReplace
+	// OutlierWinsorize clamps outliers in the initial values to the boundary beyond which a value is an outlier, which is
+	// the median of the initial values plus or minus Config.InitialOutlierThreshold scaled median absolute deviations.
+	OutlierWinsorize
+)
+
+// defaultOutlierThreshold is the number of scaled median absolute deviations used when Config.InitialOutlierThreshold
+// is zero. It is the commonly recommended cutoff for the modified z-score.
+const defaultOutlierThreshold = 3.5
+
+// OutlierPolicy is a set of enums that indicates how outliers within the initial values are handled before the starting
+// mean and standard deviation are computed. A single spike in the initial values would otherwise miscalibrate the
+// detector until it leaves the lag window.
+type OutlierPolicy uint8
+
+// trimOutliers applies the policy to the values. Outliers are found with the modified z-score, which measures the
+// distance from the median in median absolute deviations, scaled to be consistent with the standard deviation of
+// normally distributed data. If the median absolute deviation is zero, the mean absolute deviation is used instead.
+//
+// The original values are not modified. The indices of the outliers are returned in ascending order.
+func trimOutliers(values []float64, policy OutlierPolicy, threshold float64) (trimmed []float64, outliers []int) {
+	if policy == OutlierKeep {
+		return values, nil
+	}
+	if threshold == 0 {
+		threshold = defaultOutlierThreshold
+	}
+
+	scratch := append([]float64(nil), values...)
+	median := medianSelect(scratch)
+	for i, v := range values {
+		scratch[i] = math.Abs(v - median)
+	}
+
+	// madScale and 1.2533 scale the median and mean absolute deviations to the standard deviation of a normal
+	// distribution.
+	spread := madScale * medianSelect(scratch)
+	if spread == 0 {
+		var sum float64
+		for _, deviation := range scratch {
+			sum += deviation
+		}
+		spread = 1.2533 * sum / float64(len(scratch))
+	}
+	if spread == 0 {
+		return values, nil
+	}
+	limit := threshold * spread
+
+	trimmed = append([]float64(nil), values...)
+	for i, v := range values {
+		if math.Abs(v-median) <= limit {
+			continue
+		}
+		outliers = append(outliers, i)
+		switch policy {
+		case OutlierReplace:
+			trimmed[i] = median
+		case OutlierWinsorize:
+			trimmed[i] = median + math.Copysign(limit, v-median)
+		}
+	}
+	return trimmed, outliers
+}
@@ -0,0 +1,54 @@
+package peakdetect_test
+
+import (
+	"testing"
+
+	"github.com/MicahParks/peakdetect"
+)
+
+func TestPeakDetector_InitialOutliers(t *testing.T) {
+	initial := append([]float64(nil), exampleInputs[:exampleLag]...)
+	initial[10] = 50
+
+	testCases := []struct {
+		policy   peakdetect.OutlierPolicy
+		outliers int
+		stored   float64
+	}{
+		{policy: peakdetect.OutlierKeep, outliers: 0, stored: 50},
+		{policy: peakdetect.OutlierReplace, outliers: 1, stored: 1},
+		{policy: peakdetect.OutlierWinsorize, outliers: 1},
+	}
+	for _, tc := range testCases {
+		detector := peakdetect.NewPeakDetector()
+		err := detector.InitializeWithConfig(peakdetect.Config{
+			Influence:       exampleInfluence,
+			InitialOutliers: tc.policy,
+			Threshold:       exampleThreshold,
+		}, initial)
+		if err != nil {
+			t.Fatalf(logFmt, "Error during initilization.", err)
+		}
+
+		outliers := detector.InitialOutliers()
+		if len(outliers) != tc.outliers || tc.outliers == 1 && outliers[0] != 10 {
+			t.Fatalf("Unexpected outliers for policy %d.\n  Actual: %v", tc.policy, outliers)
+		}
+		stored := detector.Window()[10]
+		if tc.policy == peakdetect.OutlierWinsorize {
+			if stored <= 1 || stored >= 50 {
+				t.Fatalf("Outlier was not winsorized.\n  Actual: %f", stored)
+			}
+		} else if stored != tc.stored {
+			t.Fatalf("Unexpected stored value for policy %d.\n  Expected: %f\n  Actual: %f", tc.policy, tc.stored, stored)
+		}
+
+		// Unless the outlier is replaced, the inflated standard deviation hides a peak while the outlier is in the lag
+		// window.
+		signals := detector.NextBatch([]float64{1, 3, 1})
+		detected := signals[1] == peakdetect.SignalPositive
+		if detected != (tc.policy == peakdetect.OutlierReplace) {
+			t.Fatalf("Unexpected detection of the first peak for policy %d.\n  Detected: %t", tc.policy, detected)
+		}
+	}
+}
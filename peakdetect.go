@@ -22,14 +22,30 @@ type Signal int8
 var ErrInvalidInitialValues = errors.New("the initial values provided are invalid")
 
 type peakDetector struct {
-	index            uint
-	influence        float64
-	lag              uint
-	movingMeanStdDev *movingMeanStdDev
-	prevMean         float64
-	prevStdDev       float64
-	prevValue        float64
-	threshold        float64
+	active              Signal
+	config              Config
+	count               uint64
+	differencer         *differencer
+	handlers            []signalHandler
+	histogram           *Histogram
+	index               uint
+	initialOutliers     []int
+	labels              map[string]string
+	last                lastValue
+	metrics             Metrics
+	moments             *MovingMoments
+	baseline            baseline
+	movingMinMax        *MovingMinMax
+	persistence         []persistenceEntry
+	preprocessor        Preprocessor
+	pendingPreprocessor Preprocessor
+	preprocessorPending bool
+	prevInput           float64
+	prevMean            float64
+	prevStdDev          float64
+	prevValue           float64
+	quantiles           *sortedWindow
+	refractory          uint
 }
 
 // PeakDetector detects peaks in realtime timeseries data using z-scores.
@@ -71,116 +87,628 @@ type PeakDetector interface {
 	// the algorithm to adapt to these trends. I.e., if you put lag at 10, it takes 10 'periods' before the algorithm's
 	// threshold is adjusted to any systematic changes in the long-term average. So choose the lag parameter based on
 	// the trending behavior of your data and how adaptive you want the algorithm to be.
+	//
+	// influence must be in the range [0, 1] and threshold must not be negative.
 	Initialize(influence, threshold float64, initialValues []float64) error
+	// InitializeWithConfig initializes the PeakDetector with a Config that may include optional behavior. It is
+	// otherwise the same as Initialize. The length of the initialValues is the lag, so cfg.Lag must either be zero or
+	// equal to it. If cfg.DerivativeOrder is set, the lag is that many fewer than the length of the initialValues. The
+	// cfg must pass Config.Validate.
+	InitializeWithConfig(cfg Config, initialValues []float64) error
+	// InitializeFromStats initializes the PeakDetector from the mean and population standard deviation of lag values
+	// computed elsewhere, such as a SQL aggregate over last week, instead of the values themselves. The lag window is
+	// filled with synthetic values that have exactly those statistics, which are replaced as values are processed. For a
+	// robust PeakDetector, the mean and stdDev are the median and scaled median absolute deviation. A Config.Baseline
+	// estimates its center and spread from the synthetic values. The statistics are of the values after any
+	// Preprocessor, which is Reset. cfg.Lag must either be zero or equal to the lag, and cfg.DerivativeOrder must be
+	// zero. The mean must be finite, the stdDev must be finite and not negative, and a lag of one requires a stdDev of
+	// zero. See Stats.
+	InitializeFromStats(mean, stdDev float64, lag uint, cfg Config) error
+	// Stats returns the moving mean and population standard deviation of the lag window that the next value is compared
+	// to, along with the lag, so they can be given to InitializeFromStats. The standard deviation does not include the
+	// floors from Config.MinStdDev and Config.QuantizationStep.
+	Stats() (mean, stdDev float64, lag uint)
+	// InitializeAndDetect initializes the PeakDetector with the first values and returns the signals for all of the
+	// values, like the reference implementation of the algorithm does for a complete series. The first cfg.Lag values,
+	// plus cfg.DerivativeOrder, are used for initialization and their signals are neutral, so the signals line up with
+	// the values. cfg.Lag must be greater than zero. The PeakDetector can continue to process values afterward.
+	InitializeAndDetect(cfg Config, values []float64) ([]Signal, error)
+	// InitialOutliers returns the indices of the initial values that were trimmed according to Config.InitialOutliers
+	// during the last initialization.
+	InitialOutliers() []int
 	// Next processes the next value and determines its signal.
 	Next(value float64) Signal
+	// NextMaybe processes the next value, which may be explicitly missing, and determines its signal. A value that is
+	// not present, NaN, or ±Inf is handled according to Config.Missing. An error wrapping ErrMissingValue is only
+	// returned for MissingReject. Next handles such values the same way, without the error.
+	NextMaybe(value float64, present bool) (Signal, error)
 	// NextBatch processes the next values and determines their signals. Their signals will be returned in a slice equal
 	// to the length of the input.
 	NextBatch(values []float64) []Signal
+	// NextDetailed processes the next value like Next, but returns a Result with the statistics used to determine its
+	// signal.
+	NextDetailed(value float64) Result
+	// NextBatchInto processes the next values like NextBatch, but appends their signals to dst[:0] and returns the
+	// result. Reusing dst across calls avoids allocation. It is faster than calling Next for each value, unless the
+	// PeakDetector is robust or uses a Baseline, DerivativeOrder, a Histogram, Metrics, a Preprocessor, TrackMoments,
+	// ReleaseThreshold, Percentile, RefractoryPeriod, Persistence, or Direction.
+	NextBatchInto(dst []Signal, values []float64) []Signal
+	// NextBatchDetailed processes the next values like NextBatch, but returns a Result for each value.
+	NextBatchDetailed(values []float64) []Result
+	// Config returns the configuration of the PeakDetector. Its Lag is the length of the initial values.
+	Config() Config
+	// Reconfigure applies a new configuration while keeping the lag window and its statistics, so parameters can be
+	// tuned on a running PeakDetector without a new warm up period. The lag cannot be changed, so cfg.Lag must either be
+	// zero or equal the current lag. Neither can Config.DerivativeOrder. Options that only apply to initialization, such
+	// as Config.InitialOutliers and Config.Baseline, take effect at the next initialization. The PeakDetector must be
+	// initialized first.
+	Reconfigure(cfg Config) error
+	// SetInfluence changes the influence of a running PeakDetector with Reconfigure, so the new configuration must pass
+	// the same validation.
+	SetInfluence(influence float64) error
+	// SetThreshold changes the threshold of a running PeakDetector with Reconfigure, so the new configuration must pass
+	// the same validation.
+	SetThreshold(threshold float64) error
+	// Evaluate runs the algorithm with the given configuration over the values currently retained in the lag window,
+	// without modifying the state of the PeakDetector. The first cfg.Lag values of the window are used for
+	// initialization and the signals for the remaining values are returned, so cfg.Lag must not exceed the lag of the
+	// PeakDetector. The retained values are the values stored by the algorithm, which are influence adjusted for
+	// signals. Every option of the cfg applies, except Config.DerivativeOrder, as the retained values are already
+	// differenced. If cfg.Baseline is nil, the baseline of the PeakDetector is used.
+	Evaluate(cfg Config) ([]Signal, error)
+	// Window returns a copy of the values currently retained in the lag window in chronological order. The retained
+	// values are the values stored by the algorithm, which are influence adjusted for signals.
+	Window() []float64
+	// Summary returns summary statistics of the values currently retained in the lag window. The zero value, apart from
+	// the Histogram, is returned if the PeakDetector is not initialized.
+	Summary() WindowSummary
+	// SetHistogram sets a Histogram that every value processed by Next is added to. Its snapshot is included in the
+	// Summary. Use nil to stop tracking a histogram. The Histogram is kept across initializations.
+	SetHistogram(h *Histogram)
+	// SetMetrics sets the Metrics that observe every value processed. Use nil to stop observing. The Metrics are kept
+	// across initializations and Reset, but not by Clone.
+	SetMetrics(m Metrics)
+	// SetPreprocessor sets a Preprocessor that transforms every value, including the initial values, before it is
+	// differenced for Config.DerivativeOrder and detected. Use nil to stop preprocessing. It takes effect at the next
+	// initialization, where it is Reset first. The Preprocessor is kept across initializations and Reset, is cloned by
+	// Clone, and is not part of the marshaled state.
+	SetPreprocessor(preprocessor Preprocessor)
+	// SetLabels attaches key/value labels, such as a series name, to the PeakDetector. They are propagated onto its
+	// outputs, such as explanations, events, encoded records, and log lines. The map is shared rather than copied, so it
+	// must not be modified after it is set. The labels are kept across initializations.
+	SetLabels(labels map[string]string)
+	// Labels returns the labels attached to the PeakDetector. The returned map must not be modified.
+	Labels() map[string]string
+	// MemoryFootprint returns the approximate number of bytes used by the PeakDetector, including its lag window, the
+	// values tracked for Config.Persistence, its labels, and any Histogram that has been set. The Preprocessor, the
+	// handlers given to OnSignal, and a custom Config.Baseline are not included, as their sizes are not known.
+	MemoryFootprint() uintptr
+	// MarshalBinary encodes the state of the PeakDetector, including its configuration, labels, and lag window, so a
+	// long-running detector can be restored without a new warm up period. The Histogram and the most recent Explanation
+	// are not included. A PeakDetector that uses a Config.Baseline cannot be encoded. It implements
+	// encoding.BinaryMarshaler.
+	MarshalBinary() ([]byte, error)
+	// UnmarshalBinary restores the state of the PeakDetector from the output of MarshalBinary. The PeakDetector does not
+	// need to be initialized first. It implements encoding.BinaryUnmarshaler.
+	UnmarshalBinary(data []byte) error
+	// MarshalJSON encodes the same state as MarshalBinary as JSON. It implements json.Marshaler.
+	MarshalJSON() ([]byte, error)
+	// UnmarshalJSON restores the state of the PeakDetector from the output of MarshalJSON. It implements
+	// json.Unmarshaler.
+	UnmarshalJSON(data []byte) error
+	// Reset returns the PeakDetector to its uninitialized state, dropping the lag window and configuration. The labels
+	// and Histogram are kept, as with initialization. It must be initialized again before use.
+	Reset()
+	// Clone returns an independent deep copy of the PeakDetector, including its lag window, so a warmed up detector can
+	// be duplicated, such as to compare two thresholds on the same live stream. The labels are shared, as they must not
+	// be modified, and the Histogram is copied. The handlers registered with OnSignal and the Metrics are not copied.
+	Clone() PeakDetector
+	// OnSignal registers a handler that is called with an event for every signal in the direction, such as to alert,
+	// log, or increment a metric without wrapping every call to Next. Use DirectionBoth for every signal. Multiple
+	// handlers are called in the order they were registered. The handlers are called synchronously by the method that
+	// processed the value, after the state is updated, so they must not call the PeakDetector. The Index of an event
+	// counts every value given to the PeakDetector since initialization, starting at zero, and its Time is the zero
+	// value. The handlers are kept across initializations and Reset, but not by Clone.
+	OnSignal(direction Direction, handler func(event SignalEvent))
+	// Explain describes how the signal for the most recently processed value was determined. The zero value is
+	// returned if no values have been processed since initialization.
+	Explain() Explanation
 }
 
 // NewPeakDetector creates a new PeakDetector. It must be initialized before use.
 func NewPeakDetector() PeakDetector {
 	return &peakDetector{
-		movingMeanStdDev: &movingMeanStdDev{},
+		baseline: &SlidingStats{},
 	}
 }
 
 func (p *peakDetector) Initialize(influence, threshold float64, initialValues []float64) error {
-	p.lag = uint(len(initialValues))
-	if p.lag == 0 {
+	return p.InitializeWithConfig(Config{
+		Influence: influence,
+		Threshold: threshold,
+	}, initialValues)
+}
+
+func (p *peakDetector) InitializeWithConfig(cfg Config, initialValues []float64) error {
+	if len(initialValues) == 0 {
 		return fmt.Errorf("the length of the initial values is zero, the length is used as the lag for the algorithm: %w", ErrInvalidInitialValues)
 	}
-	p.influence = influence
-	p.threshold = threshold
+	if uint(len(initialValues)) <= cfg.DerivativeOrder {
+		return fmt.Errorf("%d initial values are too few for a lag of at least one with a derivative order of %d: %w", len(initialValues), cfg.DerivativeOrder, ErrInvalidInitialValues)
+	}
+	for _, v := range initialValues {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("the initial values must be finite: %w", ErrInvalidInitialValues)
+		}
+	}
+	p.applyPendingPreprocessor()
+	p.prevInput = initialValues[len(initialValues)-1]
+	if p.preprocessor != nil {
+		p.preprocessor.Reset()
+		preprocessed := make([]float64, len(initialValues))
+		for i, v := range initialValues {
+			preprocessed[i] = p.preprocessor.Next(v)
+		}
+		initialValues = preprocessed
+	}
+	p.differencer = nil
+	if cfg.DerivativeOrder > 0 {
+		p.differencer = newDifferencer(cfg.DerivativeOrder)
+		for _, v := range initialValues[:cfg.DerivativeOrder] {
+			p.differencer.next(v)
+		}
+		derivatives := make([]float64, 0, uint(len(initialValues))-cfg.DerivativeOrder)
+		for _, v := range initialValues[cfg.DerivativeOrder:] {
+			derivative, _ := p.differencer.next(v)
+			derivatives = append(derivatives, derivative)
+		}
+		initialValues = derivatives
+	}
+
+	lag := uint(len(initialValues))
+	if cfg.Lag != 0 && cfg.Lag != lag {
+		return fmt.Errorf("the lag %d does not match the length of the initial values %d: %w", cfg.Lag, lag, ErrInvalidInitialValues)
+	}
+	err := cfg.Validate()
+	if err != nil {
+		return err
+	}
+	cfg.Lag = lag
+	p.config = cfg
+	p.useBaseline(cfg.Baseline)
+	initialValues, p.initialOutliers = trimOutliers(initialValues, cfg.InitialOutliers, cfg.InitialOutlierThreshold)
+
+	p.prevMean, p.prevStdDev = p.baseline.initialize(initialValues)
+	p.setResyncInterval()
+	p.prevValue = initialValues[lag-1]
+	p.active = SignalNeutral
+	p.count = 0
+	p.index = 0
+	p.last = lastValue{}
+	p.persistence = nil
+	p.refractory = 0
+	p.setQuantiles()
 
-	p.prevMean, p.prevStdDev = p.movingMeanStdDev.initialize(initialValues)
-	p.prevValue = initialValues[p.lag-1]
+	p.movingMinMax = newMovingMinMax(lag)
+	p.moments = nil
+	if cfg.TrackMoments {
+		p.moments, _ = NewMovingMoments(lag, cfg.SkewnessBound, cfg.KurtosisBound)
+	}
+	for _, v := range initialValues {
+		p.movingMinMax.Next(v)
+		if p.moments != nil {
+			p.moments.Next(v)
+		}
+	}
 
 	return nil
 }
 
-func (p *peakDetector) Next(value float64) (signal Signal) {
-	p.index++
-	if p.index == p.lag {
-		p.index = 0
+func (p *peakDetector) InitializeAndDetect(cfg Config, values []float64) ([]Signal, error) {
+	initial := cfg.Lag + cfg.DerivativeOrder
+	if cfg.Lag == 0 || initial > uint(len(values)) {
+		return nil, fmt.Errorf("the lag %d must be greater than zero and, with the derivative order %d, at most the length of the values %d: %w", cfg.Lag, cfg.DerivativeOrder, len(values), ErrInvalidConfig)
+	}
+	err := p.InitializeWithConfig(cfg, values[:initial])
+	if err != nil {
+		return nil, err
 	}
 
-	if math.Abs(value-p.prevMean) > p.threshold*p.prevStdDev {
-		if value > p.prevMean {
-			signal = SignalPositive
+	signals := make([]Signal, len(values))
+	for i, v := range values[initial:] {
+		signals[int(initial)+i] = p.Next(v)
+		p.backfill(signals[initial : int(initial)+i+1])
+	}
+	return signals, nil
+}
+
+func (p *peakDetector) Reset() {
+	p.applyPendingPreprocessor()
+	p.baseline.reset()
+	*p = peakDetector{
+		baseline:     p.baseline,
+		handlers:     p.handlers,
+		histogram:    p.histogram,
+		labels:       p.labels,
+		metrics:      p.metrics,
+		preprocessor: p.preprocessor,
+	}
+}
+
+func (p *peakDetector) Clone() PeakDetector {
+	clone := *p
+	clone.baseline = p.baseline.clone()
+	clone.handlers = nil
+	clone.metrics = nil
+	clone.initialOutliers = append([]int(nil), p.initialOutliers...)
+	if p.differencer != nil {
+		clone.differencer = &differencer{
+			prev: append([]float64(nil), p.differencer.prev...),
+			seen: p.differencer.seen,
+		}
+	}
+	if p.histogram != nil {
+		clone.histogram = p.histogram.clone()
+	}
+	clone.persistence = append([]persistenceEntry(nil), p.persistence...)
+	if p.quantiles != nil {
+		quantiles := p.quantiles.clone()
+		clone.quantiles = &quantiles
+	}
+	if p.preprocessor != nil {
+		clone.preprocessor = p.preprocessor.Clone()
+	}
+	if p.pendingPreprocessor != nil {
+		clone.pendingPreprocessor = p.pendingPreprocessor.Clone()
+	}
+	if p.moments != nil {
+		moments := *p.moments
+		moments.values = append(make([]float64, 0, cap(p.moments.values)), p.moments.values...)
+		clone.moments = &moments
+	}
+	if p.movingMinMax != nil {
+		clone.movingMinMax = p.movingMinMax.clone()
+	}
+	return &clone
+}
+
+// stdDevFloor returns the minimum standard deviation used to determine signals.
+func (p *peakDetector) stdDevFloor() float64 {
+	return math.Max(p.config.MinStdDev, p.config.QuantizationStep/math.Sqrt(12))
+}
+
+func (p *peakDetector) InitialOutliers() []int {
+	return append([]int(nil), p.initialOutliers...)
+}
+
+func (p *peakDetector) Next(value float64) Signal {
+	signal, _ := p.NextMaybe(value, true)
+	return signal
+}
+
+// next processes a value that is present and finite.
+func (p *peakDetector) next(value float64) (signal Signal) {
+	p.prevInput = value
+	if p.preprocessor != nil {
+		value = p.preprocessor.Next(value)
+	}
+	if p.differencer != nil {
+		value, _ = p.differencer.next(value)
+	}
+
+	p.advance()
+
+	if p.histogram != nil {
+		p.histogram.Next(value)
+	}
+
+	p.last = lastValue{
+		center:      p.prevMean,
+		lowVariance: p.prevStdDev < p.config.MinCoefficientOfVariation*math.Abs(p.prevMean),
+		mean:        p.prevMean,
+		processed:   true,
+		refractory:  p.refractory > 0,
+		stdDev:      math.Max(p.prevStdDev, p.stdDevFloor()),
+		value:       value,
+	}
+	if p.last.refractory {
+		p.refractory--
+	}
+	if p.quantiles != nil {
+		p.last.center = p.quantiles.quantile(0.5)
+	}
+
+	side := SignalNegative
+	if value > p.last.center {
+		side = SignalPositive
+	}
+	threshold, influence := p.config.sided(side)
+	p.last.threshold = threshold
+	if p.config.ReleaseThreshold != 0 && p.active == side {
+		p.last.threshold = p.config.ReleaseThreshold
+	}
+	p.last.bound = p.last.threshold * p.last.stdDev
+	if p.quantiles != nil {
+		p.last.bound = p.percentileBound(side)
+		p.last.threshold = zScore(p.last.bound, p.last.stdDev)
+	}
+
+	deviation := math.Abs(value - p.last.center)
+	p.active = SignalNeutral
+	if !p.last.lowVariance && math.Abs(value-p.prevMean) > p.config.Deadband && deviation > p.last.bound {
+		signal = side
+		value = influence*value + (1-influence)*p.prevValue
+		p.last.persisted = p.persist(side)
+		if p.last.persisted {
+			p.active = side
+		}
+		if !p.last.persisted || p.last.refractory || !p.config.Direction.allows(signal) {
+			signal = SignalNeutral
 		} else {
-			signal = SignalNegative
+			p.refractory = p.config.RefractoryPeriod
 		}
-		value = p.influence*value + (1-p.influence)*p.prevValue
 	} else {
+		p.persist(SignalNeutral)
 		signal = SignalNeutral
 	}
 
-	p.prevMean, p.prevStdDev = p.movingMeanStdDev.next(value)
-	p.prevValue = value
+	p.store(value)
+	p.last.signal = signal
+	p.confirm(signal)
+	if signal != SignalNeutral && len(p.handlers) != 0 {
+		p.emit(p.count-1, signal, p.prevInput)
+	}
+	if p.metrics != nil {
+		p.metrics.Observe(p.labels, p.result(p.prevInput))
+	}
 
 	return signal
 }
 
-func (p *peakDetector) NextBatch(values []float64) []Signal {
-	signals := make([]Signal, len(values))
-	for i, v := range values {
-		signals[i] = p.Next(v)
+// advance advances the position in the lag window.
+func (p *peakDetector) advance() {
+	p.index++
+	if p.index == p.config.Lag {
+		p.index = 0
 	}
-	return signals
 }
 
-// meanStdDev determines the mean and population standard deviation for the given population.
-type movingMeanStdDev struct {
-	cache        []float64
-	cacheLen     float64
-	cacheLenU    uint
-	index        uint
-	prevMean     float64
-	prevVariance float64
+// store adds the value to the lag window and the statistics that follow it.
+func (p *peakDetector) store(value float64) {
+	p.prevMean, p.prevStdDev = p.baseline.next(value)
+	p.movingMinMax.Next(value)
+	if p.moments != nil {
+		p.moments.Next(value)
+	}
+	if p.quantiles != nil {
+		p.quantiles.next(value)
+	}
+	p.prevValue = value
 }
 
-// initialize creates the needed assets for the movingMeanStdDev. It also computes the resulting mean and population
-// standard deviation using Welford's method.
-//
-// https://www.johndcook.com/blog/standard_deviation/
-func (m *movingMeanStdDev) initialize(initialValues []float64) (mean, stdDev float64) {
-	m.cacheLenU = uint(len(initialValues))
-	m.cacheLen = float64(m.cacheLenU)
-	m.cache = make([]float64, m.cacheLenU)
-	copy(m.cache, initialValues)
-
-	mean = initialValues[0]
-	prevMean := mean
-	var sumOfSquares float64
-	for i := uint(2); i <= m.cacheLenU; i++ {
-		value := initialValues[i-1]
-		mean = prevMean + (value-prevMean)/float64(i)
-		sumOfSquares = sumOfSquares + (value-prevMean)*(value-mean)
-		prevMean = mean
-	}
-
-	m.prevMean = mean
-	m.prevVariance = sumOfSquares / m.cacheLen
-	return mean, math.Sqrt(m.prevVariance)
-}
-
-// Next computes the next mean and population standard deviation. It uses a sliding window and is based on Welford's
-// method.
-//
-// https://stackoverflow.com/a/14638138/14797322
-func (m *movingMeanStdDev) next(value float64) (mean, stdDev float64) {
-	outOfWindow := m.cache[m.index]
-	m.cache[m.index] = value
-	m.index++
-	if m.index == m.cacheLenU {
-		m.index = 0
+func (p *peakDetector) Config() Config {
+	return p.config
+}
+
+func (p *peakDetector) Reconfigure(cfg Config) error {
+	err := validateReconfigure(p.config, cfg)
+	if err != nil {
+		return err
+	}
+	lag := p.config.Lag
+	cfg.Lag = lag
+
+	rebuildMoments := cfg.TrackMoments != p.config.TrackMoments || cfg.SkewnessBound != p.config.SkewnessBound || cfg.KurtosisBound != p.config.KurtosisBound
+	rebuildQuantiles := (cfg.Percentile == 0) != (p.config.Percentile == 0)
+	p.config = cfg
+	p.setResyncInterval()
+	if rebuildQuantiles {
+		p.setQuantiles()
+	}
+	if p.refractory > cfg.RefractoryPeriod {
+		p.refractory = cfg.RefractoryPeriod
+	}
+	if window := cfg.persistenceWindow(); cfg.Persistence <= 1 {
+		p.persistence = nil
+	} else if uint(len(p.persistence)) > window {
+		p.persistence = append([]persistenceEntry(nil), p.persistence[uint(len(p.persistence))-window:]...)
+	}
+	if rebuildMoments {
+		p.moments = nil
+		if cfg.TrackMoments {
+			p.moments, _ = NewMovingMoments(lag, cfg.SkewnessBound, cfg.KurtosisBound)
+			for _, v := range p.Window() {
+				p.moments.Next(v)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateReconfigure checks that a PeakDetector running the current Config can be reconfigured with the cfg.
+func validateReconfigure(current, cfg Config) error {
+	if current.Lag == 0 {
+		return fmt.Errorf("the detector must be initialized before it is reconfigured: %w", ErrInvalidConfig)
+	}
+	if cfg.Lag != 0 && cfg.Lag != current.Lag {
+		return fmt.Errorf("the lag %d does not match the current lag %d: %w", cfg.Lag, current.Lag, ErrInvalidConfig)
+	}
+	if cfg.DerivativeOrder != current.DerivativeOrder {
+		return fmt.Errorf("the derivative order %d does not match the current derivative order %d: %w", cfg.DerivativeOrder, current.DerivativeOrder, ErrInvalidConfig)
 	}
+	return cfg.Validate()
+}
 
-	newMean := m.prevMean + (value-outOfWindow)/m.cacheLen
-	m.prevVariance = m.prevVariance + (value-newMean+outOfWindow-m.prevMean)*(value-outOfWindow)/(m.cacheLen)
-	m.prevMean = newMean
+func (p *peakDetector) SetInfluence(influence float64) error {
+	cfg := p.config
+	cfg.Influence = influence
+	return p.Reconfigure(cfg)
+}
+
+func (p *peakDetector) SetThreshold(threshold float64) error {
+	cfg := p.config
+	cfg.Threshold = threshold
+	return p.Reconfigure(cfg)
+}
+
+func (p *peakDetector) Evaluate(cfg Config) ([]Signal, error) {
+	if cfg.Lag > p.config.Lag {
+		return nil, fmt.Errorf("the lag %d is longer than the retained window of %d values: %w", cfg.Lag, p.config.Lag, ErrInvalidInitialValues)
+	}
+	window := p.Window()
 
-	return m.prevMean, math.Sqrt(m.prevVariance)
+	detector := NewPeakDetector()
+	var estimator BaselineEstimator
+	switch b := p.baseline.(type) {
+	case *movingMedianMAD:
+		detector = NewRobustPeakDetector()
+	case *customBaseline:
+		estimator = b.estimator
+	}
+	if cfg.Baseline == nil {
+		cfg.Baseline = estimator
+	}
+	// The retained values are already differenced.
+	cfg.DerivativeOrder = 0
+	err := detector.InitializeWithConfig(cfg, window[:cfg.Lag])
+	if err != nil {
+		return nil, err
+	}
+
+	return detector.NextBatch(window[cfg.Lag:]), nil
+}
+
+func (p *peakDetector) NextBatch(values []float64) []Signal {
+	return p.NextBatchInto(make([]Signal, 0, len(values)), values)
+}
+
+func (p *peakDetector) NextBatchInto(dst []Signal, values []float64) []Signal {
+	dst = dst[:0]
+	m, ok := p.baseline.(*SlidingStats)
+	if !ok || p.differencer != nil || p.histogram != nil || p.metrics != nil || p.preprocessor != nil || p.moments != nil || p.config.Persistence > 1 || p.config.RefractoryPeriod != 0 || p.config.ReleaseThreshold != 0 || p.config.Percentile != 0 || p.config.Direction != DirectionBoth {
+		for _, v := range values {
+			dst = append(dst, p.Next(v))
+			p.backfill(dst)
+		}
+		return dst
+	}
+
+	// This is Next for the most common configurations, with the constants hoisted out of the loop and no dynamic
+	// dispatch.
+	deadband := p.config.Deadband
+	floor := p.stdDevFloor()
+	minCV := p.config.MinCoefficientOfVariation
+	lag := p.config.Lag
+	positiveThreshold, positiveInfluence := p.config.sided(SignalPositive)
+	negativeThreshold, negativeInfluence := p.config.sided(SignalNegative)
+	// The minimum and maximum only depend on the lag window, so they are rebuilt once at the end for a long batch.
+	rebuildMinMax := uint(len(values)) >= lag
+
+	var last lastValue
+	for _, value := range values {
+		if value-value != 0 {
+			// NaN and ±Inf follow the missing value policy.
+			dst = append(dst, p.Next(value))
+			last.processed = false
+			continue
+		}
+
+		mean := p.prevMean
+		side, threshold, influence := SignalPositive, positiveThreshold, positiveInfluence
+		if value <= mean {
+			side, threshold, influence = SignalNegative, negativeThreshold, negativeInfluence
+		}
+		last = lastValue{
+			center:      mean,
+			lowVariance: p.prevStdDev < minCV*math.Abs(mean),
+			mean:        mean,
+			processed:   true,
+			stdDev:      math.Max(p.prevStdDev, floor),
+			threshold:   threshold,
+			value:       value,
+		}
+		last.bound = threshold * last.stdDev
+		deviation := math.Abs(value - mean)
+		stored := value
+		signal := SignalNeutral
+		if !last.lowVariance && deviation > deadband && deviation > last.bound {
+			signal = side
+			stored = influence*value + (1-influence)*p.prevValue
+		}
+		last.signal = signal
+		// The active side is only used for Config.ReleaseThreshold, but it is kept so a later Reconfigure or the encoded
+		// state sees the same side as Next would have left.
+		p.active = signal
+
+		p.index++
+		if p.index == lag {
+			p.index = 0
+		}
+		p.prevMean, p.prevStdDev = m.next(stored)
+		if !rebuildMinMax {
+			p.movingMinMax.Next(stored)
+		}
+		p.count++
+		p.prevInput = value
+		p.prevValue = stored
+		dst = append(dst, signal)
+		if signal != SignalNeutral && len(p.handlers) != 0 {
+			p.emit(p.count-1, signal, value)
+		}
+	}
+	if last.processed {
+		p.last = last
+	}
+	if rebuildMinMax {
+		p.movingMinMax.reset()
+		for _, v := range m.cache[m.index:] {
+			p.movingMinMax.Next(v)
+		}
+		for _, v := range m.cache[:m.index] {
+			p.movingMinMax.Next(v)
+		}
+	}
+
+	return dst
+}
+
+func (p *peakDetector) SetLabels(labels map[string]string) {
+	p.labels = labels
+}
+
+func (p *peakDetector) Labels() map[string]string {
+	return p.labels
+}
+
+func (p *peakDetector) SetHistogram(h *Histogram) {
+	p.histogram = h
+}
+
+func (p *peakDetector) Window() []float64 {
+	return p.baseline.window()
+}
+
+// baseline estimates the center and spread of the values in the lag window. The spread is on the scale of a standard
+// deviation, so it can be compared with the threshold.
+type baseline interface {
+	// initialize replaces the window with the initial values and returns their center and spread.
+	initialize(initialValues []float64) (center, spread float64)
+	// next adds the value to the window, removing the oldest, and returns the new center and spread.
+	next(value float64) (center, spread float64)
+	// window returns a copy of the values in the window in chronological order.
+	window() []float64
+	// clone returns a deep copy.
+	clone() baseline
+	// reset returns the baseline to its zero value.
+	reset()
+	memoryFootprint() uintptr
+}
+
+// setResyncInterval sets how often the mean and standard deviation are recomputed from the lag window, if the baseline
+// supports it.
+func (p *peakDetector) setResyncInterval() {
+	if m, ok := p.baseline.(*SlidingStats); ok {
+		m.resyncInterval = p.config.ResyncInterval
+	}
 }
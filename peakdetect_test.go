@@ -1,7 +1,14 @@
 package peakdetect_test
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
 	"testing"
 
 	"github.com/MicahParks/peakdetect"
@@ -111,3 +118,893 @@ func TestPeakDetector_SignalNegative(t *testing.T) {
 		t.Fatalf("Signal should have been negative.\n  Actual: %d", signal)
 	}
 }
+
+func TestPeakDetector_Explain(t *testing.T) {
+	data := []float64{0, 1, 0, -1, 0, -500}
+	const lag = 5
+
+	detector := peakdetect.NewPeakDetector()
+	err := detector.Initialize(0.5, exampleThreshold, data[:lag])
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+
+	explanation := detector.Explain()
+	if explanation.Conditions != nil {
+		t.Fatalf("Explanation should be empty before processing values.\n  Actual: %+v", explanation)
+	}
+
+	signal := detector.Next(data[lag])
+	explanation = detector.Explain()
+	if explanation.Signal != signal {
+		t.Fatalf("Explanation signal did not match.\n  Expected: %d\n  Actual: %d", signal, explanation.Signal)
+	}
+	if explanation.Mean != 0 || explanation.Value != -500 || explanation.Stored != -250 {
+		t.Fatalf("Explanation values did not match.\n  Actual: %+v", explanation)
+	}
+	if explanation.ZScore >= -exampleThreshold {
+		t.Fatalf("Explanation z-score should exceed the threshold.\n  Actual: %f", explanation.ZScore)
+	}
+
+	expected := []peakdetect.Condition{
+		{Name: peakdetect.ConditionExceedsThreshold, Passed: true},
+		{Name: peakdetect.ConditionAboveMean, Passed: false},
+	}
+	for i, condition := range explanation.Conditions {
+		if condition != expected[i] {
+			t.Fatalf("Explanation condition did not match.\n  Expected: %+v\n  Actual: %+v", expected[i], condition)
+		}
+	}
+}
+
+func TestPeakDetector_Evaluate(t *testing.T) {
+	detector := peakdetect.NewPeakDetector()
+	err := detector.Initialize(exampleInfluence, exampleThreshold, exampleInputs[0:exampleLag])
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+	detector.NextBatch(exampleInputs[exampleLag:45])
+
+	_, err = detector.Evaluate(peakdetect.Config{Lag: exampleLag + 1})
+	if !errors.Is(err, peakdetect.ErrInvalidInitialValues) {
+		t.Fatalf("Evaluation with a lag longer than the window did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidInitialValues, err)
+	}
+
+	const lag = 10
+	window := exampleInputs[45-exampleLag : 45]
+	reference := peakdetect.NewPeakDetector()
+	err = reference.Initialize(1, 2, window[:lag])
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+	expected := reference.NextBatch(window[lag:])
+
+	signals, err := detector.Evaluate(peakdetect.Config{Influence: 1, Lag: lag, Threshold: 2})
+	if err != nil {
+		t.Fatalf(logFmt, "Error during evaluation.", err)
+	}
+	if len(signals) != len(expected) {
+		t.Fatalf("Unexpected number of signals.\n  Expected: %d\n  Actual: %d", len(expected), len(signals))
+	}
+	for i, signal := range signals {
+		if signal != expected[i] {
+			t.Fatalf("Evaluated signal did not match reference signal.\n  Expected: %d\n  Actual: %d", expected[i], signal)
+		}
+	}
+
+	cfg := peakdetect.Config{Direction: peakdetect.DirectionNegative, Influence: 1, Lag: lag, RefractoryPeriod: 2, Threshold: 1}
+	reference = peakdetect.NewPeakDetector()
+	err = reference.InitializeWithConfig(cfg, window[:lag])
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+	expected = reference.NextBatch(window[lag:])
+	signals, err = detector.Evaluate(cfg)
+	if err != nil {
+		t.Fatalf(logFmt, "Error during evaluation.", err)
+	}
+	if !reflect.DeepEqual(signals, expected) {
+		t.Fatalf("Evaluated signals did not use the whole configuration.\n  Expected: %v\n  Actual: %v", expected, signals)
+	}
+
+	signal := detector.Next(exampleInputs[45])
+	if signal != exampleOutputs[45] {
+		t.Fatalf("Evaluation modified the state of the detector.\n  Expected: %d\n  Actual: %d", exampleOutputs[45], signal)
+	}
+}
+
+func TestPeakDetector_Window(t *testing.T) {
+	data := []float64{1, 2, 3, 4, 100, 6}
+	const lag = 3
+
+	detector := peakdetect.NewPeakDetector()
+	err := detector.Initialize(0.5, 3, data[:lag])
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+	detector.NextBatch(data[lag:])
+
+	window := detector.Window()
+	expected := []float64{4, 52, 6}
+	if len(window) != len(expected) {
+		t.Fatalf("Unexpected window length.\n  Expected: %d\n  Actual: %d", len(expected), len(window))
+	}
+	for i, v := range window {
+		if v != expected[i] {
+			t.Fatalf("Window value did not match at index %d.\n  Expected: %f\n  Actual: %f", i, expected[i], v)
+		}
+	}
+
+	window[0] = 0
+	if detector.Window()[0] != expected[0] {
+		t.Fatalf("Modifying the returned window modified the detector.")
+	}
+}
+
+func TestPeakDetector_InitializeWithConfig(t *testing.T) {
+	detector := peakdetect.NewPeakDetector()
+	err := detector.InitializeWithConfig(peakdetect.Config{Lag: 3}, []float64{1, 2})
+	if !errors.Is(err, peakdetect.ErrInvalidInitialValues) {
+		t.Fatalf("Mismatched lag did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidInitialValues, err)
+	}
+
+	err = detector.InitializeWithConfig(peakdetect.Config{Influence: exampleInfluence, Lag: exampleLag, Threshold: exampleThreshold}, exampleInputs[:exampleLag])
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+	for i, signal := range detector.NextBatch(exampleInputs[exampleLag:]) {
+		if signal != exampleOutputs[i+exampleLag] {
+			t.Fatalf("Example signal did not match actual signal.\n  Example: %d\n  Actual: %d", exampleOutputs[i+exampleLag], signal)
+		}
+	}
+}
+
+func TestPeakDetector_MinCoefficientOfVariation(t *testing.T) {
+	// A constant stretch followed by a small move would be a signal without the floor.
+	data := []float64{5, 5, 5, 5.01, 5, 4.88, 50}
+	const lag = 5
+
+	detector := peakdetect.NewPeakDetector()
+	err := detector.InitializeWithConfig(peakdetect.Config{MinCoefficientOfVariation: 0.005, Threshold: 3}, data[:lag])
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+
+	signal := detector.Next(data[lag])
+	if signal != peakdetect.SignalNeutral {
+		t.Fatalf("Signal should have been suppressed.\n  Actual: %d", signal)
+	}
+	explanation := detector.Explain()
+	if !explanation.LowVariance || explanation.Conditions[0].Name != peakdetect.ConditionCoefficientOfVariation || explanation.Conditions[0].Passed {
+		t.Fatalf("Explanation should report low variance.\n  Actual: %+v", explanation)
+	}
+	if !explanation.Conditions[1].Passed {
+		t.Fatalf("Explanation should report that the threshold was exceeded.\n  Actual: %+v", explanation)
+	}
+
+	signal = detector.Next(data[lag+1])
+	if signal != peakdetect.SignalPositive {
+		t.Fatalf("Signal should not have been suppressed once the window varies.\n  Actual: %d", signal)
+	}
+}
+
+func TestPeakDetector_MinStdDev(t *testing.T) {
+	// A constant stretch followed by a small move would be a signal without the floor.
+	initialValues := []float64{5, 5, 5, 5, 5}
+
+	detector := peakdetect.NewPeakDetector()
+	err := detector.InitializeWithConfig(peakdetect.Config{MinStdDev: 0.1, Threshold: 3}, initialValues)
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+
+	if signal := detector.Next(4.88); signal != peakdetect.SignalNeutral {
+		t.Fatalf("Small move should not signal.\n  Actual: %d", signal)
+	}
+	if explanation := detector.Explain(); explanation.StdDev != 0.1 {
+		t.Fatalf("Explanation should use the standard deviation floor.\n  Expected: %f\n  Actual: %f", 0.1, explanation.StdDev)
+	}
+	if signal := detector.Next(4); signal != peakdetect.SignalNegative {
+		t.Fatalf("Large move should signal.\n  Expected: %d\n  Actual: %d", peakdetect.SignalNegative, signal)
+	}
+}
+
+func TestPeakDetector_Quantization(t *testing.T) {
+	// An integer sensor that reads the same value has no variance, so a change of one step is an infinite z-score.
+	initialValues := []float64{10, 10, 10, 10, 10}
+
+	testCases := map[string]struct {
+		cfg      peakdetect.Config
+		expected []peakdetect.Signal
+	}{
+		"None": {
+			cfg:      peakdetect.Config{Threshold: 3},
+			expected: []peakdetect.Signal{peakdetect.SignalPositive, peakdetect.SignalPositive},
+		},
+		"QuantizationStep": {
+			cfg:      peakdetect.Config{QuantizationStep: 1, Threshold: 4},
+			expected: []peakdetect.Signal{peakdetect.SignalNeutral, peakdetect.SignalPositive},
+		},
+		"Deadband": {
+			cfg:      peakdetect.Config{Deadband: 1, QuantizationStep: 1, Threshold: 3},
+			expected: []peakdetect.Signal{peakdetect.SignalNeutral, peakdetect.SignalPositive},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			detector := peakdetect.NewPeakDetector()
+			err := detector.InitializeWithConfig(tc.cfg, initialValues)
+			if err != nil {
+				t.Fatalf(logFmt, "Error during initilization.", err)
+			}
+
+			signal := detector.Next(11)
+			if signal != tc.expected[0] {
+				t.Fatalf("Incorrect signal for a one step change.\n  Expected: %d\n  Actual: %d", tc.expected[0], signal)
+			}
+			if tc.cfg.QuantizationStep > 0 && detector.Explain().StdDev != 1/math.Sqrt(12) {
+				t.Fatalf("Explanation should use the standard deviation floor.\n  Actual: %f", detector.Explain().StdDev)
+			}
+			signal = detector.Next(15)
+			if signal != tc.expected[1] {
+				t.Fatalf("Incorrect signal for a large change.\n  Expected: %d\n  Actual: %d", tc.expected[1], signal)
+			}
+		})
+	}
+}
+
+func TestPeakDetector_RefractoryPeriod(t *testing.T) {
+	const refractoryPeriod = 3
+
+	detector := peakdetect.NewPeakDetector()
+	err := detector.InitializeWithConfig(peakdetect.Config{
+		Influence:        exampleInfluence,
+		RefractoryPeriod: refractoryPeriod,
+		Threshold:        exampleThreshold,
+	}, exampleInputs[0:exampleLag])
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+
+	signals := detector.NextBatch(exampleInputs[exampleLag:])
+	sinceSignal := refractoryPeriod
+	for i, signal := range signals {
+		if signal != peakdetect.SignalNeutral {
+			if sinceSignal < refractoryPeriod {
+				t.Fatalf("Signal at index %d was within the refractory period.", exampleLag+i)
+			}
+			sinceSignal = 0
+			continue
+		}
+		sinceSignal++
+	}
+
+	// Without the refractory period, these signals are 1, 0, 1, 1, 1, 1, 1.
+	expected := []peakdetect.Signal{1, 0, 0, 0, 1, 0, 0}
+	if actual := signals[15:22]; !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("Incorrect signals.\n  Expected: %v\n  Actual: %v", expected, actual)
+	}
+	if explanation := detector.Explain(); len(explanation.Conditions) != 3 || explanation.Conditions[2].Name != peakdetect.ConditionOutsideRefractoryPeriod {
+		t.Fatalf("Explanation did not include the refractory period condition.\n  Actual: %+v", explanation)
+	}
+}
+
+func TestPeakDetector_Direction(t *testing.T) {
+	values := []float64{10, 11, 10, 9, 10, 11, 9, 10, 30, 10, 11, -10, 10, 9}
+	const lag = 8
+
+	testCases := map[peakdetect.Direction][]peakdetect.Signal{
+		peakdetect.DirectionBoth:     {1, 0, 0, -1, 0, 0},
+		peakdetect.DirectionPositive: {1, 0, 0, 0, 0, 0},
+		peakdetect.DirectionNegative: {0, 0, 0, -1, 0, 0},
+	}
+	var windows [][]float64
+	for direction, expected := range testCases {
+		detector := peakdetect.NewPeakDetector()
+		err := detector.InitializeWithConfig(peakdetect.Config{Direction: direction, Influence: 0, Threshold: 3}, values[:lag])
+		if err != nil {
+			t.Fatalf(logFmt, "Error during initilization.", err)
+		}
+
+		signals := detector.NextBatch(values[lag:])
+		if !reflect.DeepEqual(signals, expected) {
+			t.Fatalf("Incorrect signals for direction %d.\n  Expected: %v\n  Actual: %v", direction, expected, signals)
+		}
+		windows = append(windows, detector.Window())
+	}
+
+	// Excursions in the ignored direction are still influence adjusted.
+	for _, window := range windows[1:] {
+		if !reflect.DeepEqual(window, windows[0]) {
+			t.Fatalf("Windows should not depend on the direction.\n  Expected: %v\n  Actual: %v", windows[0], window)
+		}
+	}
+}
+
+func TestPeakDetector_Reconfigure(t *testing.T) {
+	const split = exampleLag + 10
+
+	detector := peakdetect.NewPeakDetector()
+	err := detector.Reconfigure(peakdetect.Config{})
+	if !errors.Is(err, peakdetect.ErrInvalidConfig) {
+		t.Fatalf("Reconfiguring an uninitialized detector did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidConfig, err)
+	}
+
+	err = detector.Initialize(exampleInfluence, 10, exampleInputs[0:exampleLag])
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+	detector.NextBatch(exampleInputs[exampleLag:split])
+
+	err = detector.Reconfigure(peakdetect.Config{Lag: exampleLag + 1})
+	if !errors.Is(err, peakdetect.ErrInvalidConfig) {
+		t.Fatalf("Changing the lag did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidConfig, err)
+	}
+
+	// Continuing with the example configuration must match a detector that used it all along, as no signals were
+	// produced before the split with either threshold.
+	err = detector.SetThreshold(-1)
+	if !errors.Is(err, peakdetect.ErrInvalidConfig) {
+		t.Fatalf("A negative threshold did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidConfig, err)
+	}
+	err = detector.SetInfluence(2)
+	if !errors.Is(err, peakdetect.ErrInvalidConfig) {
+		t.Fatalf("An influence outside of [0, 1] did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidConfig, err)
+	}
+	err = detector.SetThreshold(exampleThreshold)
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to set the threshold.", err)
+	}
+	err = detector.SetInfluence(exampleInfluence)
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to set the influence.", err)
+	}
+	err = detector.Reconfigure(peakdetect.Config{Influence: exampleInfluence, Threshold: exampleThreshold, TrackMoments: true})
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to reconfigure.", err)
+	}
+	if cfg := detector.Config(); cfg.Lag != exampleLag || cfg.Threshold != exampleThreshold {
+		t.Fatalf("Incorrect configuration after reconfiguring.\n  Actual: %+v", cfg)
+	}
+	if detector.Summary().Moments == nil {
+		t.Fatalf("Moments should be tracked after reconfiguring.")
+	}
+
+	signals := detector.NextBatch(exampleInputs[split:])
+	if !reflect.DeepEqual(signals, exampleOutputs[split:]) {
+		t.Fatalf("Incorrect signals after reconfiguring.\n  Expected: %v\n  Actual: %v", exampleOutputs[split:], signals)
+	}
+}
+
+func TestPeakDetector_SetLabels(t *testing.T) {
+	labels := map[string]string{"series": "queue-depth"}
+
+	detector := peakdetect.NewPeakDetector()
+	detector.SetLabels(labels)
+	err := detector.Initialize(exampleInfluence, exampleThreshold, exampleInputs[0:exampleLag])
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+
+	detector.Next(exampleInputs[exampleLag])
+	if detector.Labels()["series"] != "queue-depth" || detector.Explain().Labels["series"] != "queue-depth" {
+		t.Fatalf("Labels were not propagated to the explanation.\n  Actual: %v", detector.Explain().Labels)
+	}
+}
+
+func TestPeakDetector_DerivativeOrder(t *testing.T) {
+	// A plateau, then a slow curve up to a higher plateau. The level changes too slowly to signal, but the slope does.
+	var data []float64
+	for i := 0; i < 30; i++ {
+		data = append(data, 10+0.01*float64(i%3))
+	}
+	for i := 0; i < 10; i++ {
+		data = append(data, data[len(data)-1]+float64(i)*0.5)
+	}
+	const order = 1
+	const lag = 20
+
+	detector := peakdetect.NewPeakDetector()
+	err := detector.InitializeWithConfig(peakdetect.Config{DerivativeOrder: order, Lag: lag, Threshold: 5}, data[:lag+order])
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+	if window := detector.Window(); len(window) != lag || math.Abs(window[0]-0.01) > 1e-9 {
+		t.Fatalf("The window should hold the first differences.\n  Actual: %v", window)
+	}
+
+	signals := detector.NextBatch(data[lag+order:])
+	first := -1
+	for i, signal := range signals {
+		if signal == peakdetect.SignalPositive {
+			first = lag + order + i
+			break
+		}
+	}
+	if first != 31 {
+		t.Fatalf("The slope should signal at the start of the curve.\n  Expected: %d\n  Actual: %d", 31, first)
+	}
+	if explanation := detector.Explain(); math.Abs(explanation.Value-4.5) > 1e-9 {
+		t.Fatalf("The explained value should be the derivative.\n  Expected: %f\n  Actual: %f", 4.5, explanation.Value)
+	}
+
+	err = detector.InitializeWithConfig(peakdetect.Config{DerivativeOrder: 2}, data[:2])
+	if !errors.Is(err, peakdetect.ErrInvalidInitialValues) {
+		t.Fatalf("Too few initial values did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidInitialValues, err)
+	}
+	err = detector.InitializeWithConfig(peakdetect.Config{DerivativeOrder: 2, Threshold: 3}, []float64{1, 4, 9, 16, 25, 36})
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+	if window := detector.Window(); !reflect.DeepEqual(window, []float64{2, 2, 2, 2}) {
+		t.Fatalf("The window should hold the second differences.\n  Actual: %v", window)
+	}
+}
+
+func TestPeakDetector_InitializeAndDetect(t *testing.T) {
+	detector := peakdetect.NewPeakDetector()
+	_, err := detector.InitializeAndDetect(peakdetect.Config{}, exampleInputs)
+	if !errors.Is(err, peakdetect.ErrInvalidConfig) {
+		t.Fatalf("Zero lag did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidConfig, err)
+	}
+
+	const split = 60
+	signals, err := detector.InitializeAndDetect(peakdetect.Config{
+		Influence: exampleInfluence,
+		Lag:       exampleLag,
+		Threshold: exampleThreshold,
+	}, exampleInputs[:split])
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to initialize and detect.", err)
+	}
+	signals = append(signals, detector.NextBatch(exampleInputs[split:])...)
+
+	if !reflect.DeepEqual(signals, exampleOutputs) {
+		t.Fatalf("Signals did not match the reference outputs.\n  Expected: %v\n  Actual: %v", exampleOutputs, signals)
+	}
+}
+
+func TestPeakDetector_Clone(t *testing.T) {
+	const split = exampleLag + 10
+
+	histogram, err := peakdetect.NewHistogram(exampleLag, peakdetect.LinearBuckets(0, 1, 5))
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to create histogram.", err)
+	}
+	for _, detector := range []peakdetect.PeakDetector{peakdetect.NewPeakDetector(), peakdetect.NewRobustPeakDetector(), peakdetect.NewConcurrentPeakDetector()} {
+		detector.SetHistogram(histogram)
+		err = detector.InitializeWithConfig(peakdetect.Config{
+			Influence:    exampleInfluence,
+			Threshold:    exampleThreshold,
+			TrackMoments: true,
+		}, exampleInputs[:exampleLag])
+		if err != nil {
+			t.Fatalf(logFmt, "Error during initilization.", err)
+		}
+		detector.NextBatch(exampleInputs[exampleLag:split])
+
+		clone := detector.Clone()
+		expected := detector.NextBatchDetailed(exampleInputs[split:])
+		if reflect.DeepEqual(detector.Window(), clone.Window()) {
+			t.Fatalf("Processing values should not change the clone.")
+		}
+		actual := clone.NextBatchDetailed(exampleInputs[split:])
+		if !reflect.DeepEqual(expected, actual) {
+			t.Fatalf("Clone results do not match.\n  Expected: %v\n  Actual: %v", expected, actual)
+		}
+		if !reflect.DeepEqual(detector.Summary(), clone.Summary()) {
+			t.Fatalf("Clone summary does not match.\n  Expected: %+v\n  Actual: %+v", detector.Summary(), clone.Summary())
+		}
+	}
+}
+
+func TestPeakDetector_Reset(t *testing.T) {
+	detector := peakdetect.NewPeakDetector()
+	detector.SetLabels(map[string]string{"series": "reset"})
+	err := detector.Initialize(exampleInfluence, exampleThreshold, exampleInputs[:exampleLag])
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+	detector.NextBatch(exampleInputs[exampleLag:])
+
+	detector.Reset()
+	if len(detector.Window()) != 0 || detector.Config() != (peakdetect.Config{}) {
+		t.Fatalf("Reset did not drop the state.\n  Window: %v\n  Config: %+v", detector.Window(), detector.Config())
+	}
+	if detector.Labels()["series"] != "reset" {
+		t.Fatalf("Reset should keep the labels.\n  Actual: %v", detector.Labels())
+	}
+
+	err = detector.Initialize(exampleInfluence, exampleThreshold, exampleInputs[:exampleLag])
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+	signals := detector.NextBatch(exampleInputs[exampleLag:])
+	if !reflect.DeepEqual(signals, exampleOutputs[exampleLag:]) {
+		t.Fatalf("Incorrect signals after reset.\n  Expected: %v\n  Actual: %v", exampleOutputs[exampleLag:], signals)
+	}
+}
+
+func TestPeakDetector_ResyncInterval(t *testing.T) {
+	for _, resyncInterval := range []uint{0, 1000} {
+		r := rand.New(rand.NewSource(int64(resyncInterval)))
+		const lag = 50
+		data := make([]float64, 1_000_000)
+		for i := range data {
+			// A large offset with regime changes in scale is the worst case for the incremental update.
+			scale := 1e-3
+			if i/10_000%2 == 1 {
+				scale = 1e3
+			}
+			data[i] = 1e6 + scale*r.NormFloat64()
+		}
+
+		detector := peakdetect.NewPeakDetector()
+		err := detector.InitializeWithConfig(peakdetect.Config{
+			Influence:      1,
+			Lag:            lag,
+			ResyncInterval: resyncInterval,
+			Threshold:      exampleThreshold,
+		}, data[:lag])
+		if err != nil {
+			t.Fatalf(logFmt, "Error during initilization.", err)
+		}
+
+		var maxError float64
+		for i, v := range data[lag:] {
+			detector.Next(v)
+			if i%1000 != 0 {
+				continue
+			}
+			summary := detector.Summary()
+			expectedMean, expectedStdDev := referenceMeanStdDev(detector.Window())
+			if math.IsNaN(summary.StdDev) {
+				t.Fatalf("The standard deviation is NaN at index %d with resync interval %d.", lag+i, resyncInterval)
+			}
+			maxError = math.Max(maxError, math.Abs(summary.Mean-expectedMean)/expectedStdDev)
+			maxError = math.Max(maxError, math.Abs(summary.StdDev-expectedStdDev)/expectedStdDev)
+		}
+		// Without resynchronization, the drift is only guaranteed to not produce a NaN.
+		if resyncInterval != 0 && maxError > 1e-6 {
+			t.Fatalf("The drift relative to the standard deviation is not bounded with resync interval %d.\n  Expected: <= %g\n  Actual: %g", resyncInterval, 1e-6, maxError)
+		}
+	}
+}
+
+func referenceMeanStdDev(values []float64) (mean, stdDev float64) {
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	for _, v := range values {
+		stdDev += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(stdDev / float64(len(values)))
+}
+
+func TestPeakDetector_NextBatchInto(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	data := make([]float64, 5000)
+	for i := range data {
+		data[i] = r.NormFloat64()
+		if r.Intn(50) == 0 {
+			data[i] += 10 * r.NormFloat64()
+		}
+		if r.Intn(500) == 0 {
+			data[i] = math.NaN()
+		}
+	}
+
+	configs := []peakdetect.Config{
+		{Influence: 0.5, Threshold: 3},
+		{Influence: 0.2, Threshold: 2, Deadband: 0.5, MinStdDev: 0.8, ResyncInterval: 100},
+		{Influence: 1, Threshold: 3, MinCoefficientOfVariation: 0.1, Missing: peakdetect.MissingRepeat},
+		{Influence: 0, Threshold: 3, RefractoryPeriod: 5},
+		{Influence: 0.5, Threshold: 3, NegativeThreshold: 2, NegativeInfluence: new(float64)},
+	}
+	for _, cfg := range configs {
+		const lag = 20
+		expected := peakdetect.NewPeakDetector()
+		err := expected.InitializeWithConfig(cfg, data[:lag])
+		if err != nil {
+			t.Fatalf(logFmt, "Error during initilization.", err)
+		}
+		detector := expected.Clone()
+
+		var dst []peakdetect.Signal
+		for start := lag; start < len(data); start += 1000 {
+			end := min(start+1000, len(data))
+			expectedSignals := make([]peakdetect.Signal, 0, end-start)
+			for _, v := range data[start:end] {
+				expectedSignals = append(expectedSignals, expected.Next(v))
+			}
+
+			dst = detector.NextBatchInto(dst, data[start:end])
+			if !reflect.DeepEqual(dst, expectedSignals) {
+				t.Fatalf("Incorrect signals for %+v.\n  Expected: %v\n  Actual: %v", cfg, expectedSignals, dst)
+			}
+			if !reflect.DeepEqual(detector.Explain(), expected.Explain()) || !reflect.DeepEqual(detector.Window(), expected.Window()) || !reflect.DeepEqual(detector.Summary(), expected.Summary()) {
+				t.Fatalf("Incorrect state for %+v.\n  Expected: %+v\n  Actual: %+v", cfg, expected.Explain(), detector.Explain())
+			}
+			expectedState, err := expected.MarshalJSON()
+			if err != nil {
+				t.Fatalf(logFmt, "Failed to marshal detector state.", err)
+			}
+			state, err := detector.MarshalJSON()
+			if err != nil {
+				t.Fatalf(logFmt, "Failed to marshal detector state.", err)
+			}
+			if !bytes.Equal(state, expectedState) {
+				t.Fatalf("Incorrect encoded state for %+v.\n  Expected: %s\n  Actual: %s", cfg, expectedState, state)
+			}
+		}
+	}
+}
+
+func TestPeakDetector_NextBatchIntoReconfigure(t *testing.T) {
+	// The batch ends with a signal, so the side of the exceedance must be kept for a release threshold set afterwards.
+	values := append(append([]float64(nil), exampleInputs[exampleLag:]...), 100)
+	expected := peakdetect.NewPeakDetector()
+	err := expected.Initialize(exampleInfluence, exampleThreshold, exampleInputs[:exampleLag])
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+	detector := expected.Clone()
+	for _, v := range values {
+		expected.Next(v)
+	}
+	detector.NextBatchInto(nil, values)
+
+	for _, d := range []peakdetect.PeakDetector{expected, detector} {
+		err = d.Reconfigure(peakdetect.Config{
+			Influence:        exampleInfluence,
+			Threshold:        exampleThreshold,
+			ReleaseThreshold: 1,
+		})
+		if err != nil {
+			t.Fatalf(logFmt, "Failed to reconfigure.", err)
+		}
+	}
+	const value = 2.5
+	expectedSignal := expected.Next(value)
+	if signal := detector.Next(value); signal != expectedSignal || expected.Explain().Threshold != detector.Explain().Threshold {
+		t.Fatalf("Incorrect signal after reconfiguring.\n  Expected: %d with threshold %f\n  Actual: %d with threshold %f", expectedSignal, expected.Explain().Threshold, signal, detector.Explain().Threshold)
+	}
+}
+
+func benchmarkData() []float64 {
+	r := rand.New(rand.NewSource(1))
+	data := make([]float64, 1_000_000)
+	for i := range data {
+		data[i] = r.NormFloat64()
+	}
+	return data
+}
+
+func BenchmarkPeakDetector_NextBatchMillion(b *testing.B) {
+	data := benchmarkData()
+	detector := peakdetect.NewPeakDetector()
+	err := detector.Initialize(exampleInfluence, exampleThreshold, data[:exampleLag])
+	if err != nil {
+		b.Fatalf(logFmt, "Error during initilization.", err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		detector.NextBatch(data)
+	}
+	b.ReportMetric(float64(b.N*len(data))/b.Elapsed().Seconds(), "points/s")
+}
+
+func BenchmarkPeakDetector_NextBatchIntoMillion(b *testing.B) {
+	data := benchmarkData()
+	detector := peakdetect.NewPeakDetector()
+	err := detector.Initialize(exampleInfluence, exampleThreshold, data[:exampleLag])
+	if err != nil {
+		b.Fatalf(logFmt, "Error during initilization.", err)
+	}
+	dst := make([]peakdetect.Signal, len(data))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dst = detector.NextBatchInto(dst, data)
+	}
+	b.ReportMetric(float64(b.N*len(data))/b.Elapsed().Seconds(), "points/s")
+}
+
+func BenchmarkPeakDetector_NextMillion(b *testing.B) {
+	data := benchmarkData()
+	detector := peakdetect.NewPeakDetector()
+	err := detector.Initialize(exampleInfluence, exampleThreshold, data[:exampleLag])
+	if err != nil {
+		b.Fatalf(logFmt, "Error during initilization.", err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, v := range data {
+			detector.Next(v)
+		}
+	}
+	b.ReportMetric(float64(b.N*len(data))/b.Elapsed().Seconds(), "points/s")
+}
+
+func TestPeakDetector_ReleaseThreshold(t *testing.T) {
+	initial := []float64{1, 1.1, 1, 0.9, 1, 1.1, 1, 0.9, 1, 1.1, 1, 0.9, 1, 1.1, 1, 0.9, 1, 1.1, 1, 0.9}
+	values := []float64{2, 1.5, 1.4, 1.05, 3, 0.75, 1.4}
+	expected := map[float64][]peakdetect.Signal{
+		0: {1, 0, 0, 0, 1, 0, 0},
+		3: {1, 1, 1, 0, 1, 0, 0},
+	}
+
+	for releaseThreshold, expectedSignals := range expected {
+		detector := peakdetect.NewPeakDetector()
+		err := detector.InitializeWithConfig(peakdetect.Config{
+			Influence:        0,
+			ReleaseThreshold: releaseThreshold,
+			Threshold:        8,
+		}, initial)
+		if err != nil {
+			t.Fatalf(logFmt, "Error during initilization.", err)
+		}
+
+		for i, v := range values {
+			signal := detector.Next(v)
+			if signal != expectedSignals[i] {
+				t.Fatalf("Incorrect signal at index %d with release threshold %f.\n  Expected: %d\n  Actual: %d\n  Explanation: %+v", i, releaseThreshold, expectedSignals[i], signal, detector.Explain())
+			}
+		}
+	}
+
+	err := peakdetect.Config{Threshold: 3, ReleaseThreshold: 4}.Validate()
+	if !errors.Is(err, peakdetect.ErrInvalidConfig) {
+		t.Fatalf("A release threshold above the threshold did not produce the correct error.\n  Expected: %s\n  Actual: %v", peakdetect.ErrInvalidConfig, err)
+	}
+}
+
+func TestPeakDetector_NegativeThresholdInfluence(t *testing.T) {
+	initial := []float64{1, 1.1, 1, 0.9, 1, 1.1, 1, 0.9, 1, 1.1}
+	negativeInfluence := 0.0
+
+	detector, err := peakdetect.NewFromOptions(initial,
+		peakdetect.WithInfluence(1),
+		peakdetect.WithThreshold(3),
+		peakdetect.WithNegativeThreshold(20),
+		peakdetect.WithNegativeInfluence(negativeInfluence),
+	)
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+
+	signal := detector.Next(0.5)
+	if signal != peakdetect.SignalNeutral || detector.Explain().Threshold != 20 {
+		t.Fatalf("A dip below the negative threshold should be neutral.\n  Actual: %+v", detector.Explain())
+	}
+
+	detector, err = peakdetect.NewFromOptions(initial,
+		peakdetect.WithInfluence(1),
+		peakdetect.WithThreshold(3),
+		peakdetect.WithNegativeInfluence(negativeInfluence),
+	)
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+	signal = detector.Next(-5)
+	explanation := detector.Explain()
+	if signal != peakdetect.SignalNegative || explanation.Influence != 0 || explanation.Stored != initial[len(initial)-1] {
+		t.Fatalf("A negative signal should use the negative influence.\n  Actual: %+v", explanation)
+	}
+	signal = detector.Next(20)
+	explanation = detector.Explain()
+	if signal != peakdetect.SignalPositive || explanation.Influence != 1 || explanation.Stored != 20 {
+		t.Fatalf("A positive signal should use the influence.\n  Actual: %+v", explanation)
+	}
+
+	invalid := 2.0
+	err = peakdetect.Config{Threshold: 3, NegativeInfluence: &invalid}.Validate()
+	if !errors.Is(err, peakdetect.ErrInvalidConfig) {
+		t.Fatalf("An invalid negative influence did not produce the correct error.\n  Expected: %s\n  Actual: %v", peakdetect.ErrInvalidConfig, err)
+	}
+	err = peakdetect.Config{Threshold: 3, NegativeThreshold: 2, ReleaseThreshold: 2.5}.Validate()
+	if !errors.Is(err, peakdetect.ErrInvalidConfig) {
+		t.Fatalf("A release threshold above the negative threshold did not produce the correct error.\n  Expected: %s\n  Actual: %v", peakdetect.ErrInvalidConfig, err)
+	}
+}
+
+func TestPeakDetector_Persistence(t *testing.T) {
+	initial := []float64{1, 1.1, 1, 0.9, 1, 1.1, 1, 0.9, 1, 1.1, 1, 0.9, 1, 1.1, 1, 0.9, 1, 1.1, 1, 0.9}
+	values := []float64{5, 1, 5, 5, 5, 1, 5, 1, 5}
+	tests := map[string]struct {
+		cfg      peakdetect.Config
+		expected []peakdetect.Signal
+	}{
+		"Disabled": {
+			cfg:      peakdetect.Config{},
+			expected: []peakdetect.Signal{1, 0, 1, 1, 1, 0, 1, 0, 1},
+		},
+		"Consecutive": {
+			cfg:      peakdetect.Config{Persistence: 3},
+			expected: []peakdetect.Signal{0, 0, 0, 0, 1, 0, 0, 0, 0},
+		},
+		"ConsecutiveBackfill": {
+			cfg:      peakdetect.Config{Persistence: 3, PersistenceBackfill: true},
+			expected: []peakdetect.Signal{0, 0, 1, 1, 1, 0, 0, 0, 0},
+		},
+		"KOfN": {
+			cfg:      peakdetect.Config{Persistence: 2, PersistenceWindow: 3},
+			expected: []peakdetect.Signal{0, 0, 1, 1, 1, 0, 1, 0, 1},
+		},
+		"KOfNBackfill": {
+			cfg:      peakdetect.Config{Persistence: 2, PersistenceWindow: 3, PersistenceBackfill: true},
+			expected: []peakdetect.Signal{1, 0, 1, 1, 1, 0, 1, 0, 1},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := tc.cfg
+			cfg.Threshold = 8
+
+			detector := peakdetect.NewPeakDetector()
+			err := detector.InitializeWithConfig(cfg, initial)
+			if err != nil {
+				t.Fatalf(logFmt, "Error during initilization.", err)
+			}
+			var events []uint64
+			detector.OnSignal(peakdetect.DirectionBoth, func(event peakdetect.SignalEvent) {
+				events = append(events, event.Index)
+			})
+
+			signals := detector.NextBatch(values)
+			var expectedEvents []uint64
+			for i, expected := range tc.expected {
+				if signals[i] != expected {
+					t.Fatalf("Incorrect signal at index %d.\n  Expected: %v\n  Actual: %v", i, tc.expected, signals)
+				}
+				if expected != peakdetect.SignalNeutral {
+					expectedEvents = append(expectedEvents, uint64(i))
+				}
+			}
+			sort.Slice(events, func(i, j int) bool { return events[i] < events[j] })
+			if fmt.Sprint(events) != fmt.Sprint(expectedEvents) {
+				t.Fatalf("Incorrect event indices.\n  Expected: %v\n  Actual: %v", expectedEvents, events)
+			}
+		})
+	}
+
+	detector, err := peakdetect.NewFromOptions(initial, peakdetect.WithThreshold(8), peakdetect.WithPersistence(2, 0))
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+	detector.Next(5)
+	explanation := detector.Explain()
+	for _, condition := range explanation.Conditions {
+		if condition.Name == peakdetect.ConditionPersisted && condition.Passed {
+			t.Fatalf("A single exceedance should not persist.\n  Actual: %+v", explanation)
+		}
+	}
+
+	clone := detector.Clone()
+	data, err := json.Marshal(detector)
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to marshal detector.", err)
+	}
+	restored := peakdetect.NewPeakDetector()
+	err = json.Unmarshal(data, restored)
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to unmarshal detector.", err)
+	}
+	for _, d := range []peakdetect.PeakDetector{detector, clone, restored} {
+		if signal := d.Next(5); signal != peakdetect.SignalPositive {
+			t.Fatalf("The second exceedance should persist.\n  Expected: %d\n  Actual: %d", peakdetect.SignalPositive, signal)
+		}
+	}
+
+	err = peakdetect.Config{Threshold: 3, Persistence: 3, PersistenceWindow: 2}.Validate()
+	if !errors.Is(err, peakdetect.ErrInvalidConfig) {
+		t.Fatalf("A persistence window below the persistence did not produce the correct error.\n  Expected: %s\n  Actual: %v", peakdetect.ErrInvalidConfig, err)
+	}
+}
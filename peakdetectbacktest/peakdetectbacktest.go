@@ -0,0 +1,194 @@
+// Package peakdetectbacktest replays recorded series with labeled ground truth peaks through peakdetect configurations
+// and reports the true positives, false positives, detection latency, and missed peaks of each. Keeping the reports of
+// known good configurations makes it possible to regression test them, such as when upgrading the library.
+package peakdetectbacktest
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/MicahParks/peakdetect"
+	"github.com/MicahParks/peakdetect/datasets"
+)
+
+// ErrLengthMismatch indicates that the number of signals given to Score does not match the number of values in the
+// Series.
+var ErrLengthMismatch = errors.New("the number of signals does not match the number of values")
+
+// Series is a recorded series with labeled ground truth peaks.
+type Series struct {
+	// Name identifies the series in a Report.
+	Name string
+	// Peaks are the indices of the labeled peaks in the Values.
+	Peaks []int
+	// Tolerance is the number of values a detected peak can start after, or end before, a labeled peak and still match
+	// it.
+	Tolerance uint
+	// Values are the recorded values.
+	Values []float64
+}
+
+// FromDataset creates a Series from a dataset of the datasets package.
+func FromDataset(dataset datasets.Dataset, tolerance uint) Series {
+	return Series{
+		Name:      dataset.Name,
+		Peaks:     dataset.Peaks,
+		Tolerance: tolerance,
+		Values:    dataset.Values,
+	}
+}
+
+// Detection is a detected peak and the labeled peak it matched, if any.
+type Detection struct {
+	// Peak is the detected peak.
+	Peak peakdetect.PeakEvent
+	// Label is the index of the labeled peak that the detected peak matched, or -1 for a false positive.
+	Label int
+	// Latency is the number of values from the labeled peak to the first signal of the detected peak. It is negative if
+	// the signal started before the labeled peak, such as on its rising edge. It is zero for a false positive.
+	Latency int
+}
+
+// Report is how a configuration performed on a Series.
+type Report struct {
+	// Config is the configuration that was run. It is the zero value for a Report from Score.
+	Config peakdetect.Config
+	// Series is the name of the Series.
+	Series string
+	// Err is the error from running the Config or scoring the signals, if any. The other fields are zero if it is not
+	// nil.
+	Err error
+
+	// Signals is the signal for every value of the Series, which can be compared across versions with Changed.
+	Signals []peakdetect.Signal
+	// Detections are the detected peaks in the order they occurred.
+	Detections []Detection
+	// TruePositives is the number of detected peaks that matched a labeled peak.
+	TruePositives int
+	// FalsePositives is the number of detected peaks that did not match a labeled peak.
+	FalsePositives int
+	// Missed are the indices of the labeled peaks that no detected peak matched, in ascending order. Labeled peaks
+	// within the initial values used for the lag are always missed.
+	Missed []int
+
+	// MeanLatency is the mean Latency of the true positives. It is zero if there are none.
+	MeanLatency float64
+	// MaxLatency is the greatest Latency of the true positives. It is zero if there are none.
+	MaxLatency int
+
+	// Precision is the fraction of the detected peaks that matched a labeled peak. It is zero if there are no detected
+	// peaks.
+	Precision float64
+	// Recall is the fraction of the labeled peaks that were matched. It is zero if there are no labeled peaks.
+	Recall float64
+	// F1 is the harmonic mean of the Precision and Recall. It is zero if both are zero.
+	F1 float64
+}
+
+// String summarizes the Report on one line.
+func (r Report) String() string {
+	if r.Err != nil {
+		return fmt.Sprintf("%s: lag=%d threshold=%g influence=%g: %s", r.Series, r.Config.Lag, r.Config.Threshold, r.Config.Influence, r.Err)
+	}
+	return fmt.Sprintf("%s: lag=%d threshold=%g influence=%g: tp=%d fp=%d missed=%d precision=%.3f recall=%.3f f1=%.3f latency=%.2f/%d",
+		r.Series, r.Config.Lag, r.Config.Threshold, r.Config.Influence, r.TruePositives, r.FalsePositives, len(r.Missed),
+		r.Precision, r.Recall, r.F1, r.MeanLatency, r.MaxLatency)
+}
+
+// Run runs each config over the Series like peakdetect.Detect and reports how it performed, in the order of the
+// configs.
+func Run(series Series, configs ...peakdetect.Config) []Report {
+	reports := make([]Report, len(configs))
+	for i, cfg := range configs {
+		signals, _, err := peakdetect.Detect(series.Values, cfg)
+		if err != nil {
+			reports[i] = Report{
+				Config: cfg,
+				Series: series.Name,
+				Err:    err,
+			}
+			continue
+		}
+		reports[i] = Score(series, signals)
+		reports[i].Config = cfg
+	}
+	return reports
+}
+
+// Score reports how the signals, one for each value of the Series, performed against its labeled peaks. It is used for
+// signals that Run cannot produce from a Config, such as from a robust PeakDetector or one with a Preprocessor.
+//
+// The signals are grouped into peaks with peakdetect.GroupPeaks and matched to the labeled peaks with
+// peakdetect.MatchPeaks, so the scores agree with peakdetect.GridSearch. A detected peak matches the earliest labeled
+// peak that is not yet matched and is within Series.Tolerance values of its start or end. Each labeled peak is matched
+// at most once. Negative peaks are matched the same way, so they are false positives unless they are labeled.
+//
+// There must be one signal for every value of the Series. Otherwise, the Report has an error that wraps
+// ErrLengthMismatch.
+func Score(series Series, signals []peakdetect.Signal) Report {
+	if len(signals) != len(series.Values) {
+		return Report{
+			Series: series.Name,
+			Err:    fmt.Errorf("%d signals for %d values: %w", len(signals), len(series.Values), ErrLengthMismatch),
+		}
+	}
+	report := Report{
+		Series:  series.Name,
+		Signals: signals,
+	}
+	peaks := peakdetect.GroupPeaks(signals, series.Values)
+	matched := make([]bool, len(series.Peaks))
+	var latencySum int
+	for i, match := range peakdetect.MatchPeaks(peaks, series.Peaks, series.Tolerance) {
+		detection := Detection{
+			Peak:  peaks[i],
+			Label: -1,
+		}
+		if match == -1 {
+			report.FalsePositives++
+		} else {
+			matched[match] = true
+			detection.Label = series.Peaks[match]
+			detection.Latency = int(peaks[i].StartIndex) - detection.Label
+			if report.TruePositives == 0 || detection.Latency > report.MaxLatency {
+				report.MaxLatency = detection.Latency
+			}
+			report.TruePositives++
+			latencySum += detection.Latency
+		}
+		report.Detections = append(report.Detections, detection)
+	}
+	for j, label := range series.Peaks {
+		if !matched[j] {
+			report.Missed = append(report.Missed, label)
+		}
+	}
+	sort.Ints(report.Missed)
+
+	if report.TruePositives != 0 {
+		report.MeanLatency = float64(latencySum) / float64(report.TruePositives)
+	}
+	if len(report.Detections) != 0 {
+		report.Precision = float64(report.TruePositives) / float64(len(report.Detections))
+	}
+	if len(series.Peaks) != 0 {
+		report.Recall = float64(report.TruePositives) / float64(len(series.Peaks))
+	}
+	if report.Precision+report.Recall != 0 {
+		report.F1 = 2 * report.Precision * report.Recall / (report.Precision + report.Recall)
+	}
+	return report
+}
+
+// Changed returns the indices where the signals differ, such as the Signals of the Reports of the same Series and
+// Config before and after upgrading the library. Indices beyond the end of the shorter slice are all changed.
+func Changed(previous, current []peakdetect.Signal) []int {
+	var changed []int
+	for i := 0; i < max(len(previous), len(current)); i++ {
+		if i >= len(previous) || i >= len(current) || previous[i] != current[i] {
+			changed = append(changed, i)
+		}
+	}
+	return changed
+}
@@ -0,0 +1,156 @@
+package peakdetectbacktest_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/MicahParks/peakdetect"
+	"github.com/MicahParks/peakdetect/datasets"
+	"github.com/MicahParks/peakdetect/peakdetectbacktest"
+)
+
+const logFmt = "%s\nError: %s"
+
+func TestScore(t *testing.T) {
+	const (
+		n = peakdetect.SignalNeutral
+		p = peakdetect.SignalPositive
+	)
+	series := peakdetectbacktest.Series{
+		Name:      "synthetic",
+		Peaks:     []int{12, 3, 8},
+		Tolerance: 1,
+		Values:    []float64{0, 0, 1, 2, 1, 0, 0, 0, 0, 0, 0, 0, 0, 3, 0, 0},
+	}
+	signals := []peakdetect.Signal{n, n, p, p, p, n, p, n, n, n, n, n, n, p, p, n}
+
+	report := peakdetectbacktest.Score(series, signals)
+	if report.Series != series.Name || !reflect.DeepEqual(report.Signals, signals) {
+		t.Fatalf("Incorrect series or signals.\n  Actual: %s, %v", report.Series, report.Signals)
+	}
+	if report.TruePositives != 2 || report.FalsePositives != 1 || !reflect.DeepEqual(report.Missed, []int{8}) {
+		t.Fatalf("Incorrect matches.\n  Expected: 2, 1, [8]\n  Actual: %d, %d, %v", report.TruePositives, report.FalsePositives, report.Missed)
+	}
+
+	expected := []struct {
+		label   int
+		latency int
+	}{
+		{label: 3, latency: -1},
+		{label: -1, latency: 0},
+		{label: 12, latency: 1},
+	}
+	if len(report.Detections) != len(expected) {
+		t.Fatalf("Incorrect number of detections.\n  Expected: %d\n  Actual: %d", len(expected), len(report.Detections))
+	}
+	for i, e := range expected {
+		detection := report.Detections[i]
+		if detection.Label != e.label || detection.Latency != e.latency {
+			t.Fatalf("Incorrect detection %d.\n  Expected: %d, %d\n  Actual: %d, %d", i, e.label, e.latency, detection.Label, detection.Latency)
+		}
+	}
+	if report.MeanLatency != 0 || report.MaxLatency != 1 {
+		t.Fatalf("Incorrect latency.\n  Expected: 0, 1\n  Actual: %f, %d", report.MeanLatency, report.MaxLatency)
+	}
+	if report.Precision != 2.0/3 || report.Recall != 2.0/3 || report.F1 != 2.0/3 {
+		t.Fatalf("Incorrect scores.\n  Expected: %f\n  Actual: %f, %f, %f", 2.0/3, report.Precision, report.Recall, report.F1)
+	}
+}
+
+func TestScore_Empty(t *testing.T) {
+	series := peakdetectbacktest.Series{
+		Values: []float64{1, 2, 3},
+	}
+	report := peakdetectbacktest.Score(series, make([]peakdetect.Signal, len(series.Values)))
+	if len(report.Detections) != 0 || len(report.Missed) != 0 || report.Precision != 0 || report.Recall != 0 || report.F1 != 0 {
+		t.Fatalf("A series without peaks or signals should have an empty report.\n  Actual: %+v", report)
+	}
+}
+
+func TestScore_LengthMismatch(t *testing.T) {
+	series := peakdetectbacktest.Series{
+		Name:   "short",
+		Peaks:  []int{2},
+		Values: []float64{1, 2, 3},
+	}
+	for _, signals := range [][]peakdetect.Signal{
+		{peakdetect.SignalNeutral, peakdetect.SignalPositive},
+		{peakdetect.SignalNeutral, peakdetect.SignalNeutral, peakdetect.SignalPositive, peakdetect.SignalPositive},
+	} {
+		report := peakdetectbacktest.Score(series, signals)
+		if !errors.Is(report.Err, peakdetectbacktest.ErrLengthMismatch) {
+			t.Fatalf("Signals that do not match the values should produce an error.\n  Expected: %s\n  Actual: %s", peakdetectbacktest.ErrLengthMismatch, report.Err)
+		}
+		if report.Series != series.Name || report.Signals != nil || report.Detections != nil || report.TruePositives != 0 || report.F1 != 0 {
+			t.Fatalf("A report with an error should only have its series.\n  Actual: %+v", report)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	dataset, err := datasets.Load(datasets.StackOverflow)
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to load the dataset.", err)
+	}
+	series := peakdetectbacktest.FromDataset(dataset, 2)
+	configs := []peakdetect.Config{
+		{Lag: 30, Threshold: 5},
+		{Lag: 10, Threshold: 3, Influence: 0.5},
+		{Lag: uint(len(dataset.Values)) + 1, Threshold: 5},
+	}
+
+	reports := peakdetectbacktest.Run(series, configs...)
+	if len(reports) != len(configs) {
+		t.Fatalf("Incorrect number of reports.\n  Expected: %d\n  Actual: %d", len(configs), len(reports))
+	}
+	for i, report := range reports {
+		if !reflect.DeepEqual(report.Config, configs[i]) || report.Series != dataset.Name {
+			t.Fatalf("Report %d does not describe its config and series.\n  Actual: %s", i, report)
+		}
+	}
+
+	reference := reports[0]
+	if reference.Err != nil {
+		t.Fatalf(logFmt, "Failed to run the reference config.", reference.Err)
+	}
+	if reference.TruePositives != len(dataset.Peaks) || reference.FalsePositives != 0 || len(reference.Missed) != 0 || reference.F1 != 1 {
+		t.Fatalf("The reference config should find every peak.\n  Actual: %s", reference)
+	}
+	signals, _, err := peakdetect.Detect(dataset.Values, configs[0])
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to detect.", err)
+	}
+	if !reflect.DeepEqual(reference.Signals, signals) {
+		t.Fatalf("The signals should match Detect.\n  Expected: %v\n  Actual: %v", signals, reference.Signals)
+	}
+
+	if reports[1].Err != nil || reports[1].FalsePositives == 0 || len(reports[1].Missed) == 0 {
+		t.Fatalf("The sensitive config should have false positives and missed peaks.\n  Actual: %s", reports[1])
+	}
+	if !errors.Is(reports[2].Err, peakdetect.ErrInvalidConfig) {
+		t.Fatalf("A lag longer than the series should produce an error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidConfig, reports[2].Err)
+	}
+
+	for i, evaluation := range peakdetect.GridSearch(dataset.Values, dataset.Peaks, series.Tolerance, configs[:2]) {
+		if evaluation.TruePositives != reports[i].TruePositives || evaluation.F1 != reports[i].F1 {
+			t.Fatalf("The report for config %d does not agree with GridSearch.\n  Expected: %+v\n  Actual: %s", i, evaluation, reports[i])
+		}
+	}
+}
+
+func TestChanged(t *testing.T) {
+	const (
+		n = peakdetect.SignalNeutral
+		p = peakdetect.SignalPositive
+	)
+	previous := []peakdetect.Signal{n, p, p, n}
+	current := []peakdetect.Signal{n, p, n, n, p}
+	changed := peakdetectbacktest.Changed(previous, current)
+	if !reflect.DeepEqual(changed, []int{2, 4}) {
+		t.Fatalf("Incorrect changed indices.\n  Expected: %v\n  Actual: %v", []int{2, 4}, changed)
+	}
+	if changed := peakdetectbacktest.Changed(previous, previous); changed != nil {
+		t.Fatalf("Equal signals should not have changed.\n  Actual: %v", changed)
+	}
+}
@@ -0,0 +1,64 @@
+// Command peakdetectflight runs an Arrow Flight server for the peak detection algorithm. See the peakdetectflight
+// package for the columns of the result batches.
+//
+// Usage:
+//
+//	peakdetectflight [-addr :8815] [-lag 30] [-threshold 5] [-influence 0]
+//
+// The flags are the default config for streams whose FlightDescriptor does not have one.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"syscall"
+
+	"github.com/apache/arrow/go/v17/arrow/flight"
+
+	"github.com/MicahParks/peakdetect"
+	"github.com/MicahParks/peakdetect/peakdetectflight"
+)
+
+func main() {
+	err := run(os.Args[1:], os.Stderr)
+	if err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			_, _ = fmt.Fprintf(os.Stderr, "peakdetectflight: %s\n", err)
+		}
+		os.Exit(2)
+	}
+}
+
+func run(args []string, stderr io.Writer) error {
+	flags := flag.NewFlagSet("peakdetectflight", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	addr := flags.String("addr", ":8815", "the address to listen on")
+	lag := flags.Uint("lag", 30, "the number of values in the moving window, used for initialization")
+	threshold := flags.Float64("threshold", 5, "the number of standard deviations from the moving mean for a signal")
+	influence := flags.Float64("influence", 0, "the influence of signals on the moving mean and standard deviation, from 0 to 1")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	server, err := peakdetectflight.NewServer(peakdetect.Config{
+		Influence: *influence,
+		Lag:       *lag,
+		Threshold: *threshold,
+	})
+	if err != nil {
+		return err
+	}
+	s := flight.NewServerWithMiddleware(nil)
+	err = s.Init(*addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+	s.RegisterFlightService(server)
+	s.SetShutdownOnSignals(os.Interrupt, syscall.SIGTERM)
+	_, _ = fmt.Fprintf(stderr, "listening on %s\n", s.Addr())
+	return s.Serve()
+}
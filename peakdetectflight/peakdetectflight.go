@@ -0,0 +1,253 @@
+// Package peakdetectflight serves the peak detection algorithm over Arrow Flight, so data platforms that speak Flight
+// can run detectors over streams of record batches, such as for backfills, without converting every row to JSON. It is
+// a separate module, so the rest of peakdetect does not depend on Arrow or gRPC.
+//
+// A client calls DoExchange with a stream of record batches that share one schema. Every numeric column gets its own
+// PeakDetector for the duration of the stream. Each result batch is the input batch with two columns appended for each
+// numeric column:
+//
+//   - <name>_signal, an int8 of -1, 0, or 1 for a negative, neutral, or positive signal.
+//   - <name>_zscore, a float64 of the z-score of the value, which is null if the value was not processed.
+//
+// The first Config.Lag values of each column, plus Config.DerivativeOrder, initialize its detector, so they have a
+// neutral signal and a null z-score. They must be finite. After that, null values are NaN, which are handled as
+// described by Config.Missing. Columns that are not numeric are passed through.
+//
+// The FlightDescriptor of the stream may have a command with a JSON peakdetect.Config, such as
+// {"lag": 30, "threshold": 5, "influence": 0}, to use instead of the Config of the Server.
+package peakdetectflight
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/array"
+	"github.com/apache/arrow/go/v17/arrow/flight"
+	"github.com/apache/arrow/go/v17/arrow/ipc"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/MicahParks/peakdetect"
+)
+
+const (
+	// SignalSuffix is appended to the name of a numeric column for the name of its signal column.
+	SignalSuffix = "_signal"
+	// ZScoreSuffix is appended to the name of a numeric column for the name of its z-score column.
+	ZScoreSuffix = "_zscore"
+)
+
+// Server is a flight.FlightServer that implements DoExchange by running peak detection over the numeric columns of the
+// record batches. Register it with flight.Server.RegisterFlightService.
+type Server struct {
+	flight.BaseFlightServer
+	config peakdetect.Config
+	mem    memory.Allocator
+}
+
+// NewServer creates a new Server. The cfg is used for streams that do not have a Config in their FlightDescriptor. Its
+// Lag must be set, as the first values of each column are used for initialization.
+func NewServer(cfg peakdetect.Config) (*Server, error) {
+	err := validateConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Server{
+		config: cfg,
+		mem:    memory.DefaultAllocator,
+	}, nil
+}
+
+// DoExchange implements flight.FlightServer. See the package documentation for the columns of the result batches.
+func (s *Server) DoExchange(stream flight.FlightService_DoExchangeServer) error {
+	reader, err := flight.NewRecordReader(stream, ipc.WithAllocator(s.mem))
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "failed to read the record batch stream: %s", err)
+	}
+	defer reader.Release()
+
+	cfg := s.config
+	if descriptor := reader.LatestFlightDescriptor(); descriptor != nil && len(descriptor.Cmd) != 0 {
+		cfg = peakdetect.Config{}
+		err = json.Unmarshal(descriptor.Cmd, &cfg)
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "failed to decode the config of the flight descriptor: %s", err)
+		}
+		err = validateConfig(cfg)
+		if err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	ex, err := newExchange(reader.Schema(), cfg)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	writer := flight.NewRecordWriter(stream, ipc.WithSchema(ex.schema), ipc.WithAllocator(s.mem))
+	for reader.Next() {
+		record, err := ex.process(reader.Record(), s.mem)
+		if err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		err = writer.Write(record)
+		record.Release()
+		if err != nil {
+			return fmt.Errorf("failed to write a record batch: %w", err)
+		}
+	}
+	if err = reader.Err(); err != nil {
+		return status.Errorf(codes.InvalidArgument, "failed to read a record batch: %s", err)
+	}
+	err = writer.Close()
+	if err != nil {
+		return fmt.Errorf("failed to finish the record batch stream: %w", err)
+	}
+	return nil
+}
+
+func validateConfig(cfg peakdetect.Config) error {
+	if cfg.Lag == 0 {
+		return fmt.Errorf("the lag must be set, as the first values of each column are used for initialization: %w", peakdetect.ErrInvalidConfig)
+	}
+	return cfg.Validate()
+}
+
+// exchange is the state of one DoExchange stream.
+type exchange struct {
+	columns []*column
+	config  peakdetect.Config
+	schema  *arrow.Schema
+}
+
+// column is the state of one numeric column of a stream.
+type column struct {
+	detector peakdetect.PeakDetector
+	index    int
+	warmup   []float64
+}
+
+func newExchange(schema *arrow.Schema, cfg peakdetect.Config) (*exchange, error) {
+	ex := &exchange{
+		config: cfg,
+	}
+	fields := schema.Fields()
+	for i, field := range schema.Fields() {
+		if !numeric(field.Type) {
+			continue
+		}
+		for _, name := range []string{field.Name + SignalSuffix, field.Name + ZScoreSuffix} {
+			if schema.HasField(name) {
+				return nil, fmt.Errorf("the column %q for the results of %q is already in the schema", name, field.Name)
+			}
+		}
+		fields = append(fields,
+			arrow.Field{Name: field.Name + SignalSuffix, Type: arrow.PrimitiveTypes.Int8},
+			arrow.Field{Name: field.Name + ZScoreSuffix, Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+		)
+		ex.columns = append(ex.columns, &column{
+			index: i,
+		})
+	}
+	metadata := schema.Metadata()
+	ex.schema = arrow.NewSchema(fields, &metadata)
+	return ex, nil
+}
+
+// process returns the record with the signal and z-score columns appended. The caller must release it.
+func (ex *exchange) process(record arrow.Record, mem memory.Allocator) (arrow.Record, error) {
+	rows := int(record.NumRows())
+	arrays := make([]arrow.Array, 0, len(ex.schema.Fields()))
+	arrays = append(arrays, record.Columns()...)
+
+	signals := array.NewInt8Builder(mem)
+	defer signals.Release()
+	zScores := array.NewFloat64Builder(mem)
+	defer zScores.Release()
+	var results []arrow.Array
+	defer func() {
+		for _, arr := range results {
+			arr.Release()
+		}
+	}()
+
+	for _, c := range ex.columns {
+		values := record.Column(c.index)
+		signals.Reserve(rows)
+		zScores.Reserve(rows)
+		for i := 0; i < rows; i++ {
+			v := value(values, i)
+			if c.detector == nil {
+				signals.UnsafeAppend(int8(peakdetect.SignalNeutral))
+				zScores.UnsafeAppendBoolToBitmap(false)
+				// The lag is not trusted for preallocation, as it can come from the client.
+				c.warmup = append(c.warmup, v)
+				if uint(len(c.warmup)) < ex.config.Lag+ex.config.DerivativeOrder {
+					continue
+				}
+				detector := peakdetect.NewPeakDetector()
+				err := detector.InitializeWithConfig(ex.config, c.warmup)
+				if err != nil {
+					return nil, fmt.Errorf("failed to initialize the detector of %q: %w", ex.schema.Field(c.index).Name, err)
+				}
+				c.detector = detector
+				c.warmup = nil
+				continue
+			}
+
+			result := c.detector.NextDetailed(v)
+			signals.UnsafeAppend(int8(result.Signal))
+			if math.IsNaN(v) || math.IsInf(v, 0) || math.IsNaN(result.ZScore) {
+				zScores.UnsafeAppendBoolToBitmap(false)
+			} else {
+				zScores.UnsafeAppend(result.ZScore)
+			}
+		}
+		results = append(results, signals.NewArray(), zScores.NewArray())
+	}
+
+	arrays = append(arrays, results...)
+	return array.NewRecord(ex.schema, arrays, int64(rows)), nil
+}
+
+func numeric(t arrow.DataType) bool {
+	switch t.ID() {
+	case arrow.FLOAT32, arrow.FLOAT64,
+		arrow.INT8, arrow.INT16, arrow.INT32, arrow.INT64,
+		arrow.UINT8, arrow.UINT16, arrow.UINT32, arrow.UINT64:
+		return true
+	}
+	return false
+}
+
+// value returns the value of a numeric array at i as a float64. Null values are NaN.
+func value(arr arrow.Array, i int) float64 {
+	if arr.IsNull(i) {
+		return math.NaN()
+	}
+	switch a := arr.(type) {
+	case *array.Float32:
+		return float64(a.Value(i))
+	case *array.Float64:
+		return a.Value(i)
+	case *array.Int8:
+		return float64(a.Value(i))
+	case *array.Int16:
+		return float64(a.Value(i))
+	case *array.Int32:
+		return float64(a.Value(i))
+	case *array.Int64:
+		return float64(a.Value(i))
+	case *array.Uint8:
+		return float64(a.Value(i))
+	case *array.Uint16:
+		return float64(a.Value(i))
+	case *array.Uint32:
+		return float64(a.Value(i))
+	case *array.Uint64:
+		return float64(a.Value(i))
+	}
+	return math.NaN()
+}
@@ -0,0 +1,260 @@
+package peakdetectflight_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"testing"
+
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/array"
+	"github.com/apache/arrow/go/v17/arrow/flight"
+	"github.com/apache/arrow/go/v17/arrow/ipc"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"github.com/MicahParks/peakdetect"
+	"github.com/MicahParks/peakdetect/peakdetectflight"
+)
+
+const logFmt = "%s\nError: %s"
+
+var inputSchema = arrow.NewSchema([]arrow.Field{
+	{Name: "host", Type: arrow.BinaryTypes.String},
+	{Name: "cpu", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+	{Name: "requests", Type: arrow.PrimitiveTypes.Int32},
+}, nil)
+
+func TestServer_DoExchange(t *testing.T) {
+	cfg := peakdetect.Config{
+		Influence: 0,
+		Lag:       10,
+		Threshold: 4,
+	}
+	client := startServer(t, cfg)
+
+	cpu := make([]float64, 100)
+	requests := make([]int32, len(cpu))
+	for i := range cpu {
+		cpu[i] = 1 + 0.1*math.Sin(float64(i))
+		requests[i] = int32(100 + i%3)
+	}
+	cpu[40], cpu[70] = 5, -3
+	requests[55] = 500
+
+	// The batches do not line up with the lag, so initialization spans them.
+	records := []arrow.Record{
+		newRecord(t, cpu[:7], requests[:7], -1),
+		newRecord(t, cpu[7:60], requests[7:60], 50-7),
+		newRecord(t, cpu[60:], requests[60:], -1),
+	}
+	results := exchange(t, client, nil, records)
+
+	cpuSignals, cpuZScores := resultColumns(t, results, "cpu")
+	requestSignals, _ := resultColumns(t, results, "requests")
+	// The null value is missing, so it is skipped by the detector and has a neutral signal and a null z-score.
+	expectedCPU := detect(t, cfg, append(append(append([]float64(nil), cpu[:50]...), math.NaN()), cpu[51:]...))
+	expectedRequests := make([]float64, len(requests))
+	for i, v := range requests {
+		expectedRequests[i] = float64(v)
+	}
+	for name, tc := range map[string]struct {
+		actual   []peakdetect.Signal
+		expected []peakdetect.Signal
+	}{
+		"cpu":      {actual: cpuSignals, expected: expectedCPU},
+		"requests": {actual: requestSignals, expected: detect(t, cfg, expectedRequests)},
+	} {
+		if len(tc.actual) != len(tc.expected) {
+			t.Fatalf("Incorrect number of signals for %s.\n  Expected: %d\n  Actual: %d", name, len(tc.expected), len(tc.actual))
+		}
+		for i := range tc.expected {
+			if tc.actual[i] != tc.expected[i] {
+				t.Fatalf("Incorrect signal for %s at index %d.\n  Expected: %d\n  Actual: %d", name, i, tc.expected[i], tc.actual[i])
+			}
+		}
+	}
+	if cpuSignals[40] != peakdetect.SignalPositive || cpuSignals[70] != peakdetect.SignalNegative || requestSignals[55] != peakdetect.SignalPositive {
+		t.Fatalf("The spikes should be signals.\n  Actual: %v\n  Actual: %v", cpuSignals, requestSignals)
+	}
+	for i, z := range cpuZScores {
+		if null := i < int(cfg.Lag) || i == 50; null != math.IsNaN(z) {
+			t.Fatalf("Incorrect z-score at index %d.\n  Actual: %f", i, z)
+		}
+	}
+	if z := cpuZScores[40]; z <= cfg.Threshold {
+		t.Fatalf("The z-score of the spike should exceed the threshold.\n  Actual: %f", z)
+	}
+	if host := results[0].Column(0).(*array.String).Value(0); host != "web-1" {
+		t.Fatalf("The host column should be passed through.\n  Actual: %s", host)
+	}
+}
+
+func TestServer_DoExchangeConfig(t *testing.T) {
+	client := startServer(t, peakdetect.Config{Lag: 10, Threshold: 1000})
+	values := make([]float64, 30)
+	for i := range values {
+		values[i] = float64(i % 2)
+	}
+	values[20] = 10
+
+	records := []arrow.Record{newRecord(t, values, make([]int32, len(values)), -1)}
+	results := exchange(t, client, &flight.FlightDescriptor{
+		Type: flight.DescriptorCMD,
+		Cmd:  []byte(`{"lag": 5, "threshold": 3, "influence": 0}`),
+	}, records)
+	signals, _ := resultColumns(t, results, "cpu")
+	if signals[20] != peakdetect.SignalPositive {
+		t.Fatalf("The config of the flight descriptor should be used.\n  Actual: %v", signals)
+	}
+
+	for _, cmd := range []string{`{"lag": 0, "threshold": 3}`, `{"lag": 5, "influence": 2}`, `not json`} {
+		stream, err := client.DoExchange(context.Background())
+		if err != nil {
+			t.Fatalf(logFmt, "Failed to start the exchange.", err)
+		}
+		writer := flight.NewRecordWriter(stream, ipc.WithSchema(inputSchema))
+		writer.SetFlightDescriptor(&flight.FlightDescriptor{Type: flight.DescriptorCMD, Cmd: []byte(cmd)})
+		_ = writer.Write(newRecord(t, values, make([]int32, len(values)), -1))
+		_ = writer.Close()
+		_ = stream.CloseSend()
+		_, err = flight.NewRecordReader(stream)
+		if status.Code(err) != codes.InvalidArgument {
+			t.Fatalf("An invalid config %s did not produce error.\n  Expected: %s\n  Actual: %v", cmd, codes.InvalidArgument, err)
+		}
+	}
+}
+
+func TestNewServer(t *testing.T) {
+	for _, cfg := range []peakdetect.Config{{Threshold: 3}, {Lag: 5, Threshold: -1}} {
+		_, err := peakdetectflight.NewServer(cfg)
+		if !errors.Is(err, peakdetect.ErrInvalidConfig) {
+			t.Fatalf("Invalid config %+v did not produce error.\n  Expected: %s\n  Actual: %v", cfg, peakdetect.ErrInvalidConfig, err)
+		}
+	}
+}
+
+func startServer(t *testing.T, cfg peakdetect.Config) flight.Client {
+	server, err := peakdetectflight.NewServer(cfg)
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to create the server.", err)
+	}
+	s := flight.NewServerWithMiddleware(nil)
+	err = s.Init("localhost:0")
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to listen.", err)
+	}
+	s.RegisterFlightService(server)
+	go func() {
+		_ = s.Serve()
+	}()
+	t.Cleanup(s.Shutdown)
+
+	client, err := flight.NewClientWithMiddleware(s.Addr().String(), nil, nil, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to create the client.", err)
+	}
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+	return client
+}
+
+// newRecord creates a record of the input schema. The cpu value at null, if it is not negative, is null.
+func newRecord(t *testing.T, cpu []float64, requests []int32, null int) arrow.Record {
+	b := array.NewRecordBuilder(memory.DefaultAllocator, inputSchema)
+	defer b.Release()
+	for i, v := range cpu {
+		b.Field(0).(*array.StringBuilder).Append("web-1")
+		if i == null {
+			b.Field(1).AppendNull()
+		} else {
+			b.Field(1).(*array.Float64Builder).Append(v)
+		}
+		b.Field(2).(*array.Int32Builder).Append(requests[i])
+	}
+	record := b.NewRecord()
+	t.Cleanup(record.Release)
+	return record
+}
+
+func exchange(t *testing.T, client flight.Client, descriptor *flight.FlightDescriptor, records []arrow.Record) []arrow.Record {
+	stream, err := client.DoExchange(context.Background())
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to start the exchange.", err)
+	}
+	writer := flight.NewRecordWriter(stream, ipc.WithSchema(inputSchema))
+	if descriptor != nil {
+		writer.SetFlightDescriptor(descriptor)
+	}
+	for _, record := range records {
+		err = writer.Write(record)
+		if err != nil {
+			t.Fatalf(logFmt, "Failed to write a record batch.", err)
+		}
+	}
+	err = writer.Close()
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to finish writing.", err)
+	}
+	err = stream.CloseSend()
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to close the stream.", err)
+	}
+
+	reader, err := flight.NewRecordReader(stream)
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to read the results.", err)
+	}
+	defer reader.Release()
+	var results []arrow.Record
+	for reader.Next() {
+		record := reader.Record()
+		record.Retain()
+		t.Cleanup(record.Release)
+		results = append(results, record)
+	}
+	if err = reader.Err(); err != nil && !errors.Is(err, io.EOF) {
+		t.Fatalf(logFmt, "Failed to read a result batch.", err)
+	}
+	if len(results) != len(records) {
+		t.Fatalf("Incorrect number of result batches.\n  Expected: %d\n  Actual: %d", len(records), len(results))
+	}
+	return results
+}
+
+// resultColumns returns the signals and z-scores of a column across the results. Null z-scores are NaN.
+func resultColumns(t *testing.T, results []arrow.Record, name string) ([]peakdetect.Signal, []float64) {
+	var signals []peakdetect.Signal
+	var zScores []float64
+	for _, record := range results {
+		signalIndices := record.Schema().FieldIndices(name + peakdetectflight.SignalSuffix)
+		zScoreIndices := record.Schema().FieldIndices(name + peakdetectflight.ZScoreSuffix)
+		if len(signalIndices) != 1 || len(zScoreIndices) != 1 {
+			t.Fatalf("The results do not have the columns of %s.\n  Actual: %s", name, record.Schema())
+		}
+		signalColumn := record.Column(signalIndices[0]).(*array.Int8)
+		zScoreColumn := record.Column(zScoreIndices[0]).(*array.Float64)
+		for i := 0; i < int(record.NumRows()); i++ {
+			signals = append(signals, peakdetect.Signal(signalColumn.Value(i)))
+			if zScoreColumn.IsNull(i) {
+				zScores = append(zScores, math.NaN())
+			} else {
+				zScores = append(zScores, zScoreColumn.Value(i))
+			}
+		}
+	}
+	return signals, zScores
+}
+
+func detect(t *testing.T, cfg peakdetect.Config, values []float64) []peakdetect.Signal {
+	signals, _, err := peakdetect.Detect(values, cfg)
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to detect.", err)
+	}
+	return signals
+}
@@ -0,0 +1,395 @@
+// Package peakdetectio streams values from an io.Reader in CSV, JSON Lines, or newline-delimited format into a
+// peakdetect.PeakDetector.
+package peakdetectio
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MicahParks/peakdetect"
+)
+
+const (
+	// FormatLines is one value per line, with no header. An empty line is a missing value.
+	FormatLines Format = iota
+	// FormatCSV is comma-separated values with a header row. An empty cell is a missing value.
+	FormatCSV
+	// FormatJSONL is one JSON object per line, also known as JSON Lines. A null or absent value is a missing value.
+	FormatJSONL
+)
+
+// SignalField is the name of the column or field added to annotated records.
+const SignalField = "signal"
+
+var (
+	// ErrMissingField indicates that a field required by the Config is not in the header of the CSV.
+	ErrMissingField = errors.New("the field is not in the header")
+	// ErrInvalidRecord indicates that a record could not be parsed.
+	ErrInvalidRecord = errors.New("the record is invalid")
+)
+
+// Format is a set of enums that indicates the format of the input.
+type Format uint8
+
+// Config describes the input read by the functions in this package.
+type Config struct {
+	// Format is the format of the input.
+	Format Format
+	// ValueField is the name of the CSV column or JSON field with the values. For FormatCSV it defaults to the first
+	// column. For FormatJSONL it defaults to "value". It is not used for FormatLines.
+	ValueField string
+	// TimeField is the name of the CSV column or JSON field with the timestamps of the values in RFC 3339 format. It is
+	// optional. It is not used for FormatLines.
+	TimeField string
+}
+
+// Read reads every value from r and gives it to the detector, calling handle with the event for each value. The index of
+// an event counts every record after the header, starting at zero. The detector must be initialized. Missing values are
+// given to PeakDetector.NextMaybe, so they are handled according to the Config.Missing of the detector, and the Value of
+// their events is NaN. If handle returns an error, reading stops and the error is returned.
+func Read(r io.Reader, cfg Config, detector peakdetect.PeakDetector, handle func(event peakdetect.SignalEvent) error) error {
+	src, err := newSource(r, nil, cfg)
+	if err != nil {
+		return err
+	}
+	return run(src, detector, handle)
+}
+
+// Annotate reads every value from r like Read and writes each record to w in the same format with its signal added.
+// For FormatCSV and FormatLines, the signal is added as the last column, named SignalField in the header of a CSV. For
+// FormatJSONL, the signal is added as the SignalField field of each object.
+func Annotate(r io.Reader, w io.Writer, cfg Config, detector peakdetect.PeakDetector) error {
+	src, err := newSource(r, w, cfg)
+	if err != nil {
+		return err
+	}
+	err = run(src, detector, func(event peakdetect.SignalEvent) error {
+		return src.annotate(event.Signal)
+	})
+	if err != nil {
+		return err
+	}
+	return src.flush()
+}
+
+// ReadValues reads every value from r. Missing values are NaN. This is useful to gather initial values, or to use with
+// peakdetect.Detect for a whole series.
+func ReadValues(r io.Reader, cfg Config) ([]float64, error) {
+	src, err := newSource(r, nil, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var values []float64
+	for {
+		rec, err := src.next()
+		if errors.Is(err, io.EOF) {
+			return values, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !rec.present {
+			rec.value = math.NaN()
+		}
+		values = append(values, rec.value)
+	}
+}
+
+func run(src source, detector peakdetect.PeakDetector, handle func(event peakdetect.SignalEvent) error) error {
+	if detector.Config().Lag == 0 {
+		return fmt.Errorf("the detector must be initialized: %w", peakdetect.ErrInvalidConfig)
+	}
+
+	for index := uint64(0); ; index++ {
+		rec, err := src.next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		signal, err := detector.NextMaybe(rec.value, rec.present)
+		if err != nil {
+			return fmt.Errorf("failed to process record %d: %w", index, err)
+		}
+		if !rec.present {
+			rec.value = math.NaN()
+		}
+
+		err = handle(peakdetect.SignalEvent{
+			Index:  index,
+			Labels: detector.Labels(),
+			Signal: signal,
+			Time:   rec.time,
+			Value:  rec.value,
+		})
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// record is a value read from the input.
+type record struct {
+	present bool
+	time    time.Time
+	value   float64
+}
+
+// source reads records in one format. If it was created with a writer, annotate writes the last record read with its
+// signal.
+type source interface {
+	next() (record, error)
+	annotate(signal peakdetect.Signal) error
+	flush() error
+}
+
+func newSource(r io.Reader, w io.Writer, cfg Config) (source, error) {
+	switch cfg.Format {
+	case FormatLines:
+		return newLinesSource(r, w), nil
+	case FormatCSV:
+		return newCSVSource(r, w, cfg)
+	case FormatJSONL:
+		return newJSONLSource(r, w, cfg), nil
+	default:
+		return nil, fmt.Errorf("unknown format %d: %w", cfg.Format, peakdetect.ErrInvalidConfig)
+	}
+}
+
+func parseValue(s string, line int) (record, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return record{}, nil
+	}
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return record{}, fmt.Errorf("failed to parse the value %q on line %d: %w", s, line, ErrInvalidRecord)
+	}
+	return record{present: true, value: value}, nil
+}
+
+func parseTime(s string, line int) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(s))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse the time %q on line %d: %w", s, line, ErrInvalidRecord)
+	}
+	return t, nil
+}
+
+type linesSource struct {
+	line    int
+	scanner *bufio.Scanner
+	w       *bufio.Writer
+}
+
+func newLinesSource(r io.Reader, w io.Writer) *linesSource {
+	l := &linesSource{
+		scanner: bufio.NewScanner(r),
+	}
+	if w != nil {
+		l.w = bufio.NewWriter(w)
+	}
+	return l
+}
+
+func (l *linesSource) next() (record, error) {
+	if !l.scanner.Scan() {
+		err := l.scanner.Err()
+		if err != nil {
+			return record{}, fmt.Errorf("failed to read line %d: %w", l.line+1, err)
+		}
+		return record{}, io.EOF
+	}
+	l.line++
+	return parseValue(l.scanner.Text(), l.line)
+}
+
+func (l *linesSource) annotate(signal peakdetect.Signal) error {
+	_, err := fmt.Fprintf(l.w, "%s,%d\n", strings.TrimSpace(l.scanner.Text()), signal)
+	return err
+}
+
+func (l *linesSource) flush() error {
+	return l.w.Flush()
+}
+
+type csvSource struct {
+	current     []string
+	reader      *csv.Reader
+	timeColumn  int
+	valueColumn int
+	w           *csv.Writer
+}
+
+func newCSVSource(r io.Reader, w io.Writer, cfg Config) (*csvSource, error) {
+	c := &csvSource{
+		reader:     csv.NewReader(r),
+		timeColumn: -1,
+	}
+
+	header, err := c.reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the CSV header: %w", err)
+	}
+	if cfg.ValueField != "" {
+		c.valueColumn = columnIndex(header, cfg.ValueField)
+		if c.valueColumn == -1 {
+			return nil, fmt.Errorf("value field %q: %w", cfg.ValueField, ErrMissingField)
+		}
+	}
+	if cfg.TimeField != "" {
+		c.timeColumn = columnIndex(header, cfg.TimeField)
+		if c.timeColumn == -1 {
+			return nil, fmt.Errorf("time field %q: %w", cfg.TimeField, ErrMissingField)
+		}
+	}
+
+	if w != nil {
+		c.w = csv.NewWriter(w)
+		err = c.w.Write(append(header, SignalField))
+		if err != nil {
+			return nil, fmt.Errorf("failed to write the CSV header: %w", err)
+		}
+	}
+	return c, nil
+}
+
+func (c *csvSource) next() (record, error) {
+	fields, err := c.reader.Read()
+	if errors.Is(err, io.EOF) {
+		return record{}, io.EOF
+	}
+	if err != nil {
+		return record{}, fmt.Errorf("failed to read the CSV: %w", err)
+	}
+	c.current = fields
+	line, _ := c.reader.FieldPos(0)
+
+	rec, err := parseValue(fields[c.valueColumn], line)
+	if err != nil {
+		return record{}, err
+	}
+	if c.timeColumn != -1 {
+		rec.time, err = parseTime(fields[c.timeColumn], line)
+		if err != nil {
+			return record{}, err
+		}
+	}
+	return rec, nil
+}
+
+func (c *csvSource) annotate(signal peakdetect.Signal) error {
+	return c.w.Write(append(c.current, strconv.Itoa(int(signal))))
+}
+
+func (c *csvSource) flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func columnIndex(header []string, name string) int {
+	for i, column := range header {
+		if column == name {
+			return i
+		}
+	}
+	return -1
+}
+
+type jsonlSource struct {
+	current    map[string]json.RawMessage
+	encoder    *json.Encoder
+	line       int
+	scanner    *bufio.Scanner
+	timeField  string
+	valueField string
+	w          *bufio.Writer
+}
+
+func newJSONLSource(r io.Reader, w io.Writer, cfg Config) *jsonlSource {
+	j := &jsonlSource{
+		scanner:    bufio.NewScanner(r),
+		timeField:  cfg.TimeField,
+		valueField: cfg.ValueField,
+	}
+	if j.valueField == "" {
+		j.valueField = "value"
+	}
+	if w != nil {
+		j.w = bufio.NewWriter(w)
+		j.encoder = json.NewEncoder(j.w)
+	}
+	return j
+}
+
+func (j *jsonlSource) next() (record, error) {
+	for {
+		if !j.scanner.Scan() {
+			err := j.scanner.Err()
+			if err != nil {
+				return record{}, fmt.Errorf("failed to read line %d: %w", j.line+1, err)
+			}
+			return record{}, io.EOF
+		}
+		j.line++
+		if strings.TrimSpace(j.scanner.Text()) != "" {
+			break
+		}
+	}
+
+	j.current = nil
+	err := json.Unmarshal(j.scanner.Bytes(), &j.current)
+	if err != nil {
+		return record{}, fmt.Errorf("failed to parse the JSON object on line %d: %w", j.line, errors.Join(ErrInvalidRecord, err))
+	}
+	if j.current == nil {
+		return record{}, fmt.Errorf("the JSON on line %d is not an object: %w", j.line, ErrInvalidRecord)
+	}
+
+	var rec record
+	var value *float64
+	raw, ok := j.current[j.valueField]
+	if ok {
+		err = json.Unmarshal(raw, &value)
+		if err != nil {
+			return record{}, fmt.Errorf("failed to parse the value %s on line %d: %w", raw, j.line, ErrInvalidRecord)
+		}
+	}
+	if value != nil {
+		rec.present = true
+		rec.value = *value
+	}
+
+	if j.timeField != "" {
+		var t string
+		err = json.Unmarshal(j.current[j.timeField], &t)
+		if err != nil {
+			return record{}, fmt.Errorf("failed to parse the time %s on line %d: %w", j.current[j.timeField], j.line, ErrInvalidRecord)
+		}
+		rec.time, err = parseTime(t, j.line)
+		if err != nil {
+			return record{}, err
+		}
+	}
+	return rec, nil
+}
+
+func (j *jsonlSource) annotate(signal peakdetect.Signal) error {
+	j.current[SignalField] = json.RawMessage(strconv.Itoa(int(signal)))
+	return j.encoder.Encode(j.current)
+}
+
+func (j *jsonlSource) flush() error {
+	return j.w.Flush()
+}
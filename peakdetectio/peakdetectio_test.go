@@ -0,0 +1,178 @@
+package peakdetectio_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MicahParks/peakdetect"
+	"github.com/MicahParks/peakdetect/peakdetectio"
+)
+
+const logFmt = "%s\nError: %s"
+
+var (
+	initialValues = []float64{1, 1, 1.1, 1, 0.9}
+	inputValues   = []float64{1, 5, 1}
+	inputSignals  = []peakdetect.Signal{peakdetect.SignalNeutral, peakdetect.SignalPositive, peakdetect.SignalNeutral}
+)
+
+func newDetector(t *testing.T) peakdetect.PeakDetector {
+	detector := peakdetect.NewPeakDetector()
+	err := detector.Initialize(0, 3, initialValues)
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+	return detector
+}
+
+func TestRead(t *testing.T) {
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	var csvInput, jsonlInput, linesInput strings.Builder
+	csvInput.WriteString("time,name,value\n")
+	for i, v := range inputValues {
+		ts := start.Add(time.Duration(i) * time.Second).Format(time.RFC3339)
+		_, _ = fmt.Fprintf(&csvInput, "%s,cpu,%v\n", ts, v)
+		_, _ = fmt.Fprintf(&jsonlInput, "{\"time\":%q,\"v\":%v}\n", ts, v)
+		_, _ = fmt.Fprintf(&linesInput, "%v\n", v)
+	}
+
+	tests := map[string]struct {
+		cfg   peakdetectio.Config
+		input string
+		times bool
+	}{
+		"CSV":   {cfg: peakdetectio.Config{Format: peakdetectio.FormatCSV, ValueField: "value", TimeField: "time"}, input: csvInput.String(), times: true},
+		"JSONL": {cfg: peakdetectio.Config{Format: peakdetectio.FormatJSONL, ValueField: "v", TimeField: "time"}, input: jsonlInput.String(), times: true},
+		"Lines": {cfg: peakdetectio.Config{Format: peakdetectio.FormatLines}, input: linesInput.String()},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var events []peakdetect.SignalEvent
+			err := peakdetectio.Read(strings.NewReader(tc.input), tc.cfg, newDetector(t), func(event peakdetect.SignalEvent) error {
+				events = append(events, event)
+				return nil
+			})
+			if err != nil {
+				t.Fatalf(logFmt, "Failed to read.", err)
+			}
+
+			if len(events) != len(inputValues) {
+				t.Fatalf("Incorrect number of events.\n  Expected: %d\n  Actual: %d", len(inputValues), len(events))
+			}
+			for i, event := range events {
+				if event.Index != uint64(i) || event.Value != inputValues[i] || event.Signal != inputSignals[i] {
+					t.Fatalf("Incorrect event at index %d.\n  Actual: %+v", i, event)
+				}
+				expected := time.Time{}
+				if tc.times {
+					expected = start.Add(time.Duration(i) * time.Second)
+				}
+				if !event.Time.Equal(expected) {
+					t.Fatalf("Incorrect time at index %d.\n  Expected: %s\n  Actual: %s", i, expected, event.Time)
+				}
+			}
+		})
+	}
+}
+
+func TestRead_Missing(t *testing.T) {
+	detector := peakdetect.NewPeakDetector()
+	err := detector.InitializeWithConfig(peakdetect.Config{Threshold: 3, Missing: peakdetect.MissingReject}, initialValues)
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+
+	var events []peakdetect.SignalEvent
+	handle := func(event peakdetect.SignalEvent) error {
+		events = append(events, event)
+		return nil
+	}
+	err = peakdetectio.Read(strings.NewReader("value,name\n1,cpu\n,cpu\n5,cpu\n"), peakdetectio.Config{Format: peakdetectio.FormatCSV}, detector, handle)
+	if !errors.Is(err, peakdetect.ErrMissingValue) {
+		t.Fatalf("Missing value was not rejected.\n  Expected: %s\n  Actual: %v", peakdetect.ErrMissingValue, err)
+	}
+
+	events = nil
+	err = peakdetectio.Read(strings.NewReader("{\"value\":1}\n{\"value\":null}\n{}\n"), peakdetectio.Config{Format: peakdetectio.FormatJSONL}, newDetector(t), handle)
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to read.", err)
+	}
+	if len(events) != 3 || !math.IsNaN(events[1].Value) || !math.IsNaN(events[2].Value) {
+		t.Fatalf("Missing values did not produce events with NaN values.\n  Actual: %+v", events)
+	}
+}
+
+func TestAnnotate(t *testing.T) {
+	tests := map[string]struct {
+		cfg      peakdetectio.Config
+		input    string
+		expected string
+	}{
+		"CSV": {
+			cfg:      peakdetectio.Config{Format: peakdetectio.FormatCSV, ValueField: "value"},
+			input:    "name,value\ncpu,1\ncpu,5\ncpu,1\n",
+			expected: "name,value,signal\ncpu,1,0\ncpu,5,1\ncpu,1,0\n",
+		},
+		"JSONL": {
+			cfg:      peakdetectio.Config{Format: peakdetectio.FormatJSONL},
+			input:    "{\"value\":1}\n{\"value\":5}\n{\"value\":1}\n",
+			expected: "{\"signal\":0,\"value\":1}\n{\"signal\":1,\"value\":5}\n{\"signal\":0,\"value\":1}\n",
+		},
+		"Lines": {
+			cfg:      peakdetectio.Config{Format: peakdetectio.FormatLines},
+			input:    "1\n5\n1\n",
+			expected: "1,0\n5,1\n1,0\n",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var output bytes.Buffer
+			err := peakdetectio.Annotate(strings.NewReader(tc.input), &output, tc.cfg, newDetector(t))
+			if err != nil {
+				t.Fatalf(logFmt, "Failed to annotate.", err)
+			}
+			if output.String() != tc.expected {
+				t.Fatalf("Incorrect annotated output.\n  Expected: %q\n  Actual: %q", tc.expected, output.String())
+			}
+		})
+	}
+}
+
+func TestReadValues(t *testing.T) {
+	values, err := peakdetectio.ReadValues(strings.NewReader("1\n\n2.5\n"), peakdetectio.Config{})
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to read values.", err)
+	}
+	if len(values) != 3 || values[0] != 1 || !math.IsNaN(values[1]) || values[2] != 2.5 {
+		t.Fatalf("Incorrect values.\n  Actual: %v", values)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	_, err := peakdetectio.ReadValues(strings.NewReader("value\n"), peakdetectio.Config{Format: peakdetectio.FormatCSV, ValueField: "missing"})
+	if !errors.Is(err, peakdetectio.ErrMissingField) {
+		t.Fatalf("Missing field did not produce the correct error.\n  Expected: %s\n  Actual: %v", peakdetectio.ErrMissingField, err)
+	}
+
+	for _, input := range []string{"1\nabc\n", "1\n1e\n"} {
+		_, err = peakdetectio.ReadValues(strings.NewReader(input), peakdetectio.Config{})
+		if !errors.Is(err, peakdetectio.ErrInvalidRecord) {
+			t.Fatalf("Invalid value did not produce the correct error.\n  Expected: %s\n  Actual: %v", peakdetectio.ErrInvalidRecord, err)
+		}
+	}
+
+	_, err = peakdetectio.ReadValues(strings.NewReader("null\n"), peakdetectio.Config{Format: peakdetectio.FormatJSONL})
+	if !errors.Is(err, peakdetectio.ErrInvalidRecord) {
+		t.Fatalf("JSON that is not an object did not produce the correct error.\n  Expected: %s\n  Actual: %v", peakdetectio.ErrInvalidRecord, err)
+	}
+
+	err = peakdetectio.Read(strings.NewReader("1\n"), peakdetectio.Config{}, peakdetect.NewPeakDetector(), func(peakdetect.SignalEvent) error { return nil })
+	if !errors.Is(err, peakdetect.ErrInvalidConfig) {
+		t.Fatalf("Uninitialized detector did not produce the correct error.\n  Expected: %s\n  Actual: %v", peakdetect.ErrInvalidConfig, err)
+	}
+}
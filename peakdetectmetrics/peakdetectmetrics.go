@@ -0,0 +1,351 @@
+// Package peakdetectmetrics exports the internal state of many peakdetect.PeakDetectors as Prometheus metrics or an
+// expvar variable, without any dependencies outside the standard library.
+//
+// A Collector is given to each PeakDetector with Collector.Register. Each distinct set of labels of the PeakDetectors
+// is its own series. The following metrics are exported:
+//
+//   - peakdetect_signals_total, a counter of the values processed with a signal label of positive, negative, or neutral.
+//   - peakdetect_mean, a gauge of the moving mean used for the most recent value.
+//   - peakdetect_stddev, a gauge of the moving standard deviation used for the most recent value.
+//   - peakdetect_zscore_magnitude, a histogram of the absolute z-scores of the values. Z-scores that are not finite,
+//     such as for a value after a constant lag window, are not observed, as they would make the sum infinite forever.
+//
+// The labels of the PeakDetectors are written as Prometheus labels. The names signal and le are used by the metrics
+// themselves, so labels with those names are written with a label_ prefix, such as label_signal. Characters that are
+// not valid in a Prometheus label name are replaced with underscores. Different names can be written the same way, such
+// as a-b and a_b, which Collector.Register checks for.
+package peakdetectmetrics
+
+import (
+	"bufio"
+	"errors"
+	"expvar"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/MicahParks/peakdetect"
+)
+
+// ErrLabelCollision indicates that different label names are written as the same Prometheus label name, such as a-b and
+// a_b, or signal and label_signal.
+var ErrLabelCollision = errors.New("the label names are the same once written as Prometheus label names")
+
+// DefaultBuckets are the default upper bounds of the buckets of the z-score magnitude histogram.
+var DefaultBuckets = []float64{0.5, 1, 2, 3, 4, 5, 7.5, 10, 20}
+
+// Collector implements peakdetect.Metrics. It is safe for concurrent use, so one Collector can be shared by every
+// PeakDetector in a service.
+type Collector struct {
+	buckets []float64
+	// labels are the labels of every series and registered PeakDetector, keyed by how they are written.
+	labels map[string]map[string]string
+	mux    sync.Mutex
+	series map[string]*series
+}
+
+// series is the state of the metrics for one set of labels.
+type series struct {
+	bucketCounts []uint64
+	labels       map[string]string
+	mean         float64
+	signals      [3]uint64
+	stdDev       float64
+	zCount       uint64
+	zSum         float64
+}
+
+// NewCollector creates a new Collector. The buckets are the upper bounds of the buckets of the z-score magnitude
+// histogram. They do not need to be sorted. If there are none, DefaultBuckets are used.
+func NewCollector(buckets []float64) *Collector {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+	return &Collector{
+		buckets: sorted,
+		labels:  make(map[string]map[string]string),
+		series:  make(map[string]*series),
+	}
+}
+
+// Register sets the Collector as the Metrics of the detector, like PeakDetector.SetMetrics, after checking that its
+// labels can be written as Prometheus labels. An error wrapping ErrLabelCollision is returned if two of its label names
+// are written the same way, or if its labels are written the same way as different labels of another PeakDetector, as
+// their series could not be told apart. The labels must be set before the detector is registered.
+func (c *Collector) Register(detector peakdetect.PeakDetector) error {
+	labels := detector.Labels()
+	key, err := labelString(labels)
+	if err != nil {
+		return err
+	}
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	err = c.claim(key, labels)
+	if err != nil {
+		return err
+	}
+	detector.SetMetrics(c)
+	return nil
+}
+
+// Observe implements peakdetect.Metrics. Values of a PeakDetector whose labels would be rejected by Register are not
+// observed, as they would be written as invalid or duplicate series.
+func (c *Collector) Observe(labels map[string]string, result peakdetect.Result) {
+	key, err := labelString(labels)
+	if err != nil {
+		return
+	}
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	s, ok := c.series[key]
+	if !ok || !maps.Equal(s.labels, labels) {
+		if c.claim(key, labels) != nil {
+			return
+		}
+	}
+	if !ok {
+		s = &series{
+			bucketCounts: make([]uint64, len(c.buckets)),
+			labels:       labels,
+		}
+		c.series[key] = s
+	}
+
+	s.signals[result.Signal+1]++
+	s.mean = result.Mean
+	s.stdDev = result.StdDev
+	magnitude := math.Abs(result.ZScore)
+	if math.IsNaN(magnitude) || math.IsInf(magnitude, 0) {
+		return
+	}
+	s.zCount++
+	s.zSum += magnitude
+	for i, bound := range c.buckets {
+		if magnitude <= bound {
+			s.bucketCounts[i]++
+		}
+	}
+}
+
+// claim records the labels that are written as the key. The mutex must be held.
+func (c *Collector) claim(key string, labels map[string]string) error {
+	if existing, ok := c.labels[key]; ok && !maps.Equal(existing, labels) {
+		return fmt.Errorf("the labels %v are written the same as the labels %v: %w", labels, existing, ErrLabelCollision)
+	}
+	c.labels[key] = labels
+	return nil
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format, so the Collector can be registered as the
+// handler of a metrics endpoint.
+func (c *Collector) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = c.WritePrometheus(w)
+}
+
+// WritePrometheus writes the metrics in the Prometheus text exposition format.
+func (c *Collector) WritePrometheus(w io.Writer) error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	keys := make([]string, 0, len(c.series))
+	for key := range c.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	buf := bufio.NewWriter(w)
+	_, _ = fmt.Fprint(buf, "# HELP peakdetect_signals_total The number of values processed by signal.\n# TYPE peakdetect_signals_total counter\n")
+	for _, key := range keys {
+		s := c.series[key]
+		for i, name := range []string{"negative", "neutral", "positive"} {
+			_, _ = fmt.Fprintf(buf, "peakdetect_signals_total%s %d\n", withLabel(key, "signal", name), s.signals[i])
+		}
+	}
+
+	_, _ = fmt.Fprint(buf, "# HELP peakdetect_mean The moving mean used for the most recent value.\n# TYPE peakdetect_mean gauge\n")
+	for _, key := range keys {
+		_, _ = fmt.Fprintf(buf, "peakdetect_mean%s %s\n", key, formatFloat(c.series[key].mean))
+	}
+
+	_, _ = fmt.Fprint(buf, "# HELP peakdetect_stddev The moving standard deviation used for the most recent value.\n# TYPE peakdetect_stddev gauge\n")
+	for _, key := range keys {
+		_, _ = fmt.Fprintf(buf, "peakdetect_stddev%s %s\n", key, formatFloat(c.series[key].stdDev))
+	}
+
+	_, _ = fmt.Fprint(buf, "# HELP peakdetect_zscore_magnitude The absolute z-scores of the values.\n# TYPE peakdetect_zscore_magnitude histogram\n")
+	for _, key := range keys {
+		s := c.series[key]
+		for i, bound := range c.buckets {
+			_, _ = fmt.Fprintf(buf, "peakdetect_zscore_magnitude_bucket%s %d\n", withLabel(key, "le", formatFloat(bound)), s.bucketCounts[i])
+		}
+		_, _ = fmt.Fprintf(buf, "peakdetect_zscore_magnitude_bucket%s %d\n", withLabel(key, "le", "+Inf"), s.zCount)
+		_, _ = fmt.Fprintf(buf, "peakdetect_zscore_magnitude_sum%s %s\n", key, formatFloat(s.zSum))
+		_, _ = fmt.Fprintf(buf, "peakdetect_zscore_magnitude_count%s %d\n", key, s.zCount)
+	}
+
+	return buf.Flush()
+}
+
+// SeriesSnapshot is a snapshot of the metrics for one set of labels.
+type SeriesSnapshot struct {
+	Labels    map[string]string
+	Mean      float64
+	Negative  uint64
+	Neutral   uint64
+	Positive  uint64
+	StdDev    float64
+	ZScoreSum float64
+	// ZScoreBuckets are the cumulative counts of the z-score magnitude histogram, keyed by their upper bound.
+	ZScoreBuckets map[string]uint64
+	ZScoreCount   uint64
+}
+
+// Snapshot returns a snapshot of the metrics of every series, ordered by their labels.
+func (c *Collector) Snapshot() []SeriesSnapshot {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	keys := make([]string, 0, len(c.series))
+	for key := range c.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	snapshots := make([]SeriesSnapshot, 0, len(keys))
+	for _, key := range keys {
+		s := c.series[key]
+		buckets := make(map[string]uint64, len(c.buckets))
+		for i, bound := range c.buckets {
+			buckets[formatFloat(bound)] = s.bucketCounts[i]
+		}
+		snapshots = append(snapshots, SeriesSnapshot{
+			Labels:        s.labels,
+			Mean:          s.mean,
+			Negative:      s.signals[0],
+			Neutral:       s.signals[1],
+			Positive:      s.signals[2],
+			StdDev:        s.stdDev,
+			ZScoreSum:     s.zSum,
+			ZScoreBuckets: buckets,
+			ZScoreCount:   s.zCount,
+		})
+	}
+	return snapshots
+}
+
+// Var returns an expvar.Var with the Snapshot of the Collector as JSON, such as to use with expvar.Publish. Values that
+// are not finite, such as a moving mean of NaN, are written as null.
+func (c *Collector) Var() expvar.Var {
+	return expvar.Func(func() any {
+		snapshots := c.Snapshot()
+		out := make([]map[string]any, len(snapshots))
+		for i, s := range snapshots {
+			out[i] = map[string]any{
+				"labels":        s.Labels,
+				"mean":          finite(s.Mean),
+				"negative":      s.Negative,
+				"neutral":       s.Neutral,
+				"positive":      s.Positive,
+				"stdDev":        finite(s.StdDev),
+				"zScoreBuckets": s.ZScoreBuckets,
+				"zScoreCount":   s.ZScoreCount,
+				"zScoreSum":     finite(s.ZScoreSum),
+			}
+		}
+		return out
+	})
+}
+
+func finite(f float64) any {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil
+	}
+	return f
+}
+
+func formatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	case math.IsNaN(f):
+		return "NaN"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// labelString returns the labels in the Prometheus text format, sorted by name, such as {series="cpu"}. Characters that
+// are not valid in a label name are replaced with underscores, and names used by the metrics themselves are prefixed.
+// An error wrapping ErrLabelCollision is returned if two names are written the same way.
+func labelString(labels map[string]string) (string, error) {
+	if len(labels) == 0 {
+		return "", nil
+	}
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	written := make(map[string]string, len(names))
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, name := range names {
+		written[labelName(name)] = name
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(labelName(name))
+		b.WriteString(`="`)
+		b.WriteString(labelValueReplacer.Replace(labels[name]))
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	if len(written) != len(names) {
+		for _, name := range names {
+			if other := written[labelName(name)]; other != name {
+				return "", fmt.Errorf("the label names %q and %q are both written as %q: %w", other, name, labelName(name), ErrLabelCollision)
+			}
+		}
+	}
+	return b.String(), nil
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func labelName(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		valid := r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || i > 0 && r >= '0' && r <= '9'
+		if !valid {
+			r = '_'
+		}
+		b.WriteRune(r)
+	}
+	switch b.String() {
+	case "le", "signal":
+		return "label_" + b.String()
+	}
+	return b.String()
+}
+
+// withLabel adds a label to a label string.
+func withLabel(key, name, value string) string {
+	label := name + `="` + value + `"`
+	if key == "" {
+		return "{" + label + "}"
+	}
+	return key[:len(key)-1] + "," + label + "}"
+}
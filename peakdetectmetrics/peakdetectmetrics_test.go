@@ -0,0 +1,146 @@
+package peakdetectmetrics_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/MicahParks/peakdetect"
+	"github.com/MicahParks/peakdetect/peakdetectmetrics"
+)
+
+const logFmt = "%s\nError: %s"
+
+func newDetector(t *testing.T, collector *peakdetectmetrics.Collector, series string) peakdetect.PeakDetector {
+	detector := peakdetect.NewPeakDetector()
+	detector.SetLabels(map[string]string{"series": series, "bad-name": "a\"b"})
+	detector.SetMetrics(collector)
+	err := detector.Initialize(0, 3, []float64{1, 1, 1.1, 1, 0.9})
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+	return detector
+}
+
+func TestCollector(t *testing.T) {
+	collector := peakdetectmetrics.NewCollector([]float64{3, 1})
+	cpu := newDetector(t, collector, "cpu")
+	memory := newDetector(t, collector, "memory")
+	cpu.NextBatch([]float64{1, 5, -5, 1})
+	memory.Next(1)
+
+	snapshots := collector.Snapshot()
+	if len(snapshots) != 2 || snapshots[0].Labels["series"] != "cpu" {
+		t.Fatalf("Incorrect series.\n  Actual: %+v", snapshots)
+	}
+	cpuSnapshot := snapshots[0]
+	if cpuSnapshot.Positive != 1 || cpuSnapshot.Negative != 1 || cpuSnapshot.Neutral != 2 || cpuSnapshot.ZScoreCount != 4 {
+		t.Fatalf("Incorrect counts.\n  Actual: %+v", cpuSnapshot)
+	}
+	if cpuSnapshot.ZScoreBuckets["1"] != 2 || cpuSnapshot.ZScoreBuckets["3"] != 2 {
+		t.Fatalf("Incorrect histogram.\n  Actual: %+v", cpuSnapshot.ZScoreBuckets)
+	}
+
+	recorder := httptest.NewRecorder()
+	collector.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	body := recorder.Body.String()
+	for _, line := range []string{
+		"# TYPE peakdetect_signals_total counter",
+		`peakdetect_signals_total{bad_name="a\"b",series="cpu",signal="positive"} 1`,
+		`peakdetect_zscore_magnitude_bucket{bad_name="a\"b",series="cpu",le="+Inf"} 4`,
+		`peakdetect_zscore_magnitude_count{bad_name="a\"b",series="memory"} 1`,
+		"# TYPE peakdetect_stddev gauge",
+	} {
+		if !strings.Contains(body, line+"\n") {
+			t.Fatalf("The Prometheus output does not have a line.\n  Expected: %s\n  Actual: %s", line, body)
+		}
+	}
+
+	var decoded []map[string]any
+	err := json.Unmarshal([]byte(collector.Var().String()), &decoded)
+	if err != nil {
+		t.Fatalf(logFmt, "The expvar output is not valid JSON.", err)
+	}
+	if len(decoded) != 2 || decoded[0]["positive"] != float64(1) {
+		t.Fatalf("Incorrect expvar output.\n  Actual: %v", decoded)
+	}
+}
+
+func TestCollector_Edges(t *testing.T) {
+	collector := peakdetectmetrics.NewCollector(nil)
+	detector := peakdetect.NewPeakDetector()
+	detector.SetLabels(map[string]string{"le": "x", "signal": "y"})
+	detector.SetMetrics(collector)
+	err := detector.Initialize(0, 3, []float64{1, 1, 1})
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+
+	// The lag window is constant, so the z-score of the spike is infinite.
+	detector.NextBatch([]float64{5, 1})
+	snapshot := collector.Snapshot()[0]
+	if snapshot.ZScoreCount != 1 || snapshot.ZScoreSum != 0 {
+		t.Fatalf("An infinite z-score should not be observed.\n  Actual: %+v", snapshot)
+	}
+
+	var b strings.Builder
+	err = collector.WritePrometheus(&b)
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to write metrics.", err)
+	}
+	for _, line := range []string{
+		`peakdetect_signals_total{label_le="x",label_signal="y",signal="positive"} 1`,
+		`peakdetect_zscore_magnitude_bucket{label_le="x",label_signal="y",le="+Inf"} 1`,
+		`peakdetect_zscore_magnitude_sum{label_le="x",label_signal="y"} 0`,
+	} {
+		if !strings.Contains(b.String(), line+"\n") {
+			t.Fatalf("The Prometheus output does not have a line.\n  Expected: %s\n  Actual: %s", line, b.String())
+		}
+	}
+}
+
+func TestCollector_Register(t *testing.T) {
+	collector := peakdetectmetrics.NewCollector(nil)
+	register := func(labels map[string]string) (peakdetect.PeakDetector, error) {
+		detector := peakdetect.NewPeakDetector()
+		detector.SetLabels(labels)
+		err := detector.Initialize(0, 3, []float64{1, 1, 1.1, 1, 0.9})
+		if err != nil {
+			t.Fatalf(logFmt, "Error during initilization.", err)
+		}
+		return detector, collector.Register(detector)
+	}
+
+	detector, err := register(map[string]string{"a-b": "x"})
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to register a detector.", err)
+	}
+	detector.Next(1)
+	if snapshots := collector.Snapshot(); len(snapshots) != 1 || snapshots[0].Neutral != 1 {
+		t.Fatalf("A registered detector should be observed.\n  Actual: %+v", snapshots)
+	}
+	_, err = register(map[string]string{"a-b": "x"})
+	if err != nil {
+		t.Fatalf(logFmt, "Detectors with the same labels should share a series.", err)
+	}
+
+	for _, labels := range []map[string]string{
+		{"a_b": "x"},
+		{"a.b": "x", "a_b": "y"},
+		{"signal": "x", "label_signal": "y"},
+	} {
+		detector, err = register(labels)
+		if !errors.Is(err, peakdetectmetrics.ErrLabelCollision) {
+			t.Fatalf("Colliding label names %v did not produce error.\n  Expected: %s\n  Actual: %v", labels, peakdetectmetrics.ErrLabelCollision, err)
+		}
+
+		// A detector given the Collector directly is not observed, rather than writing an invalid or duplicate series.
+		detector.SetMetrics(collector)
+		detector.Next(1)
+		if snapshots := collector.Snapshot(); len(snapshots) != 1 || snapshots[0].Neutral != 1 {
+			t.Fatalf("A detector with colliding labels %v should not be observed.\n  Actual: %+v", labels, snapshots)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+// Package peakdetectplot renders a series with its signals, moving mean, and threshold bands as an SVG image or a
+// self-contained HTML page. Seeing the bands is the quickest way to tune the lag, threshold, and influence.
+package peakdetectplot
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"math"
+
+	"github.com/MicahParks/peakdetect"
+)
+
+const (
+	defaultHeight = 400
+	defaultWidth  = 1000
+	margin        = 50
+)
+
+// Options are the options for rendering a plot. The zero value is ready to use.
+type Options struct {
+	// Height is the height of the plot in pixels. It defaults to 400.
+	Height int
+	// Title is the title of the plot. It is optional.
+	Title string
+	// Width is the width of the plot in pixels. It defaults to 1000.
+	Width int
+}
+
+// SVG runs the algorithm over the data with the cfg like peakdetect.Detect and writes an SVG image of the values, the
+// moving mean, the band of the mean plus or minus the threshold times the standard deviation, and the signals. Values
+// outside the band are signals, unless an option of the cfg suppressed them. The first cfg.Lag values are used for
+// initialization, so they have no band.
+//
+// The cfg.Lag must be set. The cfg.DerivativeOrder must be zero, because the band would not be in the units of the
+// values. The cfg.NegativeThreshold, cfg.ReleaseThreshold, and cfg.Percentile must also be zero, because they make the
+// band asymmetric or change it while a signal is sustained, which the plotted band does not show.
+func SVG(w io.Writer, data []float64, cfg peakdetect.Config, opts Options) error {
+	results, err := detect(data, cfg)
+	if err != nil {
+		return err
+	}
+
+	buf := bufio.NewWriter(w)
+	render(buf, data, results, cfg, opts)
+	return buf.Flush()
+}
+
+// HTML is like SVG, but writes a self-contained HTML page with the image and a legend.
+func HTML(w io.Writer, data []float64, cfg peakdetect.Config, opts Options) error {
+	results, err := detect(data, cfg)
+	if err != nil {
+		return err
+	}
+
+	title := opts.Title
+	if title == "" {
+		title = "peakdetect"
+	}
+	buf := bufio.NewWriter(w)
+	_, _ = fmt.Fprintf(buf, `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>%s</title>
+<style>body{font-family:sans-serif;margin:2em}.legend span{margin-right:1.5em}</style>
+</head>
+<body>
+<h1>%s</h1>
+<p>lag %d, threshold %g, influence %g</p>
+`, html.EscapeString(title), html.EscapeString(title), cfg.Lag, cfg.Threshold, cfg.Influence)
+	render(buf, data, results, cfg, opts)
+	_, _ = fmt.Fprint(buf, `
+<p class="legend"><span style="color:#1f77b4">&#9644; value</span><span style="color:#7f7f7f">&#9644; moving mean</span><span style="color:#bbbbbb">&#9632; threshold band</span><span style="color:#d62728">&#9679; positive signal</span><span style="color:#2ca02c">&#9679; negative signal</span></p>
+</body>
+</html>
+`)
+	return buf.Flush()
+}
+
+func detect(data []float64, cfg peakdetect.Config) ([]peakdetect.Result, error) {
+	if cfg.Lag == 0 {
+		return nil, fmt.Errorf("the lag must be set to plot: %w", peakdetect.ErrInvalidConfig)
+	}
+	if cfg.DerivativeOrder != 0 {
+		return nil, fmt.Errorf("a derivative order can not be plotted: %w", peakdetect.ErrInvalidConfig)
+	}
+	if cfg.NegativeThreshold != 0 || cfg.ReleaseThreshold != 0 || cfg.Percentile != 0 {
+		return nil, fmt.Errorf("a negative threshold, release threshold, or percentile can not be plotted: %w", peakdetect.ErrInvalidConfig)
+	}
+	if uint(len(data)) < cfg.Lag {
+		return nil, fmt.Errorf("%d values are too few for a lag of %d: %w", len(data), cfg.Lag, peakdetect.ErrInvalidInitialValues)
+	}
+
+	detector := peakdetect.NewPeakDetector()
+	err := detector.InitializeWithConfig(cfg, data[:cfg.Lag])
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize the detector: %w", err)
+	}
+	return detector.NextBatchDetailed(data[cfg.Lag:]), nil
+}
+
+// scale maps indices and values to pixels.
+type scale struct {
+	height float64
+	maxX   float64
+	maxY   float64
+	minY   float64
+	width  float64
+}
+
+func (s scale) x(index int) float64 {
+	return margin + float64(index)/math.Max(s.maxX, 1)*s.width
+}
+
+func (s scale) y(value float64) float64 {
+	return margin + (s.maxY-value)/(s.maxY-s.minY)*s.height
+}
+
+func render(w io.Writer, data []float64, results []peakdetect.Result, cfg peakdetect.Config, opts Options) {
+	width, height := opts.Width, opts.Height
+	if width <= 0 {
+		width = defaultWidth
+	}
+	if height <= 0 {
+		height = defaultHeight
+	}
+	lag := int(cfg.Lag)
+
+	s := scale{
+		height: float64(height - 2*margin),
+		maxX:   float64(len(data) - 1),
+		maxY:   math.Inf(-1),
+		minY:   math.Inf(1),
+		width:  float64(width - 2*margin),
+	}
+	include := func(v float64) {
+		if !math.IsNaN(v) && !math.IsInf(v, 0) {
+			s.minY = math.Min(s.minY, v)
+			s.maxY = math.Max(s.maxY, v)
+		}
+	}
+	for _, v := range data {
+		include(v)
+	}
+	for _, r := range results {
+		include(r.Mean + cfg.Threshold*r.StdDev)
+		include(r.Mean - cfg.Threshold*r.StdDev)
+	}
+	if s.minY > s.maxY {
+		s.minY, s.maxY = 0, 1
+	}
+	if s.minY == s.maxY {
+		s.minY--
+		s.maxY++
+	}
+
+	_, _ = fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="12">`+"\n", width, height, width, height)
+	if opts.Title != "" {
+		_, _ = fmt.Fprintf(w, `<title>%s</title>`+"\n", html.EscapeString(opts.Title))
+	}
+	_, _ = fmt.Fprintf(w, `<rect x="%d" y="%d" width="%g" height="%g" fill="none" stroke="#000000"/>`+"\n", margin, margin, s.width, s.height)
+	_, _ = fmt.Fprintf(w, `<text x="%d" y="%g" text-anchor="end">%.6g</text>`+"\n", margin-4, s.y(s.maxY)+4, s.maxY)
+	_, _ = fmt.Fprintf(w, `<text x="%d" y="%g" text-anchor="end">%.6g</text>`+"\n", margin-4, s.y(s.minY)+4, s.minY)
+	_, _ = fmt.Fprintf(w, `<text x="%d" y="%g">0</text>`+"\n", margin, s.y(s.minY)+16)
+	_, _ = fmt.Fprintf(w, `<text x="%g" y="%g" text-anchor="end">%d</text>`+"\n", s.x(len(data)-1), s.y(s.minY)+16, len(data)-1)
+
+	if len(results) != 0 {
+		_, _ = fmt.Fprint(w, `<polygon fill="#dddddd" stroke="#bbbbbb" points="`)
+		for i, r := range results {
+			writePoint(w, s, lag+i, r.Mean+cfg.Threshold*r.StdDev)
+		}
+		for i := len(results) - 1; i >= 0; i-- {
+			r := results[i]
+			writePoint(w, s, lag+i, r.Mean-cfg.Threshold*r.StdDev)
+		}
+		_, _ = fmt.Fprint(w, `"/>`+"\n")
+
+		_, _ = fmt.Fprint(w, `<polyline fill="none" stroke="#7f7f7f" stroke-dasharray="4 2" points="`)
+		for i, r := range results {
+			writePoint(w, s, lag+i, r.Mean)
+		}
+		_, _ = fmt.Fprint(w, `"/>`+"\n")
+	}
+
+	_, _ = fmt.Fprint(w, `<polyline fill="none" stroke="#1f77b4" points="`)
+	for i, v := range data {
+		writePoint(w, s, i, v)
+	}
+	_, _ = fmt.Fprint(w, `"/>`+"\n")
+
+	for i, r := range results {
+		var color string
+		switch r.Signal {
+		case peakdetect.SignalPositive:
+			color = "#d62728"
+		case peakdetect.SignalNegative:
+			color = "#2ca02c"
+		default:
+			continue
+		}
+		_, _ = fmt.Fprintf(w, `<circle cx="%.2f" cy="%.2f" r="3" fill="%s"/>`+"\n", s.x(lag+i), s.y(r.Value), color)
+	}
+
+	_, _ = fmt.Fprint(w, "</svg>\n")
+}
+
+// writePoint writes a point of a polyline or polygon. Points that are not finite are skipped.
+func writePoint(w io.Writer, s scale, index int, value float64) {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return
+	}
+	_, _ = fmt.Fprintf(w, "%.2f,%.2f ", s.x(index), s.y(value))
+}
@@ -0,0 +1,94 @@
+package peakdetectplot_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/MicahParks/peakdetect"
+	"github.com/MicahParks/peakdetect/peakdetectplot"
+)
+
+const logFmt = "%s\nError: %s"
+
+var (
+	cfg  = peakdetect.Config{Lag: 5, Threshold: 3}
+	data = []float64{1, 1, 1.1, 1, 0.9, 1, 5, 6, 1, 1, -4, 1}
+)
+
+func TestSVG(t *testing.T) {
+	var buf bytes.Buffer
+	err := peakdetectplot.SVG(&buf, data, cfg, peakdetectplot.Options{Title: "a <b> & c"})
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to render.", err)
+	}
+
+	var circles, polylines int
+	decoder := xml.NewDecoder(&buf)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			t.Fatalf(logFmt, "The SVG is not well-formed.", err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch start.Name.Local {
+		case "circle":
+			circles++
+		case "polyline":
+			polylines++
+		}
+	}
+
+	signals, _, err := peakdetect.Detect(data, cfg)
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to detect.", err)
+	}
+	var expected int
+	for _, signal := range signals {
+		if signal != peakdetect.SignalNeutral {
+			expected++
+		}
+	}
+	if expected == 0 || circles != expected {
+		t.Fatalf("Incorrect number of signals plotted.\n  Expected: %d\n  Actual: %d", expected, circles)
+	}
+	if polylines != 2 {
+		t.Fatalf("Incorrect number of lines plotted.\n  Expected: %d\n  Actual: %d", 2, polylines)
+	}
+}
+
+func TestHTML(t *testing.T) {
+	var buf bytes.Buffer
+	err := peakdetectplot.HTML(&buf, data, cfg, peakdetectplot.Options{Title: "<script>"})
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to render.", err)
+	}
+	page := buf.String()
+	if !strings.HasPrefix(page, "<!DOCTYPE html>") || !strings.Contains(page, "<svg") || strings.Contains(page, "<script>") {
+		t.Fatalf("The HTML page is incorrect.\n  Actual: %s", page)
+	}
+}
+
+func TestSVG_InvalidConfig(t *testing.T) {
+	for _, c := range []peakdetect.Config{
+		{Threshold: 3},
+		{Lag: 5, Threshold: 3, DerivativeOrder: 1},
+		{Lag: 5, Threshold: 3, NegativeThreshold: 2},
+		{Lag: 5, Threshold: 3, ReleaseThreshold: 1},
+		{Lag: 5, Threshold: 3, Percentile: 0.99},
+	} {
+		err := peakdetectplot.SVG(&bytes.Buffer{}, data, c, peakdetectplot.Options{})
+		if !errors.Is(err, peakdetect.ErrInvalidConfig) {
+			t.Fatalf("Invalid config did not produce the correct error for %+v.\n  Expected: %s\n  Actual: %v", c, peakdetect.ErrInvalidConfig, err)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package peakdetect
+
+// PeakEvent is a group of consecutive values with the same non-neutral signal. A single wide peak produces a signal for
+// every value above the threshold, but only one PeakEvent.
+type PeakEvent struct {
+	// StartIndex is the index of the first value of the peak.
+	StartIndex uint64
+	// EndIndex is the index of the last value of the peak, inclusive.
+	EndIndex uint64
+	// ApexIndex is the index of the most extreme value of the peak. This is the greatest value for a positive peak and
+	// the least value for a negative peak. Ties are resolved in favor of the earliest value.
+	ApexIndex uint64
+	// ApexValue is the most extreme value of the peak.
+	ApexValue float64
+	// Signal is the signal of every value in the peak.
+	Signal Signal
+}
+
+// PeakGrouper coalesces a stream of signals into PeakEvents. Indices count every signal given to the PeakGrouper,
+// starting at zero. The zero value is ready to use.
+type PeakGrouper struct {
+	current PeakEvent
+	index   uint64
+	open    bool
+}
+
+// Next adds the signal for the next value. If it ends a peak, the event for the peak is returned and ok is true. A peak
+// ends with a neutral signal or a signal in the opposite direction, which starts a new peak.
+func (g *PeakGrouper) Next(signal Signal, value float64) (event PeakEvent, ok bool) {
+	index := g.index
+	g.index++
+
+	if g.open && signal == g.current.Signal {
+		g.current.EndIndex = index
+		if signal == SignalPositive && value > g.current.ApexValue || signal == SignalNegative && value < g.current.ApexValue {
+			g.current.ApexIndex = index
+			g.current.ApexValue = value
+		}
+		return PeakEvent{}, false
+	}
+
+	event, ok = g.current, g.open
+	g.open = signal != SignalNeutral
+	if g.open {
+		g.current = PeakEvent{
+			StartIndex: index,
+			EndIndex:   index,
+			ApexIndex:  index,
+			ApexValue:  value,
+			Signal:     signal,
+		}
+	}
+	return event, ok
+}
+
+// Flush ends the current peak, if any, and returns its event. It is used at the end of the data, where a peak may not
+// have ended yet.
+func (g *PeakGrouper) Flush() (event PeakEvent, ok bool) {
+	event, ok = g.current, g.open
+	g.open = false
+	return event, ok
+}
+
+// GroupPeaks coalesces the signals for the given values into PeakEvents, including any peak that is still open at the
+// end. The signals and values must be the same length.
+func GroupPeaks(signals []Signal, values []float64) []PeakEvent {
+	var grouper PeakGrouper
+	var events []PeakEvent
+	for i, signal := range signals {
+		if event, ok := grouper.Next(signal, values[i]); ok {
+			events = append(events, event)
+		}
+	}
+	if event, ok := grouper.Flush(); ok {
+		events = append(events, event)
+	}
+	return events
+}
@@ -0,0 +1,59 @@
+package peakdetect_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/MicahParks/peakdetect"
+)
+
+func TestGroupPeaks(t *testing.T) {
+	detector := peakdetect.NewPeakDetector()
+	err := detector.Initialize(exampleInfluence, exampleThreshold, exampleInputs[0:exampleLag])
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+
+	values := exampleInputs[exampleLag:]
+	events := peakdetect.GroupPeaks(detector.NextBatch(values), values)
+
+	// Indices are relative to the first value after the initial values.
+	expected := []peakdetect.PeakEvent{
+		{StartIndex: 15, EndIndex: 15, ApexIndex: 15, ApexValue: 1.5, Signal: peakdetect.SignalPositive},
+		{StartIndex: 17, EndIndex: 21, ApexIndex: 19, ApexValue: 5, Signal: peakdetect.SignalPositive},
+		{StartIndex: 28, EndIndex: 33, ApexIndex: 30, ApexValue: 4, Signal: peakdetect.SignalPositive},
+		{StartIndex: 37, EndIndex: 40, ApexIndex: 37, ApexValue: 4, Signal: peakdetect.SignalPositive},
+	}
+	if !reflect.DeepEqual(events, expected) {
+		t.Fatalf("Incorrect peak events.\n  Expected: %+v\n  Actual: %+v", expected, events)
+	}
+}
+
+func TestPeakGrouper_Next(t *testing.T) {
+	signals := []peakdetect.Signal{1, 1, -1, -1, 0, -1}
+	values := []float64{5, 7, -3, -4, 0, -2}
+
+	var grouper peakdetect.PeakGrouper
+	var events []peakdetect.PeakEvent
+	for i, signal := range signals {
+		if event, ok := grouper.Next(signal, values[i]); ok {
+			events = append(events, event)
+		}
+	}
+
+	expected := []peakdetect.PeakEvent{
+		{StartIndex: 0, EndIndex: 1, ApexIndex: 1, ApexValue: 7, Signal: peakdetect.SignalPositive},
+		{StartIndex: 2, EndIndex: 3, ApexIndex: 3, ApexValue: -4, Signal: peakdetect.SignalNegative},
+	}
+	if !reflect.DeepEqual(events, expected) {
+		t.Fatalf("Incorrect peak events.\n  Expected: %+v\n  Actual: %+v", expected, events)
+	}
+
+	event, ok := grouper.Flush()
+	if !ok || event.StartIndex != 5 || event.Signal != peakdetect.SignalNegative {
+		t.Fatalf("Flush did not return the open peak.\n  Actual: %+v", event)
+	}
+	if _, ok = grouper.Flush(); ok {
+		t.Fatalf("Flush should not return a peak twice.")
+	}
+}
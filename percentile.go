@@ -0,0 +1,24 @@
+package peakdetect
+
+import (
+	"math"
+)
+
+// setQuantiles rebuilds the order statistics of the lag window for Config.Percentile, or drops them if it is disabled.
+func (p *peakDetector) setQuantiles() {
+	p.quantiles = nil
+	if p.config.Percentile != 0 {
+		p.quantiles = &sortedWindow{}
+		p.quantiles.initialize(p.Window())
+	}
+}
+
+// percentileBound returns the deviation from the median of the lag window that a value on the side of it must exceed
+// for Config.Percentile, which is the distance from the median to the percentile for that side. Exceeding it is the
+// same as being beyond the percentile, regardless of where the moving mean is in a skewed window.
+func (p *peakDetector) percentileBound(side Signal) float64 {
+	if side == SignalPositive {
+		return math.Max(0, p.quantiles.quantile(p.config.Percentile/100)-p.last.center)
+	}
+	return math.Max(0, p.last.center-p.quantiles.quantile(1-p.config.Percentile/100))
+}
@@ -0,0 +1,151 @@
+package peakdetect_test
+
+import (
+	"encoding/json"
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/MicahParks/peakdetect"
+)
+
+func TestPeakDetector_Percentile(t *testing.T) {
+	for _, percentile := range []float64{99, 95, 75, 100} {
+		r := rand.New(rand.NewSource(int64(percentile)))
+		data := make([]float64, 500)
+		for i := range data {
+			// An exponential distribution is far from normal, which is the use case for percentiles.
+			data[i] = r.ExpFloat64()
+		}
+		const lag = 50
+
+		detector, err := peakdetect.NewFromOptions(data[:lag], peakdetect.WithInfluence(1), peakdetect.WithPercentile(percentile))
+		if err != nil {
+			t.Fatalf(logFmt, "Error during initilization.", err)
+		}
+
+		for i, v := range data[lag:] {
+			window := detector.Window()
+			upper := referenceQuantile(window, percentile/100)
+			lower := referenceQuantile(window, 1-percentile/100)
+			expected := peakdetect.SignalNeutral
+			switch {
+			case v > upper:
+				expected = peakdetect.SignalPositive
+			case v < lower:
+				expected = peakdetect.SignalNegative
+			}
+
+			signal := detector.Next(v)
+			if signal != expected {
+				t.Fatalf("Incorrect signal at index %d for the %f percentile of %f and %f.\n  Expected: %d\n  Actual: %d", lag+i, percentile, lower, upper, expected, signal)
+			}
+			explanation := detector.Explain()
+			exceeds := explanation.Conditions[0]
+			if exceeds.Name != peakdetect.ConditionExceedsThreshold || exceeds.Passed != (expected != peakdetect.SignalNeutral) {
+				t.Fatalf("Incorrect explanation at index %d.\n  Expected: %t\n  Actual: %t", lag+i, expected != peakdetect.SignalNeutral, exceeds.Passed)
+			}
+		}
+	}
+}
+
+func TestPeakDetector_PercentileSkewed(t *testing.T) {
+	initial := []float64{1, 1, 1, 1, 1, 1, 1, 1, 1, 100}
+	detector, err := peakdetect.NewFromOptions(initial, peakdetect.WithPercentile(80))
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+
+	// The value is above the percentile, but below the moving mean of 10.9.
+	result := detector.NextDetailed(5)
+	if result.Signal != peakdetect.SignalPositive {
+		t.Fatalf("A value above the percentile should signal.\n  Expected: %d\n  Actual: %d", peakdetect.SignalPositive, result.Signal)
+	}
+	if result.Strength <= 1 {
+		t.Fatalf("A value above the percentile should have a strength greater than one.\n  Actual: %f", result.Strength)
+	}
+}
+
+func TestPeakDetector_PercentileSpike(t *testing.T) {
+	detector, err := peakdetect.NewFromOptions(exampleInputs[:exampleLag], peakdetect.WithPercentile(99))
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+	if signal := detector.Next(1); signal != peakdetect.SignalNeutral {
+		t.Fatalf("A typical value should not signal.\n  Actual: %d", signal)
+	}
+	if signal := detector.Next(5); signal != peakdetect.SignalPositive {
+		t.Fatalf("A spike should signal.\n  Actual: %d", signal)
+	}
+	if strength := detector.Explain().Strength; !(strength > 1) {
+		t.Fatalf("The strength of a signal should be greater than one.\n  Actual: %f", strength)
+	}
+	if signal := detector.Next(0.5); signal != peakdetect.SignalNegative {
+		t.Fatalf("A dip should signal.\n  Actual: %d", signal)
+	}
+}
+
+func TestPeakDetector_PercentileState(t *testing.T) {
+	const split = exampleLag + 20
+	cfg := peakdetect.Config{
+		Influence:  0.5,
+		Percentile: 95,
+	}
+	detector := peakdetect.NewPeakDetector()
+	err := detector.InitializeWithConfig(cfg, exampleInputs[:exampleLag])
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+	detector.NextBatch(exampleInputs[exampleLag:split])
+
+	data, err := json.Marshal(detector)
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to marshal the detector.", err)
+	}
+	restored := peakdetect.NewPeakDetector()
+	err = json.Unmarshal(data, restored)
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to unmarshal the detector.", err)
+	}
+	clone := detector.Clone()
+
+	expected := detector.NextBatch(exampleInputs[split:])
+	for name, d := range map[string]peakdetect.PeakDetector{"Clone": clone, "Restored": restored} {
+		actual := d.NextBatch(exampleInputs[split:])
+		if !reflect.DeepEqual(actual, expected) {
+			t.Fatalf("%s signals do not match.\n  Expected: %v\n  Actual: %v", name, expected, actual)
+		}
+	}
+
+	// Enabling the percentile on a running detector uses its current window.
+	reconfigured, err := peakdetect.NewFromOptions(exampleInputs[:exampleLag], peakdetect.WithThreshold(exampleThreshold))
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+	reconfigured.NextBatch(exampleInputs[exampleLag:split])
+	err = reconfigured.Reconfigure(cfg)
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to reconfigure.", err)
+	}
+	fresh, err := peakdetect.NewFromConfig(cfg, reconfigured.Window())
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+	expected = fresh.NextBatch(exampleInputs[split:])
+	actual := reconfigured.NextBatch(exampleInputs[split:])
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("Reconfigured signals do not match.\n  Expected: %v\n  Actual: %v", expected, actual)
+	}
+}
+
+// referenceQuantile computes the q-quantile of the values by sorting, linearly interpolating between the closest ranks.
+func referenceQuantile(values []float64, q float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	position := q * float64(len(sorted)-1)
+	lower := math.Floor(position)
+	upper := math.Ceil(position)
+	return sorted[int(lower)] + (position-lower)*(sorted[int(upper)]-sorted[int(lower)])
+}
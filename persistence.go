@@ -0,0 +1,82 @@
+package peakdetect
+
+// persistenceEntry is a recent value tracked for Config.Persistence.
+type persistenceEntry struct {
+	// Input is the value given to the PeakDetector, which is reported if the signal is backfilled.
+	Input float64 `json:"input"`
+	// Side is the side of the moving mean the value exceeded the threshold on, or SignalNeutral if it did not.
+	Side Signal `json:"side"`
+	// Signaled is true if a signal was reported for the value.
+	Signaled bool `json:"signaled,omitempty"`
+}
+
+// persistenceWindow returns the number of recent values that exceedances are counted over for Config.Persistence.
+func (c Config) persistenceWindow() uint {
+	if c.PersistenceWindow == 0 {
+		return c.Persistence
+	}
+	return c.PersistenceWindow
+}
+
+// persist records if the most recent value exceeded the threshold on the side and reports if the exceedance has
+// persisted for Config.Persistence of the recent values.
+func (p *peakDetector) persist(side Signal) bool {
+	if p.config.Persistence <= 1 {
+		return true
+	}
+	if window := p.config.persistenceWindow(); uint(len(p.persistence)) == window {
+		copy(p.persistence, p.persistence[1:])
+		p.persistence = p.persistence[:window-1]
+	}
+	p.persistence = append(p.persistence, persistenceEntry{
+		Input: p.prevInput,
+		Side:  side,
+	})
+	if side == SignalNeutral {
+		return false
+	}
+
+	var exceeded uint
+	for _, entry := range p.persistence {
+		if entry.Side == side {
+			exceeded++
+		}
+	}
+	return exceeded >= p.config.Persistence
+}
+
+// confirm records the signal for the most recent value. With Config.PersistenceBackfill, the earlier exceedances on
+// the same side that were waiting to persist are reported with the signal too.
+func (p *peakDetector) confirm(signal Signal) {
+	if p.config.Persistence <= 1 || signal == SignalNeutral {
+		return
+	}
+	newest := len(p.persistence) - 1
+	p.persistence[newest].Signaled = true
+	if !p.config.PersistenceBackfill {
+		return
+	}
+	for i := range p.persistence[:newest] {
+		entry := &p.persistence[i]
+		if entry.Side != signal || entry.Signaled {
+			continue
+		}
+		entry.Signaled = true
+		offset := uint(newest - i)
+		p.last.backfilled = append(p.last.backfilled, offset)
+		if len(p.handlers) != 0 {
+			p.emit(p.count-1-uint64(offset), signal, entry.Input)
+		}
+	}
+}
+
+// backfill applies the signal of the most recent value to the earlier values it was backfilled to. The signals end with
+// the signal of the most recent value.
+func (p *peakDetector) backfill(signals []Signal) {
+	newest := len(signals) - 1
+	for _, offset := range p.last.backfilled {
+		if offset <= uint(newest) {
+			signals[newest-int(offset)] = signals[newest]
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package peakdetect
+
+import (
+	"fmt"
+)
+
+// Preprocessor transforms each value before detection, such as to remove a slowly ramping baseline that would otherwise
+// swamp the moving mean and standard deviation. See PeakDetector.SetPreprocessor. LinearDetrend, MedianBaseline,
+// HighPass, and MedianFilter are Preprocessors.
+type Preprocessor interface {
+	// Next transforms the next value.
+	Next(value float64) float64
+	// Reset returns the Preprocessor to the state it was created with.
+	Reset()
+	// Clone returns an independent deep copy of the Preprocessor.
+	Clone() Preprocessor
+}
+
+// LinearDetrend is a Preprocessor that removes a linear trend. Each value is replaced by its residual from the least
+// squares line through the preceding window of values, extrapolated to the value. The value itself is not part of the
+// fit, so a peak does not bend the line. Each update is O(n) for a window of n values.
+type LinearDetrend struct {
+	count  uint
+	index  uint
+	values []float64
+}
+
+// NewLinearDetrend creates a new LinearDetrend over the given window size, which must be at least two. Until two values
+// have been processed, there is no line and the residual is zero.
+func NewLinearDetrend(window uint) (*LinearDetrend, error) {
+	if window < 2 {
+		return nil, fmt.Errorf("the window size for a linear detrend must be at least two: %w", ErrInvalidWindow)
+	}
+	return &LinearDetrend{
+		values: make([]float64, window),
+	}, nil
+}
+
+// Next implements Preprocessor.
+func (l *LinearDetrend) Next(value float64) float64 {
+	window := uint(len(l.values))
+	residual := 0.0
+	if l.count >= 2 {
+		// The oldest value is at x = 0 and the value is at x = n.
+		n := l.count
+		start := (l.index + window - n) % window
+		var sumX, sumY, sumXY, sumXX float64
+		for i := uint(0); i < n; i++ {
+			x, y := float64(i), l.values[(start+i)%window]
+			sumX += x
+			sumY += y
+			sumXY += x * y
+			sumXX += x * x
+		}
+		fn := float64(n)
+		slope := (fn*sumXY - sumX*sumY) / (fn*sumXX - sumX*sumX)
+		intercept := (sumY - slope*sumX) / fn
+		residual = value - (intercept + slope*fn)
+	}
+
+	l.values[l.index] = value
+	l.index++
+	if l.index == window {
+		l.index = 0
+	}
+	if l.count < window {
+		l.count++
+	}
+	return residual
+}
+
+// Reset implements Preprocessor.
+func (l *LinearDetrend) Reset() {
+	l.count = 0
+	l.index = 0
+}
+
+// Clone implements Preprocessor.
+func (l *LinearDetrend) Clone() Preprocessor {
+	clone := *l
+	clone.values = append([]float64(nil), l.values...)
+	return &clone
+}
+
+// MedianBaseline is a Preprocessor that subtracts a moving median baseline. Each value is replaced by its difference
+// from the median of the preceding window of values. Unlike a moving mean, the median follows a ramping or stepping
+// baseline without being pulled by peaks.
+type MedianBaseline struct {
+	index   uint
+	scratch []float64
+	values  []float64
+}
+
+// NewMedianBaseline creates a new MedianBaseline over the given window size, which must be greater than zero. The first
+// value has no baseline, so its difference is zero.
+func NewMedianBaseline(window uint) (*MedianBaseline, error) {
+	if window == 0 {
+		return nil, fmt.Errorf("the window size for a median baseline must be greater than zero: %w", ErrInvalidWindow)
+	}
+	return &MedianBaseline{
+		scratch: make([]float64, 0, window),
+		values:  make([]float64, 0, window),
+	}, nil
+}
+
+// Next implements Preprocessor.
+func (m *MedianBaseline) Next(value float64) float64 {
+	difference := 0.0
+	if len(m.values) > 0 {
+		m.scratch = append(m.scratch[:0], m.values...)
+		difference = value - medianSelect(m.scratch)
+	}
+
+	if len(m.values) < cap(m.values) {
+		m.values = append(m.values, value)
+	} else {
+		m.values[m.index] = value
+		m.index++
+		if m.index == uint(len(m.values)) {
+			m.index = 0
+		}
+	}
+	return difference
+}
+
+// Reset implements Preprocessor.
+func (m *MedianBaseline) Reset() {
+	m.index = 0
+	m.values = m.values[:0]
+}
+
+// Clone implements Preprocessor.
+func (m *MedianBaseline) Clone() Preprocessor {
+	return &MedianBaseline{
+		index:   m.index,
+		scratch: make([]float64, 0, cap(m.scratch)),
+		values:  append(make([]float64, 0, cap(m.values)), m.values...),
+	}
+}
+
+// HighPass is a Preprocessor that is a first order infinite impulse response high-pass filter. It removes slow changes
+// in the baseline while keeping fast changes, such as peaks.
+//
+// https://en.wikipedia.org/wiki/High-pass_filter#Discrete-time_realization
+type HighPass struct {
+	alpha     float64
+	prevInput float64
+	prevValue float64
+	started   bool
+}
+
+// NewHighPass creates a new HighPass with the smoothing factor alpha, which must be in the range (0, 1]. An alpha closer
+// to zero removes faster changes. For a time constant of RC and a sampling interval of dt, alpha is RC / (RC + dt).
+func NewHighPass(alpha float64) (*HighPass, error) {
+	if !(alpha > 0 && alpha <= 1) {
+		return nil, fmt.Errorf("the alpha %f for a high-pass filter must be in the range (0, 1]: %w", alpha, ErrInvalidConfig)
+	}
+	return &HighPass{
+		alpha: alpha,
+	}, nil
+}
+
+// Next implements Preprocessor. The first value has no previous value, so its output is zero.
+func (h *HighPass) Next(value float64) float64 {
+	if !h.started {
+		h.started = true
+		h.prevInput = value
+		return 0
+	}
+	h.prevValue = h.alpha * (h.prevValue + value - h.prevInput)
+	h.prevInput = value
+	return h.prevValue
+}
+
+// Reset implements Preprocessor.
+func (h *HighPass) Reset() {
+	*h = HighPass{
+		alpha: h.alpha,
+	}
+}
+
+// Clone implements Preprocessor.
+func (h *HighPass) Clone() Preprocessor {
+	clone := *h
+	return &clone
+}
+
+// Reset implements Preprocessor.
+func (m *MedianFilter) Reset() {
+	m.index = 0
+	m.values = m.values[:0]
+}
+
+// Clone implements Preprocessor.
+func (m *MedianFilter) Clone() Preprocessor {
+	return &MedianFilter{
+		index:   m.index,
+		scratch: make([]float64, 0, cap(m.scratch)),
+		values:  append(make([]float64, 0, cap(m.values)), m.values...),
+	}
+}
+
+func (p *peakDetector) SetPreprocessor(preprocessor Preprocessor) {
+	p.pendingPreprocessor = preprocessor
+	p.preprocessorPending = true
+	if p.config.Lag == 0 {
+		p.applyPendingPreprocessor()
+	}
+}
+
+// applyPendingPreprocessor replaces the Preprocessor with the one most recently given to SetPreprocessor, if any. The
+// lag window holds values from the previous Preprocessor, so it is only applied when the window is replaced.
+func (p *peakDetector) applyPendingPreprocessor() {
+	if p.preprocessorPending {
+		p.preprocessor = p.pendingPreprocessor
+		p.pendingPreprocessor = nil
+		p.preprocessorPending = false
+	}
+}
@@ -0,0 +1,179 @@
+package peakdetect_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/MicahParks/peakdetect"
+)
+
+// rampWithSpike returns a noisy ramp with a slope of one and a spike of 20 above the ramp at index 80.
+func rampWithSpike() []float64 {
+	noise := []float64{0.3, -0.2, 0.1, -0.3, 0.2, -0.1, 0}
+	data := make([]float64, 100)
+	for i := range data {
+		data[i] = float64(i) + noise[i%len(noise)]
+	}
+	data[80] += 20
+	return data
+}
+
+func TestPreprocessor_Errors(t *testing.T) {
+	_, err := peakdetect.NewLinearDetrend(1)
+	if !errors.Is(err, peakdetect.ErrInvalidWindow) {
+		t.Fatalf("Invalid window did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidWindow, err)
+	}
+	_, err = peakdetect.NewMedianBaseline(0)
+	if !errors.Is(err, peakdetect.ErrInvalidWindow) {
+		t.Fatalf("Invalid window did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidWindow, err)
+	}
+	for _, alpha := range []float64{0, -0.5, 1.5, math.NaN()} {
+		_, err = peakdetect.NewHighPass(alpha)
+		if !errors.Is(err, peakdetect.ErrInvalidConfig) {
+			t.Fatalf("Invalid alpha %f did not produce error.\n  Expected: %s\n  Actual: %s", alpha, peakdetect.ErrInvalidConfig, err)
+		}
+	}
+}
+
+func TestLinearDetrend(t *testing.T) {
+	detrend, err := peakdetect.NewLinearDetrend(5)
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to create linear detrend.", err)
+	}
+
+	expected := []float64{0, 0, 0, 0, 0, 0, 0, 10}
+	for i, v := range []float64{1, 3, 5, 7, 9, 11, 13, 25} {
+		actual := detrend.Next(v)
+		if math.Abs(actual-expected[i]) > 1e-9 {
+			t.Fatalf("Residual did not match at index %d.\n  Expected: %f\n  Actual: %f", i, expected[i], actual)
+		}
+	}
+}
+
+func TestMedianBaseline(t *testing.T) {
+	baseline, err := peakdetect.NewMedianBaseline(3)
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to create median baseline.", err)
+	}
+
+	expected := []float64{0, 1, 0.5, 10, 0, 1}
+	for i, v := range []float64{1, 2, 2, 12, 2, 3} {
+		actual := baseline.Next(v)
+		if actual != expected[i] {
+			t.Fatalf("Difference did not match at index %d.\n  Expected: %f\n  Actual: %f", i, expected[i], actual)
+		}
+	}
+
+	baseline.Reset()
+	if actual := baseline.Next(100); actual != 0 {
+		t.Fatalf("Reset did not clear the window.\n  Expected: %f\n  Actual: %f", 0.0, actual)
+	}
+}
+
+func TestHighPass(t *testing.T) {
+	highPass, err := peakdetect.NewHighPass(0.5)
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to create high-pass filter.", err)
+	}
+
+	expected := []float64{0, 0, 2, 1, 0.5}
+	for i, v := range []float64{3, 3, 7, 7, 7} {
+		actual := highPass.Next(v)
+		if actual != expected[i] {
+			t.Fatalf("Filtered value did not match at index %d.\n  Expected: %f\n  Actual: %f", i, expected[i], actual)
+		}
+	}
+
+	clone := highPass.Clone()
+	if a, b := highPass.Next(7), clone.Next(7); a != b {
+		t.Fatalf("Clone did not match.\n  Expected: %f\n  Actual: %f", a, b)
+	}
+}
+
+func TestPeakDetector_SetPreprocessor(t *testing.T) {
+	const lag = 30
+	data := rampWithSpike()
+
+	detector := peakdetect.NewPeakDetector()
+	err := detector.Initialize(exampleInfluence, exampleThreshold, data[:lag])
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to initialize peak detector.", err)
+	}
+	for i, signal := range detector.NextBatch(data[lag:]) {
+		if signal != peakdetect.SignalNeutral {
+			t.Fatalf("The ramp should hide the spike without preprocessing, but index %d has a signal.", i+lag)
+		}
+	}
+
+	for name, newPreprocessor := range map[string]func() (peakdetect.Preprocessor, error){
+		"LinearDetrend": func() (peakdetect.Preprocessor, error) {
+			return peakdetect.NewLinearDetrend(10)
+		},
+		"MedianBaseline": func() (peakdetect.Preprocessor, error) {
+			return peakdetect.NewMedianBaseline(3)
+		},
+		"HighPass": func() (peakdetect.Preprocessor, error) {
+			return peakdetect.NewHighPass(0.5)
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			preprocessor, err := newPreprocessor()
+			if err != nil {
+				t.Fatalf(logFmt, "Failed to create preprocessor.", err)
+			}
+
+			detector := peakdetect.NewPeakDetector()
+			detector.SetPreprocessor(preprocessor)
+			err = detector.Initialize(exampleInfluence, exampleThreshold, data[:lag])
+			if err != nil {
+				t.Fatalf(logFmt, "Failed to initialize peak detector.", err)
+			}
+			clone := detector.Clone()
+
+			signals := detector.NextBatch(data[lag:])
+			if signals[80-lag] != peakdetect.SignalPositive {
+				t.Fatalf("The spike was not detected.\n  Expected: %d\n  Actual: %d", peakdetect.SignalPositive, signals[80-lag])
+			}
+			for i, signal := range clone.NextBatch(data[lag:]) {
+				if signal != signals[i] {
+					t.Fatalf("The clone did not match at index %d.\n  Expected: %d\n  Actual: %d", i+lag, signals[i], signal)
+				}
+			}
+		})
+	}
+}
+
+func TestPeakDetector_SetPreprocessorPending(t *testing.T) {
+	const lag = 30
+	data := rampWithSpike()
+
+	detector := peakdetect.NewPeakDetector()
+	err := detector.Initialize(exampleInfluence, exampleThreshold, data[:lag])
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to initialize peak detector.", err)
+	}
+	reference := detector.Clone()
+
+	preprocessor, err := peakdetect.NewLinearDetrend(10)
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to create preprocessor.", err)
+	}
+	detector.SetPreprocessor(preprocessor)
+	for i, v := range data[lag:] {
+		expected := reference.NextDetailed(v)
+		actual := detector.NextDetailed(v)
+		if actual != expected {
+			t.Fatalf("The preprocessor took effect before initialization at index %d.\n  Expected: %+v\n  Actual: %+v", i+lag, expected, actual)
+		}
+	}
+
+	err = detector.Initialize(exampleInfluence, exampleThreshold, data[:lag])
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to initialize peak detector.", err)
+	}
+	signals := detector.NextBatch(data[lag:])
+	if signals[80-lag] != peakdetect.SignalPositive {
+		t.Fatalf("The preprocessor did not take effect at initialization.\n  Expected: %d\n  Actual: %d", peakdetect.SignalPositive, signals[80-lag])
+	}
+}
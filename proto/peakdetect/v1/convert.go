@@ -0,0 +1,248 @@
+package peakdetectv1
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/MicahParks/peakdetect"
+)
+
+// detectorState mirrors the JSON encoding of the state of a peakdetect.PeakDetector. The state is converted through
+// JSON, as its Go type is not exported.
+type detectorState struct {
+	Version         uint8              `json:"version"`
+	Active          peakdetect.Signal  `json:"active,omitempty"`
+	Config          peakdetect.Config  `json:"config"`
+	Count           uint64             `json:"count"`
+	Differences     []float64          `json:"differences,omitempty"`
+	Index           uint               `json:"index"`
+	Input           float64            `json:"input"`
+	InitialOutliers []int              `json:"initialOutliers,omitempty"`
+	Labels          map[string]string  `json:"labels,omitempty"`
+	Mean            float64            `json:"mean"`
+	Persistence     []persistenceEntry `json:"persistence,omitempty"`
+	Refractory      uint               `json:"refractory,omitempty"`
+	Robust          bool               `json:"robust,omitempty"`
+	StdDev          float64            `json:"stdDev"`
+	Value           float64            `json:"value"`
+	Variance        float64            `json:"variance"`
+	Window          []float64          `json:"window"`
+}
+
+type persistenceEntry struct {
+	Input    float64           `json:"input"`
+	Side     peakdetect.Signal `json:"side"`
+	Signaled bool              `json:"signaled,omitempty"`
+}
+
+// FromSignal converts a peakdetect.Signal to a Signal.
+func FromSignal(signal peakdetect.Signal) Signal {
+	switch signal {
+	case peakdetect.SignalNegative:
+		return Signal_SIGNAL_NEGATIVE
+	case peakdetect.SignalPositive:
+		return Signal_SIGNAL_POSITIVE
+	default:
+		return Signal_SIGNAL_NEUTRAL
+	}
+}
+
+// ToSignal converts a Signal to a peakdetect.Signal. SIGNAL_UNSPECIFIED and unknown values are neutral.
+func ToSignal(signal Signal) peakdetect.Signal {
+	switch signal {
+	case Signal_SIGNAL_NEGATIVE:
+		return peakdetect.SignalNegative
+	case Signal_SIGNAL_POSITIVE:
+		return peakdetect.SignalPositive
+	default:
+		return peakdetect.SignalNeutral
+	}
+}
+
+// FromConfig converts a peakdetect.Config to a Config. The custom baseline is not converted.
+func FromConfig(cfg peakdetect.Config) *Config {
+	return &Config{
+		Influence:                 cfg.Influence,
+		Lag:                       uint64(cfg.Lag),
+		Threshold:                 cfg.Threshold,
+		MinCoefficientOfVariation: cfg.MinCoefficientOfVariation,
+		InitialOutliers:           OutlierPolicy(cfg.InitialOutliers),
+		InitialOutlierThreshold:   cfg.InitialOutlierThreshold,
+		TrackMoments:              cfg.TrackMoments,
+		SkewnessBound:             cfg.SkewnessBound,
+		KurtosisBound:             cfg.KurtosisBound,
+		MinStdDev:                 cfg.MinStdDev,
+		QuantizationStep:          cfg.QuantizationStep,
+		Deadband:                  cfg.Deadband,
+		NegativeThreshold:         cfg.NegativeThreshold,
+		NegativeInfluence:         cfg.NegativeInfluence,
+		ReleaseThreshold:          cfg.ReleaseThreshold,
+		Percentile:                cfg.Percentile,
+		Persistence:               uint64(cfg.Persistence),
+		PersistenceWindow:         uint64(cfg.PersistenceWindow),
+		PersistenceBackfill:       cfg.PersistenceBackfill,
+		RefractoryPeriod:          uint64(cfg.RefractoryPeriod),
+		Direction:                 Direction(cfg.Direction),
+		DerivativeOrder:           uint64(cfg.DerivativeOrder),
+		ResyncInterval:            uint64(cfg.ResyncInterval),
+		Missing:                   MissingPolicy(cfg.Missing),
+	}
+}
+
+// ToConfig converts a Config to a peakdetect.Config. A nil Config is the zero value.
+func ToConfig(cfg *Config) peakdetect.Config {
+	return peakdetect.Config{
+		Influence:                 cfg.GetInfluence(),
+		Lag:                       uint(cfg.GetLag()),
+		Threshold:                 cfg.GetThreshold(),
+		MinCoefficientOfVariation: cfg.GetMinCoefficientOfVariation(),
+		InitialOutliers:           peakdetect.OutlierPolicy(cfg.GetInitialOutliers()),
+		InitialOutlierThreshold:   cfg.GetInitialOutlierThreshold(),
+		TrackMoments:              cfg.GetTrackMoments(),
+		SkewnessBound:             cfg.GetSkewnessBound(),
+		KurtosisBound:             cfg.GetKurtosisBound(),
+		MinStdDev:                 cfg.GetMinStdDev(),
+		QuantizationStep:          cfg.GetQuantizationStep(),
+		Deadband:                  cfg.GetDeadband(),
+		NegativeThreshold:         cfg.GetNegativeThreshold(),
+		NegativeInfluence:         cfg.NegativeInfluence,
+		ReleaseThreshold:          cfg.GetReleaseThreshold(),
+		Percentile:                cfg.GetPercentile(),
+		Persistence:               uint(cfg.GetPersistence()),
+		PersistenceWindow:         uint(cfg.GetPersistenceWindow()),
+		PersistenceBackfill:       cfg.GetPersistenceBackfill(),
+		RefractoryPeriod:          uint(cfg.GetRefractoryPeriod()),
+		Direction:                 peakdetect.Direction(cfg.GetDirection()),
+		DerivativeOrder:           uint(cfg.GetDerivativeOrder()),
+		ResyncInterval:            uint(cfg.GetResyncInterval()),
+		Missing:                   peakdetect.MissingPolicy(cfg.GetMissing()),
+	}
+}
+
+// FromResult converts a peakdetect.Result to a Detection.
+func FromResult(result peakdetect.Result) *Detection {
+	return &Detection{
+		Signal:   FromSignal(result.Signal),
+		Value:    result.Value,
+		Stored:   result.Filtered,
+		Mean:     result.Mean,
+		StdDev:   result.StdDev,
+		ZScore:   result.ZScore,
+		Strength: result.Strength,
+	}
+}
+
+// ToResult converts a Detection to a peakdetect.Result.
+func ToResult(detection *Detection) peakdetect.Result {
+	return peakdetect.Result{
+		Filtered: detection.GetStored(),
+		Mean:     detection.GetMean(),
+		Signal:   ToSignal(detection.GetSignal()),
+		Strength: detection.GetStrength(),
+		StdDev:   detection.GetStdDev(),
+		Value:    detection.GetValue(),
+		ZScore:   detection.GetZScore(),
+	}
+}
+
+// FromPeakEvent converts a peakdetect.PeakEvent to a PeakEvent.
+func FromPeakEvent(event peakdetect.PeakEvent) *PeakEvent {
+	return &PeakEvent{
+		StartIndex: event.StartIndex,
+		EndIndex:   event.EndIndex,
+		ApexIndex:  event.ApexIndex,
+		ApexValue:  event.ApexValue,
+		Signal:     FromSignal(event.Signal),
+	}
+}
+
+// ToPeakEvent converts a PeakEvent to a peakdetect.PeakEvent.
+func ToPeakEvent(event *PeakEvent) peakdetect.PeakEvent {
+	return peakdetect.PeakEvent{
+		StartIndex: event.GetStartIndex(),
+		EndIndex:   event.GetEndIndex(),
+		ApexIndex:  event.GetApexIndex(),
+		ApexValue:  event.GetApexValue(),
+		Signal:     ToSignal(event.GetSignal()),
+	}
+}
+
+// FromDetector converts the state of a peakdetect.PeakDetector to a DetectorState. It fails for the same reasons as
+// PeakDetector.MarshalJSON, such as a custom baseline.
+func FromDetector(detector peakdetect.PeakDetector) (*DetectorState, error) {
+	data, err := detector.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	var state detectorState
+	err = json.Unmarshal(data, &state)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode detector state: %w", err)
+	}
+
+	msg := &DetectorState{
+		Config:      FromConfig(state.Config),
+		Window:      state.Window,
+		Mean:        state.Mean,
+		Variance:    state.Variance,
+		PrevValue:   state.Value,
+		Version:     uint32(state.Version),
+		Active:      FromSignal(state.Active),
+		Count:       state.Count,
+		Differences: state.Differences,
+		Index:       uint64(state.Index),
+		Input:       state.Input,
+		Labels:      state.Labels,
+		Refractory:  uint64(state.Refractory),
+		Robust:      state.Robust,
+		StdDev:      state.StdDev,
+	}
+	for _, i := range state.InitialOutliers {
+		msg.InitialOutliers = append(msg.InitialOutliers, int64(i))
+	}
+	for _, entry := range state.Persistence {
+		msg.Persistence = append(msg.Persistence, &PersistenceEntry{
+			Input:    entry.Input,
+			Side:     FromSignal(entry.Side),
+			Signaled: entry.Signaled,
+		})
+	}
+	return msg, nil
+}
+
+// ToDetector restores the state of a peakdetect.PeakDetector from a DetectorState. The state is validated the same way
+// as by PeakDetector.UnmarshalJSON, so the error wraps peakdetect.ErrInvalidState if it is not valid.
+func ToDetector(msg *DetectorState, detector peakdetect.PeakDetector) error {
+	state := detectorState{
+		Version:     uint8(msg.GetVersion()),
+		Active:      ToSignal(msg.GetActive()),
+		Config:      ToConfig(msg.GetConfig()),
+		Count:       msg.GetCount(),
+		Differences: msg.GetDifferences(),
+		Index:       uint(msg.GetIndex()),
+		Input:       msg.GetInput(),
+		Labels:      msg.GetLabels(),
+		Mean:        msg.GetMean(),
+		Refractory:  uint(msg.GetRefractory()),
+		Robust:      msg.GetRobust(),
+		StdDev:      msg.GetStdDev(),
+		Value:       msg.GetPrevValue(),
+		Variance:    msg.GetVariance(),
+		Window:      msg.GetWindow(),
+	}
+	for _, i := range msg.GetInitialOutliers() {
+		state.InitialOutliers = append(state.InitialOutliers, int(i))
+	}
+	for _, entry := range msg.GetPersistence() {
+		state.Persistence = append(state.Persistence, persistenceEntry{
+			Input:    entry.GetInput(),
+			Side:     ToSignal(entry.GetSide()),
+			Signaled: entry.GetSignaled(),
+		})
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to encode detector state: %w", err)
+	}
+	return detector.UnmarshalJSON(data)
+}
@@ -0,0 +1,138 @@
+package peakdetectv1_test
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/MicahParks/peakdetect"
+	peakdetectv1 "github.com/MicahParks/peakdetect/proto/peakdetect/v1"
+)
+
+const logFmt = "%s\nError: %s"
+
+func TestSignal(t *testing.T) {
+	for _, signal := range []peakdetect.Signal{peakdetect.SignalNegative, peakdetect.SignalNeutral, peakdetect.SignalPositive} {
+		if actual := peakdetectv1.ToSignal(peakdetectv1.FromSignal(signal)); actual != signal {
+			t.Fatalf("The signal did not round trip.\n  Expected: %d\n  Actual: %d", signal, actual)
+		}
+	}
+	if actual := peakdetectv1.ToSignal(peakdetectv1.Signal_SIGNAL_UNSPECIFIED); actual != peakdetect.SignalNeutral {
+		t.Fatalf("An unspecified signal should be neutral.\n  Actual: %d", actual)
+	}
+}
+
+func TestResult(t *testing.T) {
+	result := peakdetect.Result{
+		Filtered: 1.5,
+		Mean:     1,
+		Signal:   peakdetect.SignalPositive,
+		Strength: 1.25,
+		StdDev:   0.5,
+		Value:    3,
+		ZScore:   4,
+	}
+	var detection peakdetectv1.Detection
+	roundTrip(t, peakdetectv1.FromResult(result), &detection)
+	if actual := peakdetectv1.ToResult(&detection); actual != result {
+		t.Fatalf("The result did not round trip.\n  Expected: %+v\n  Actual: %+v", result, actual)
+	}
+}
+
+func TestPeakEvent(t *testing.T) {
+	event := peakdetect.PeakEvent{
+		StartIndex: 10,
+		EndIndex:   14,
+		ApexIndex:  12,
+		ApexValue:  -7.5,
+		Signal:     peakdetect.SignalNegative,
+	}
+	var msg peakdetectv1.PeakEvent
+	roundTrip(t, peakdetectv1.FromPeakEvent(event), &msg)
+	if actual := peakdetectv1.ToPeakEvent(&msg); actual != event {
+		t.Fatalf("The peak event did not round trip.\n  Expected: %+v\n  Actual: %+v", event, actual)
+	}
+}
+
+func TestDetectorState(t *testing.T) {
+	const lag = 20
+	values := make([]float64, 200)
+	for i := range values {
+		values[i] = math.Sin(float64(i) / 3)
+		if i%37 == 0 {
+			values[i] += 10
+		}
+	}
+
+	negativeInfluence := 0.25
+	detector := peakdetect.NewPeakDetector()
+	detector.SetLabels(map[string]string{"series": "proto"})
+	err := detector.InitializeWithConfig(peakdetect.Config{
+		Influence:           0.5,
+		Lag:                 lag,
+		Threshold:           3,
+		NegativeInfluence:   &negativeInfluence,
+		Persistence:         2,
+		PersistenceWindow:   3,
+		PersistenceBackfill: true,
+		RefractoryPeriod:    1,
+		DerivativeOrder:     1,
+	}, values[:lag+1])
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+	detector.NextBatch(values[lag+1 : 100])
+
+	state, err := peakdetectv1.FromDetector(detector)
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to convert detector state.", err)
+	}
+	var msg peakdetectv1.DetectorState
+	roundTrip(t, state, &msg)
+	restored := peakdetect.NewPeakDetector()
+	err = peakdetectv1.ToDetector(&msg, restored)
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to restore detector state.", err)
+	}
+
+	expected, err := detector.MarshalJSON()
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to marshal detector state.", err)
+	}
+	actual, err := restored.MarshalJSON()
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to marshal restored detector state.", err)
+	}
+	if !bytes.Equal(expected, actual) {
+		t.Fatalf("The detector state did not round trip.\n  Expected: %s\n  Actual: %s", expected, actual)
+	}
+	if !reflect.DeepEqual(detector.NextBatch(values[100:]), restored.NextBatch(values[100:])) {
+		t.Fatalf("The restored detector should produce the same signals as the original.")
+	}
+}
+
+func TestToDetectorInvalid(t *testing.T) {
+	msg := &peakdetectv1.DetectorState{
+		Config: &peakdetectv1.Config{Lag: 3, Threshold: 3},
+		Window: []float64{1, 2},
+	}
+	err := peakdetectv1.ToDetector(msg, peakdetect.NewPeakDetector())
+	if !errors.Is(err, peakdetect.ErrInvalidState) {
+		t.Fatalf(logFmt, "Expected an invalid state error.", err)
+	}
+}
+
+func roundTrip(t *testing.T, msg, dst proto.Message) {
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to marshal message.", err)
+	}
+	err = proto.Unmarshal(data, dst)
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to unmarshal message.", err)
+	}
+}
@@ -0,0 +1,161 @@
+package peakdetect
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// AggregationMean uses the mean of the values in each interval.
+	AggregationMean Aggregation = iota
+	// AggregationLast uses the last value in each interval.
+	AggregationLast
+	// AggregationLinear linearly interpolates between the values on either side of each grid time.
+	AggregationLinear
+)
+
+var (
+	// ErrInvalidInterval indicates that the interval provided is not valid.
+	ErrInvalidInterval = errors.New("the interval provided is invalid")
+	// ErrOutOfOrder indicates that a timestamped value was older than a previous value.
+	ErrOutOfOrder = errors.New("the timestamped value is out of order")
+)
+
+// Aggregation is a set of enums that indicates how a Resampler determines the value for a grid time.
+type Aggregation uint8
+
+// Sample is a timestamped value.
+type Sample struct {
+	Time  time.Time
+	Value float64
+}
+
+// Resampler aligns timestamped values onto a fixed grid of times, one interval apart, so irregularly reported data can
+// be given to a detector that expects regular samples. Grid times are multiples of the interval since the zero time, as
+// with time.Time.Truncate.
+//
+// For AggregationMean and AggregationLast, the value for a grid time is aggregated from the values in the interval
+// starting at that time. An interval is complete when a value after it is given. Intervals without any values repeat
+// the value of the previous interval. For AggregationLinear, the value for a grid time is interpolated as soon as a
+// value at or after that time is given.
+type Resampler struct {
+	aggregation Aggregation
+	bucket      time.Time
+	count       uint
+	emitted     float64
+	interval    time.Duration
+	last        float64
+	prev        Sample
+	started     bool
+	sum         float64
+}
+
+// NewResampler creates a new Resampler with the given interval and aggregation.
+func NewResampler(interval time.Duration, aggregation Aggregation) (*Resampler, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("the interval for a resampler must be positive: %w", ErrInvalidInterval)
+	}
+	return &Resampler{
+		aggregation: aggregation,
+		interval:    interval,
+	}, nil
+}
+
+// Next adds the timestamped value and returns the samples for any grid times it completes. Values must be given in
+// chronological order.
+func (r *Resampler) Next(t time.Time, value float64) ([]Sample, error) {
+	if r.aggregation == AggregationLinear {
+		return r.nextLinear(t, value)
+	}
+
+	if !r.started {
+		r.started = true
+		r.bucket = t.Truncate(r.interval)
+	}
+	if t.Before(r.bucket) {
+		return nil, fmt.Errorf("the time %s is before the current interval starting at %s: %w", t, r.bucket, ErrOutOfOrder)
+	}
+
+	var samples []Sample
+	bucket := t.Truncate(r.interval)
+	if bucket.After(r.bucket) {
+		samples = append(samples, r.flush())
+		for g := r.bucket.Add(r.interval); g.Before(bucket); g = g.Add(r.interval) {
+			samples = append(samples, Sample{
+				Time:  g,
+				Value: samples[len(samples)-1].Value,
+			})
+		}
+		r.bucket = bucket
+	}
+
+	r.count++
+	r.sum += value
+	r.last = value
+
+	return samples, nil
+}
+
+// Flush returns the sample for the current incomplete interval, if any. It is only meaningful for AggregationMean and
+// AggregationLast, as AggregationLinear never has an incomplete interval.
+func (r *Resampler) Flush() (sample Sample, ok bool) {
+	if r.aggregation == AggregationLinear || r.count == 0 {
+		return Sample{}, false
+	}
+	sample = r.flush()
+	r.bucket = r.bucket.Add(r.interval)
+	return sample, true
+}
+
+// flush aggregates the current interval and resets it. An interval without any values, such as the one after Flush,
+// repeats the previous sample.
+func (r *Resampler) flush() Sample {
+	if r.count != 0 {
+		r.emitted = r.last
+		if r.aggregation == AggregationMean {
+			r.emitted = r.sum / float64(r.count)
+		}
+	}
+	r.count = 0
+	r.sum = 0
+	return Sample{
+		Time:  r.bucket,
+		Value: r.emitted,
+	}
+}
+
+func (r *Resampler) nextLinear(t time.Time, value float64) ([]Sample, error) {
+	if !r.started {
+		r.started = true
+		r.prev = Sample{
+			Time:  t,
+			Value: value,
+		}
+		r.bucket = t.Truncate(r.interval)
+		if r.bucket.Before(t) {
+			r.bucket = r.bucket.Add(r.interval)
+		}
+	}
+	if t.Before(r.prev.Time) {
+		return nil, fmt.Errorf("the time %s is before the previous time %s: %w", t, r.prev.Time, ErrOutOfOrder)
+	}
+
+	var samples []Sample
+	for ; !r.bucket.After(t); r.bucket = r.bucket.Add(r.interval) {
+		v := value
+		if elapsed := t.Sub(r.prev.Time); elapsed > 0 {
+			v = r.prev.Value + (value-r.prev.Value)*float64(r.bucket.Sub(r.prev.Time))/float64(elapsed)
+		}
+		samples = append(samples, Sample{
+			Time:  r.bucket,
+			Value: v,
+		})
+	}
+	r.prev = Sample{
+		Time:  t,
+		Value: value,
+	}
+
+	return samples, nil
+}
@@ -0,0 +1,96 @@
+package peakdetect_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/MicahParks/peakdetect"
+)
+
+func TestNewResampler(t *testing.T) {
+	_, err := peakdetect.NewResampler(0, peakdetect.AggregationMean)
+	if !errors.Is(err, peakdetect.ErrInvalidInterval) {
+		t.Fatalf("Invalid interval did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidInterval, err)
+	}
+}
+
+func TestResampler_Next(t *testing.T) {
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	input := []peakdetect.Sample{
+		{Time: start.Add(1 * time.Second), Value: 1},
+		{Time: start.Add(4 * time.Second), Value: 3},
+		{Time: start.Add(12 * time.Second), Value: 5},
+		{Time: start.Add(22 * time.Second), Value: 10},
+	}
+
+	testCases := []struct {
+		aggregation peakdetect.Aggregation
+		expected    []float64
+	}{
+		{aggregation: peakdetect.AggregationMean, expected: []float64{2, 5, 10}},
+		{aggregation: peakdetect.AggregationLast, expected: []float64{3, 5, 10}},
+		{aggregation: peakdetect.AggregationLinear, expected: []float64{4.5, 9}},
+	}
+	for _, tc := range testCases {
+		resampler, err := peakdetect.NewResampler(10*time.Second, tc.aggregation)
+		if err != nil {
+			t.Fatalf(logFmt, "Failed to create resampler.", err)
+		}
+
+		var samples []peakdetect.Sample
+		for _, s := range input {
+			out, err := resampler.Next(s.Time, s.Value)
+			if err != nil {
+				t.Fatalf(logFmt, "Failed to resample value.", err)
+			}
+			samples = append(samples, out...)
+		}
+		if sample, ok := resampler.Flush(); ok {
+			samples = append(samples, sample)
+		}
+
+		if len(samples) != len(tc.expected) {
+			t.Fatalf("Unexpected number of samples for aggregation %d.\n  Expected: %d\n  Actual: %d", tc.aggregation, len(tc.expected), len(samples))
+		}
+		for i, sample := range samples {
+			if sample.Value != tc.expected[i] {
+				t.Fatalf("Sample value did not match for aggregation %d at index %d.\n  Expected: %f\n  Actual: %f", tc.aggregation, i, tc.expected[i], sample.Value)
+			}
+		}
+
+		_, err = resampler.Next(start, 0)
+		if !errors.Is(err, peakdetect.ErrOutOfOrder) {
+			t.Fatalf("Out of order value did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrOutOfOrder, err)
+		}
+	}
+}
+
+func TestResampler_FlushGap(t *testing.T) {
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, aggregation := range []peakdetect.Aggregation{peakdetect.AggregationMean, peakdetect.AggregationLast} {
+		resampler, err := peakdetect.NewResampler(10*time.Second, aggregation)
+		if err != nil {
+			t.Fatalf(logFmt, "Failed to create resampler.", err)
+		}
+
+		_, _ = resampler.Next(start, 4)
+		sample, ok := resampler.Flush()
+		if !ok || sample.Value != 4 {
+			t.Fatalf("Unexpected flushed sample for aggregation %d.\n  Expected: %f\n  Actual: %f", aggregation, 4.0, sample.Value)
+		}
+
+		samples, err := resampler.Next(start.Add(35*time.Second), 8)
+		if err != nil {
+			t.Fatalf(logFmt, "Failed to resample value.", err)
+		}
+		expected := []peakdetect.Sample{
+			{Time: start.Add(10 * time.Second), Value: 4},
+			{Time: start.Add(20 * time.Second), Value: 4},
+		}
+		if !reflect.DeepEqual(samples, expected) {
+			t.Fatalf("Gap after flush was not filled with the last sample for aggregation %d.\n  Expected: %v\n  Actual: %v", aggregation, expected, samples)
+		}
+	}
+}
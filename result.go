@@ -0,0 +1,54 @@
+package peakdetect
+
+// Result is the signal for a value along with the detector state used to determine it.
+type Result struct {
+	// Filtered is the value stored in the lag window. It differs from Value when a signal is influence adjusted.
+	Filtered float64
+	// Mean is the moving mean of the window before the value was processed.
+	Mean float64
+	// Signal is the signal for the value.
+	Signal Signal
+	// Strength is the exceedance ratio of the value, which is its absolute deviation from the moving mean divided by the
+	// threshold multiplied by the moving standard deviation. It is at least one for any value beyond the threshold, so
+	// it can rank signals by severity. It is computed the same way for values that were suppressed by an option.
+	Strength float64
+	// StdDev is the moving population standard deviation of the window before the value was processed. It includes the
+	// floors from Config.MinStdDev and Config.QuantizationStep.
+	StdDev float64
+	// Value is the value that was processed.
+	Value float64
+	// ZScore is the number of standard deviations the value is from the moving mean. It is of the value after any
+	// Preprocessor and Config.DerivativeOrder, which is what the moving mean and standard deviation are of.
+	ZScore float64
+}
+
+func (p *peakDetector) NextDetailed(value float64) Result {
+	p.Next(value)
+	return p.result(value)
+}
+
+// result returns the Result for the most recently processed value.
+func (p *peakDetector) result(value float64) Result {
+	return Result{
+		Filtered: p.prevValue,
+		Mean:     p.last.mean,
+		Signal:   p.last.signal,
+		Strength: p.last.strength(),
+		StdDev:   p.last.stdDev,
+		Value:    value,
+		ZScore:   zScore(p.last.value-p.last.mean, p.last.stdDev),
+	}
+}
+
+func (p *peakDetector) NextBatchDetailed(values []float64) []Result {
+	results := make([]Result, len(values))
+	for i, v := range values {
+		results[i] = p.NextDetailed(v)
+		for _, offset := range p.last.backfilled {
+			if offset <= uint(i) {
+				results[i-int(offset)].Signal = results[i].Signal
+			}
+		}
+	}
+	return results
+}
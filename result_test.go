@@ -0,0 +1,78 @@
+package peakdetect_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/MicahParks/peakdetect"
+)
+
+func TestPeakDetector_NextDetailed(t *testing.T) {
+	detector := peakdetect.NewPeakDetector()
+	err := detector.Initialize(exampleInfluence, exampleThreshold, exampleInputs[0:exampleLag])
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+
+	results := detector.NextBatchDetailed(exampleInputs[exampleLag:])
+	for i, result := range results {
+		expected := exampleOutputs[exampleLag+i]
+		if result.Signal != expected {
+			t.Fatalf("Incorrect signal at index %d.\n  Expected: %d\n  Actual: %d", exampleLag+i, expected, result.Signal)
+		}
+		if result.Value != exampleInputs[exampleLag+i] {
+			t.Fatalf("Incorrect value at index %d.\n  Expected: %f\n  Actual: %f", exampleLag+i, exampleInputs[exampleLag+i], result.Value)
+		}
+		if result.ZScore != (result.Value-result.Mean)/result.StdDev {
+			t.Fatalf("Incorrect z-score at index %d.\n  Actual: %+v", exampleLag+i, result)
+		}
+		if (result.Strength > 1) != (result.Signal != peakdetect.SignalNeutral) {
+			t.Fatalf("Strength should exceed one only for signals at index %d.\n  Actual: %+v", exampleLag+i, result)
+		}
+		if math.Abs(result.Strength-math.Abs(result.ZScore)/exampleThreshold) > 1e-12 {
+			t.Fatalf("Incorrect strength at index %d.\n  Actual: %+v", exampleLag+i, result)
+		}
+		if result.Signal == peakdetect.SignalNeutral && result.Filtered != result.Value {
+			t.Fatalf("Filtered value should not differ for a neutral signal at index %d.\n  Actual: %+v", exampleLag+i, result)
+		}
+	}
+
+	window := detector.Window()
+	last := results[len(results)-1]
+	if window[len(window)-1] != last.Filtered {
+		t.Fatalf("Filtered value should be stored in the window.\n  Expected: %f\n  Actual: %f", last.Filtered, window[len(window)-1])
+	}
+}
+
+func TestPeakDetector_NextDetailedDerivative(t *testing.T) {
+	values := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 14, 16, 18}
+	detector := peakdetect.NewPeakDetector()
+	err := detector.InitializeWithConfig(peakdetect.Config{
+		DerivativeOrder: 1,
+		MinStdDev:       0.5,
+		Threshold:       3,
+	}, values[:11])
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+
+	expected := []struct {
+		signal peakdetect.Signal
+		zScore float64
+	}{
+		{signal: peakdetect.SignalNeutral, zScore: 0},
+		{signal: peakdetect.SignalNeutral, zScore: 2},
+	}
+	for i, e := range expected {
+		result := detector.NextDetailed(values[11+i])
+		if result.Signal != e.signal {
+			t.Fatalf("Incorrect signal at index %d.\n  Expected: %d\n  Actual: %d", 11+i, e.signal, result.Signal)
+		}
+		if result.ZScore != e.zScore {
+			t.Fatalf("Incorrect z-score at index %d.\n  Expected: %f\n  Actual: %f", 11+i, e.zScore, result.ZScore)
+		}
+		if result.Value != values[11+i] {
+			t.Fatalf("Incorrect value at index %d.\n  Expected: %f\n  Actual: %f", 11+i, values[11+i], result.Value)
+		}
+	}
+}
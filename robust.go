@@ -0,0 +1,97 @@
+package peakdetect
+
+import (
+	"sort"
+)
+
+// madScale scales the median absolute deviation to the standard deviation of a normal distribution.
+const madScale = 1.4826
+
+// NewRobustPeakDetector creates a new PeakDetector that uses the moving median and median absolute deviation (MAD) of
+// the lag window instead of the moving mean and standard deviation. This is the robust variant of the algorithm
+// recommended for data with large outliers, as a single outlier in the lag window barely moves the median or the MAD.
+// The MAD is scaled by 1.4826, so the threshold has the same meaning for normally distributed data. The Mean and
+// StdDev of an Explanation or Result are the median and scaled MAD.
+//
+// Each update is O(lag) in the worst case to keep the window sorted, with O(log lag) comparisons. It must be
+// initialized before use.
+func NewRobustPeakDetector() PeakDetector {
+	return &peakDetector{
+		baseline: &movingMedianMAD{},
+	}
+}
+
+// movingMedianMAD tracks the median and median absolute deviation of a sliding window.
+type movingMedianMAD struct {
+	sortedWindow
+}
+
+func (m *movingMedianMAD) initialize(initialValues []float64) (median, spread float64) {
+	m.sortedWindow.initialize(initialValues)
+	return m.stats()
+}
+
+func (m *movingMedianMAD) next(value float64) (median, spread float64) {
+	m.sortedWindow.next(value)
+	return m.stats()
+}
+
+func (m *movingMedianMAD) clone() baseline {
+	return &movingMedianMAD{
+		sortedWindow: m.sortedWindow.clone(),
+	}
+}
+
+func (m *movingMedianMAD) reset() {
+	*m = movingMedianMAD{}
+}
+
+func (m *movingMedianMAD) window() []float64 {
+	return m.sortedWindow.window()
+}
+
+// stats returns the median and the scaled median absolute deviation of the sorted window.
+func (m *movingMedianMAD) stats() (median, spread float64) {
+	n := len(m.sorted)
+	middle := n / 2
+	median = m.sorted[middle]
+	mad := m.kthDeviation(median, middle)
+	if n%2 == 0 {
+		median = (m.sorted[middle-1] + m.sorted[middle]) / 2
+		mad = (m.kthDeviation(median, middle-1) + m.kthDeviation(median, middle)) / 2
+	}
+	return median, madScale * mad
+}
+
+// kthDeviation returns the kth smallest, starting at zero, absolute deviation of the sorted window from the median.
+//
+// The deviations of the values below the median, read from the median outward, are ascending, as are the deviations of
+// the remaining values. The kth smallest of the two ascending sequences is found by bisecting how many come from the
+// first, so no deviations are materialized.
+func (m *movingMedianMAD) kthDeviation(median float64, k int) float64 {
+	split := sort.SearchFloat64s(m.sorted, median)
+	lower := func(i int) float64 { return median - m.sorted[split-1-i] }
+	upper := func(j int) float64 { return m.sorted[split+j] - median }
+	lowerLen, upperLen := split, len(m.sorted)-split
+
+	// Take i deviations from the lower sequence and k+1-i from the upper.
+	lo, hi := max(0, k+1-upperLen), min(k+1, lowerLen)
+	for lo < hi {
+		i := (lo + hi) / 2
+		if lower(i) < upper(k-i) {
+			lo = i + 1
+		} else {
+			hi = i
+		}
+	}
+
+	i, j := lo, k+1-lo
+	var deviation float64
+	if i > 0 {
+		deviation = lower(i - 1)
+	}
+	if j > 0 && upper(j-1) > deviation {
+		deviation = upper(j - 1)
+	}
+	return deviation
+}
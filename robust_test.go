@@ -0,0 +1,112 @@
+package peakdetect_test
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/MicahParks/peakdetect"
+)
+
+func TestRobustPeakDetector_MedianMAD(t *testing.T) {
+	for _, lag := range []int{1, 2, 5, 8} {
+		r := rand.New(rand.NewSource(int64(lag)))
+		data := make([]float64, 200)
+		for i := range data {
+			// Few distinct values, so the window often has duplicates.
+			data[i] = float64(r.Intn(6))
+		}
+
+		detector := peakdetect.NewRobustPeakDetector()
+		err := detector.Initialize(1, 3, data[:lag])
+		if err != nil {
+			t.Fatalf(logFmt, "Error during initilization.", err)
+		}
+
+		for i, v := range data[lag:] {
+			window := detector.Window()
+			expectedMedian, expectedMAD := referenceMedianMAD(window)
+
+			detector.Next(v)
+			explanation := detector.Explain()
+			if explanation.Mean != expectedMedian || math.Abs(explanation.StdDev-1.4826*expectedMAD) > 1e-12 {
+				t.Fatalf("Incorrect median or MAD at index %d with lag %d for window %v.\n  Expected: %f, %f\n  Actual: %f, %f", lag+i, lag, window, expectedMedian, 1.4826*expectedMAD, explanation.Mean, explanation.StdDev)
+			}
+		}
+	}
+}
+
+func TestRobustPeakDetector_Outlier(t *testing.T) {
+	// A single huge outlier in the lag window inflates the standard deviation, hiding the peak that follows.
+	initialValues := []float64{10, 11, 9, 10, 1000, 10, 11, 9, 10, 11}
+	const peak = 20
+
+	detector := peakdetect.NewPeakDetector()
+	err := detector.Initialize(0, 3, initialValues)
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+	if signal := detector.Next(peak); signal != peakdetect.SignalNeutral {
+		t.Fatalf("The outlier should hide the peak from the standard detector.\n  Actual: %d", signal)
+	}
+
+	robust := peakdetect.NewRobustPeakDetector()
+	err = robust.Initialize(0, 3, initialValues)
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+	if signal := robust.Next(peak); signal != peakdetect.SignalPositive {
+		t.Fatalf("The robust detector should signal despite the outlier.\n  Expected: %d\n  Actual: %d", peakdetect.SignalPositive, signal)
+	}
+	if summary := robust.Summary(); summary.Mean <= 100 {
+		t.Fatalf("The summary should report the mean, not the median.\n  Actual: %f", summary.Mean)
+	}
+}
+
+func TestRobustPeakDetector_MarshalBinary(t *testing.T) {
+	const split = exampleLag + 30
+
+	detector := peakdetect.NewRobustPeakDetector()
+	err := detector.Initialize(exampleInfluence, exampleThreshold, exampleInputs[:exampleLag])
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+	detector.NextBatch(exampleInputs[exampleLag:split])
+
+	data, err := detector.MarshalBinary()
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to marshal detector state.", err)
+	}
+	restored := peakdetect.NewPeakDetector()
+	err = restored.UnmarshalBinary(data)
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to unmarshal detector state.", err)
+	}
+
+	expected := detector.NextBatchDetailed(exampleInputs[split:])
+	actual := restored.NextBatchDetailed(exampleInputs[split:])
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("Restored robust detector results do not match.\n  Expected: %v\n  Actual: %v", expected, actual)
+	}
+}
+
+func referenceMedianMAD(values []float64) (median, mad float64) {
+	median = referenceMedian(values)
+	deviations := make([]float64, len(values))
+	for i, v := range values {
+		deviations[i] = math.Abs(v - median)
+	}
+	return median, referenceMedian(deviations)
+}
+
+func referenceMedian(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
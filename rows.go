@@ -0,0 +1,138 @@
+package peakdetect
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrMissingColumn indicates that a column required by a RowsConfig is not in the result set.
+var ErrMissingColumn = errors.New("the column is not in the result set")
+
+// RowsConfig describes the columns of a result set consumed by ConsumeRows.
+type RowsConfig struct {
+	// ValueColumn is the name of the column with the values. It is required.
+	ValueColumn string
+	// TimeColumn is the name of the column with the timestamps of the values. It is optional.
+	TimeColumn string
+	// SeriesColumn is the name of the column with the key of the series each value belongs to. It is optional. If it is
+	// empty, every value belongs to the series with an empty key.
+	SeriesColumn string
+}
+
+// ConsumeRows feeds detectors from the rows of a SQL query, such as a backfill from a data warehouse. Rows are processed
+// in the order of the result set, so it should be ordered by time.
+//
+// The detector function returns the initialized PeakDetector for a series. It is called once for each series, the
+// first time the series is seen. A nil PeakDetector skips the series. The handle function is called with the event for
+// every value processed. The index of an event counts the values processed for its series. Rows with a NULL series key
+// are skipped.
+//
+// NULL values are given to the detector as missing with PeakDetector.NextMaybe, so they are handled according to the
+// Config.Missing of the detector. Their events have a NaN value. For MissingReject, ConsumeRows stops with the error
+// wrapping ErrMissingValue.
+//
+// The rows are closed before returning.
+func ConsumeRows(rows *sql.Rows, cfg RowsConfig, detector func(series string) (PeakDetector, error), handle func(series string, event SignalEvent) error) error {
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return fmt.Errorf("failed to get the columns of the result set: %w", err)
+	}
+
+	var (
+		series sql.NullString
+		t      sql.NullTime
+		value  sql.NullFloat64
+	)
+	dest := make([]any, len(columns))
+	for i := range dest {
+		dest[i] = new(any)
+	}
+	for _, c := range []struct {
+		name     string
+		dest     any
+		required bool
+	}{
+		{name: cfg.ValueColumn, dest: &value, required: true},
+		{name: cfg.TimeColumn, dest: &t},
+		{name: cfg.SeriesColumn, dest: &series},
+	} {
+		if c.name == "" && !c.required {
+			continue
+		}
+		i := columnIndex(columns, c.name)
+		if i == -1 {
+			return fmt.Errorf("the column %q is not in the result set: %w", c.name, ErrMissingColumn)
+		}
+		dest[i] = c.dest
+	}
+
+	type seriesState struct {
+		detector PeakDetector
+		index    uint64
+	}
+	states := make(map[string]*seriesState)
+
+	for rows.Next() {
+		err = rows.Scan(dest...)
+		if err != nil {
+			return fmt.Errorf("failed to scan row: %w", err)
+		}
+		if cfg.SeriesColumn != "" && !series.Valid {
+			continue
+		}
+
+		state, ok := states[series.String]
+		if !ok {
+			d, err := detector(series.String)
+			if err != nil {
+				return fmt.Errorf("failed to get the detector for series %q: %w", series.String, err)
+			}
+			state = &seriesState{detector: d}
+			states[series.String] = state
+		}
+		if state.detector == nil {
+			continue
+		}
+
+		v := value.Float64
+		if !value.Valid {
+			v = math.NaN()
+		}
+		signal, err := state.detector.NextMaybe(v, value.Valid)
+		if err != nil {
+			return fmt.Errorf("failed to process the value for series %q: %w", series.String, err)
+		}
+
+		event := SignalEvent{
+			Index:  state.index,
+			Labels: state.detector.Labels(),
+			Signal: signal,
+			Time:   t.Time,
+			Value:  v,
+		}
+		state.index++
+		err = handle(series.String, event)
+		if err != nil {
+			return fmt.Errorf("failed to handle event for series %q: %w", series.String, err)
+		}
+	}
+	err = rows.Err()
+	if err != nil {
+		return fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return nil
+}
+
+func columnIndex(columns []string, name string) int {
+	for i, column := range columns {
+		if column == name {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,159 @@
+package peakdetect_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/MicahParks/peakdetect"
+)
+
+const rowsDriverName = "peakdetect_test_rows"
+
+var rowsTestData [][]driver.Value
+
+func init() {
+	sql.Register(rowsDriverName, rowsDriver{})
+}
+
+func TestConsumeRows(t *testing.T) {
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	rowsTestData = nil
+	for i, v := range exampleInputs {
+		var value driver.Value = v
+		if i == exampleLag+1 {
+			value = nil
+		}
+		ts := start.Add(time.Duration(i) * time.Second)
+		rowsTestData = append(rowsTestData,
+			[]driver.Value{"ignored", "a", ts, value},
+			[]driver.Value{"ignored", nil, ts, v},
+			[]driver.Value{"ignored", "b", ts, value},
+		)
+	}
+
+	db, err := sql.Open(rowsDriverName, "")
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to open database.", err)
+	}
+	defer db.Close()
+
+	for _, policy := range []peakdetect.MissingPolicy{peakdetect.MissingSkip, peakdetect.MissingRepeat, peakdetect.MissingReject} {
+		rows, err := db.Query("SELECT other, series, time, value FROM samples")
+		if err != nil {
+			t.Fatalf(logFmt, "Failed to query.", err)
+		}
+
+		seen := map[string]int{}
+		detector := func(series string) (peakdetect.PeakDetector, error) {
+			if series == "b" {
+				return nil, nil
+			}
+			detector := peakdetect.NewPeakDetector()
+			return detector, detector.InitializeWithConfig(peakdetect.Config{
+				Influence: exampleInfluence,
+				Missing:   policy,
+				Threshold: exampleThreshold,
+			}, exampleInputs[:exampleLag])
+		}
+		var events []peakdetect.SignalEvent
+		handle := func(series string, event peakdetect.SignalEvent) error {
+			seen[series]++
+			if seen[series] > exampleLag {
+				events = append(events, event)
+			}
+			return nil
+		}
+
+		cfg := peakdetect.RowsConfig{
+			ValueColumn:  "value",
+			TimeColumn:   "time",
+			SeriesColumn: "series",
+		}
+		err = peakdetect.ConsumeRows(rows, cfg, detector, handle)
+		if policy == peakdetect.MissingReject {
+			if !errors.Is(err, peakdetect.ErrMissingValue) {
+				t.Fatalf("NULL value did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrMissingValue, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf(logFmt, "Failed to consume rows.", err)
+		}
+
+		if len(seen) != 1 {
+			t.Fatalf("Unexpected series handled.\n  Actual: %v", seen)
+		}
+		if seen["a"] != len(exampleInputs) {
+			t.Fatalf("Incorrect number of events.\n  Expected: %d\n  Actual: %d", len(exampleInputs), seen["a"])
+		}
+		for i, event := range events {
+			if event.Index != uint64(exampleLag+i) {
+				t.Fatalf("Incorrect index.\n  Expected: %d\n  Actual: %d", exampleLag+i, event.Index)
+			}
+			if event.Time.IsZero() {
+				t.Fatalf("Time was not scanned for index %d.", event.Index)
+			}
+			if missing := event.Index == exampleLag+1; missing != math.IsNaN(event.Value) {
+				t.Fatalf("Only the NULL value should be NaN at index %d.\n  Actual: %f", event.Index, event.Value)
+			}
+		}
+	}
+}
+
+func TestConsumeRowsMissingColumn(t *testing.T) {
+	rowsTestData = nil
+	db, err := sql.Open(rowsDriverName, "")
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to open database.", err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query("SELECT other, series, time, value FROM samples")
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to query.", err)
+	}
+	err = peakdetect.ConsumeRows(rows, peakdetect.RowsConfig{ValueColumn: "reading"}, nil, nil)
+	if !errors.Is(err, peakdetect.ErrMissingColumn) {
+		t.Fatalf("Missing column did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrMissingColumn, err)
+	}
+}
+
+// rowsDriver is a minimal database/sql driver that returns rowsTestData for any query.
+type rowsDriver struct{}
+
+func (rowsDriver) Open(string) (driver.Conn, error) { return rowsConn{}, nil }
+
+type rowsConn struct{}
+
+func (rowsConn) Prepare(string) (driver.Stmt, error) { return rowsStmt{}, nil }
+func (rowsConn) Close() error                        { return nil }
+func (rowsConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+type rowsStmt struct{}
+
+func (rowsStmt) Close() error                               { return nil }
+func (rowsStmt) NumInput() int                              { return 0 }
+func (rowsStmt) Exec([]driver.Value) (driver.Result, error) { return nil, errors.New("not supported") }
+func (rowsStmt) Query([]driver.Value) (driver.Rows, error) {
+	return &rowsRows{data: rowsTestData}, nil
+}
+
+type rowsRows struct {
+	data [][]driver.Value
+}
+
+func (r *rowsRows) Columns() []string { return []string{"other", "series", "time", "value"} }
+func (r *rowsRows) Close() error      { return nil }
+func (r *rowsRows) Next(dest []driver.Value) error {
+	if len(r.data) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.data[0])
+	r.data = r.data[1:]
+	return nil
+}
@@ -0,0 +1,159 @@
+package peakdetect
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	// SeasonalityHourOfDay keeps a baseline for each hour of the day.
+	SeasonalityHourOfDay Seasonality = iota
+	// SeasonalityDayOfWeek keeps a baseline for each day of the week.
+	SeasonalityDayOfWeek
+	// SeasonalityHourOfWeek keeps a baseline for each hour of each day of the week.
+	SeasonalityHourOfWeek
+)
+
+// Seasonality is a set of enums that indicates how a SeasonalDetector divides time into buckets.
+type Seasonality uint8
+
+// Buckets returns the number of buckets for the Seasonality.
+func (s Seasonality) Buckets() int {
+	switch s {
+	case SeasonalityDayOfWeek:
+		return 7
+	case SeasonalityHourOfWeek:
+		return 7 * 24
+	}
+	return 24
+}
+
+// Bucket returns the index of the bucket for the given time. The time is bucketed in its own location, so convert it
+// with time.Time.In to use the local time of the data.
+func (s Seasonality) Bucket(t time.Time) int {
+	switch s {
+	case SeasonalityDayOfWeek:
+		return int(t.Weekday())
+	case SeasonalityHourOfWeek:
+		return int(t.Weekday())*24 + t.Hour()
+	}
+	return t.Hour()
+}
+
+// SeasonalDetector detects peaks in timestamped data relative to a baseline for the matching time-of-day or
+// day-of-week bucket. Data with a strong daily or weekly shape, such as business metrics, makes a single moving window
+// signal on every regular rise, like every Monday morning. Use NewPeriodicDetector for a custom period and bucket
+// width, such as a baseline for each minute of the day.
+//
+// Each bucket has its own PeakDetector, whose lag window holds the most recent values in that bucket. A bucket does not
+// signal until it has received Config.Lag values, plus Config.DerivativeOrder, which are used to initialize its
+// PeakDetector. Values that are NaN or ±Inf are skipped until then.
+type SeasonalDetector struct {
+	buckets     []seasonalBucket
+	config      Config
+	period      time.Duration
+	seasonality Seasonality
+	width       time.Duration
+}
+
+type seasonalBucket struct {
+	detector PeakDetector
+	warmup   []float64
+}
+
+// NewSeasonalDetector creates a new SeasonalDetector. The cfg is used for the PeakDetector of every bucket. cfg.Lag must
+// be greater than zero and the cfg must pass Config.Validate.
+func NewSeasonalDetector(seasonality Seasonality, cfg Config) (*SeasonalDetector, error) {
+	err := validateSeasonalConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &SeasonalDetector{
+		buckets:     make([]seasonalBucket, seasonality.Buckets()),
+		config:      cfg,
+		seasonality: seasonality,
+	}, nil
+}
+
+// NewPeriodicDetector creates a new SeasonalDetector with a custom period that is divided into buckets of the given
+// width, such as a period of a day with a width of a minute for a baseline for each minute of the day. The width must be
+// greater than zero and evenly divide the period. cfg.Lag must be greater than zero and the cfg must pass
+// Config.Validate.
+//
+// Buckets are aligned to the Unix epoch in the location of each time, so a period that evenly divides a day starts at
+// midnight.
+func NewPeriodicDetector(period, width time.Duration, cfg Config) (*SeasonalDetector, error) {
+	if width <= 0 || period < width || period%width != 0 {
+		return nil, fmt.Errorf("the width %s must be greater than zero and evenly divide the period %s: %w", width, period, ErrInvalidConfig)
+	}
+	err := validateSeasonalConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &SeasonalDetector{
+		buckets: make([]seasonalBucket, period/width),
+		config:  cfg,
+		period:  period,
+		width:   width,
+	}, nil
+}
+
+// validateSeasonalConfig checks that the cfg can initialize the PeakDetector of a bucket.
+func validateSeasonalConfig(cfg Config) error {
+	if cfg.Lag == 0 {
+		return fmt.Errorf("the lag for a seasonal detector must be greater than zero: %w", ErrInvalidConfig)
+	}
+	return cfg.Validate()
+}
+
+// Bucket returns the index of the bucket for the given time.
+func (s *SeasonalDetector) Bucket(t time.Time) int {
+	if s.period == 0 {
+		return s.seasonality.Bucket(t)
+	}
+	_, offset := t.Zone()
+	sinceEpoch := time.Duration(t.Unix()+int64(offset))*time.Second + time.Duration(t.Nanosecond())
+	phase := sinceEpoch % s.period
+	if phase < 0 {
+		phase += s.period
+	}
+	return int(phase / s.width)
+}
+
+// Buckets returns the number of buckets.
+func (s *SeasonalDetector) Buckets() int {
+	return len(s.buckets)
+}
+
+// NextAt processes the value at the given time against the baseline of its bucket.
+func (s *SeasonalDetector) NextAt(t time.Time, value float64) (Signal, error) {
+	index := s.Bucket(t)
+	bucket := &s.buckets[index]
+	if bucket.detector != nil {
+		return bucket.detector.Next(value), nil
+	}
+
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return SignalNeutral, nil
+	}
+	bucket.warmup = append(bucket.warmup, value)
+	if uint(len(bucket.warmup)) < s.config.Lag+s.config.DerivativeOrder {
+		return SignalNeutral, nil
+	}
+	warmup := bucket.warmup
+	bucket.warmup = nil
+	detector := NewPeakDetector()
+	err := detector.InitializeWithConfig(s.config, warmup)
+	if err != nil {
+		return SignalNeutral, fmt.Errorf("failed to initialize the detector for bucket %d: %w", index, err)
+	}
+	bucket.detector = detector
+	return SignalNeutral, nil
+}
+
+// Detector returns the PeakDetector for the bucket at the given index. It is nil until the bucket has received
+// Config.Lag values, plus Config.DerivativeOrder.
+func (s *SeasonalDetector) Detector(bucket int) PeakDetector {
+	return s.buckets[bucket].detector
+}
@@ -0,0 +1,189 @@
+package peakdetect_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/MicahParks/peakdetect"
+)
+
+func TestNewSeasonalDetector(t *testing.T) {
+	_, err := peakdetect.NewSeasonalDetector(peakdetect.SeasonalityHourOfWeek, peakdetect.Config{})
+	if !errors.Is(err, peakdetect.ErrInvalidConfig) {
+		t.Fatalf("Zero lag did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidConfig, err)
+	}
+
+	_, err = peakdetect.NewSeasonalDetector(peakdetect.SeasonalityHourOfDay, peakdetect.Config{Lag: 3, Threshold: -1})
+	if !errors.Is(err, peakdetect.ErrInvalidConfig) {
+		t.Fatalf("Negative threshold did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidConfig, err)
+	}
+}
+
+func TestSeasonalDetector_NextAtWarmup(t *testing.T) {
+	seasonal, err := peakdetect.NewSeasonalDetector(peakdetect.SeasonalityHourOfDay, peakdetect.Config{
+		DerivativeOrder: 1,
+		Lag:             3,
+		Threshold:       3,
+	})
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to create seasonal detector.", err)
+	}
+
+	ts := time.Date(2021, 1, 4, 9, 0, 0, 0, time.UTC)
+	for i, value := range []float64{1, math.NaN(), 2, math.Inf(1), 3, 4} {
+		_, err = seasonal.NextAt(ts.AddDate(0, 0, i), value)
+		if err != nil {
+			t.Fatalf(logFmt, "Failed to process warmup value.", err)
+		}
+	}
+	if seasonal.Detector(9) == nil {
+		t.Fatalf("Bucket was not initialized after its warmup values.")
+	}
+}
+
+func TestSeasonality_Bucket(t *testing.T) {
+	monday := time.Date(2021, 1, 4, 9, 30, 0, 0, time.UTC)
+	testCases := map[peakdetect.Seasonality]int{
+		peakdetect.SeasonalityHourOfDay:  9,
+		peakdetect.SeasonalityDayOfWeek:  1,
+		peakdetect.SeasonalityHourOfWeek: 33,
+	}
+	for seasonality, expected := range testCases {
+		if actual := seasonality.Bucket(monday); actual != expected {
+			t.Fatalf("Incorrect bucket.\n  Expected: %d\n  Actual: %d", expected, actual)
+		}
+	}
+}
+
+func TestSeasonalDetector_NextAt(t *testing.T) {
+	const weeks = 6
+	cfg := peakdetect.Config{
+		Influence: 0.5,
+		Lag:       3,
+		Threshold: 3,
+	}
+	seasonal, err := peakdetect.NewSeasonalDetector(peakdetect.SeasonalityHourOfWeek, cfg)
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to create seasonal detector.", err)
+	}
+
+	start := time.Date(2021, 1, 4, 0, 0, 0, 0, time.UTC)
+	spike := start.Add((weeks-1)*7*24*time.Hour + 27*time.Hour)
+	for ts := start; ts.Before(start.AddDate(0, 0, 7*weeks)); ts = ts.Add(time.Hour) {
+		value := 10 + float64((ts.YearDay()+ts.Hour())%3)
+		if ts.Weekday() == time.Monday && ts.Hour() == 9 {
+			value = 100 + float64(ts.YearDay()%3)
+		}
+		if ts.Equal(spike) {
+			value = 50
+		}
+
+		signal, err := seasonal.NextAt(ts, value)
+		if err != nil {
+			t.Fatalf(logFmt, "Failed to process value.", err)
+		}
+		expected := peakdetect.SignalNeutral
+		if ts.Equal(spike) {
+			expected = peakdetect.SignalPositive
+		}
+		if signal != expected {
+			t.Fatalf("Incorrect signal at %s.\n  Expected: %d\n  Actual: %d", ts, expected, signal)
+		}
+	}
+}
+
+func TestNewPeriodicDetector(t *testing.T) {
+	cfg := peakdetect.Config{
+		Lag: 3,
+	}
+	for _, tc := range []struct {
+		period time.Duration
+		width  time.Duration
+	}{
+		{period: 24 * time.Hour, width: 0},
+		{period: time.Minute, width: time.Hour},
+		{period: 24 * time.Hour, width: 7 * time.Minute},
+	} {
+		_, err := peakdetect.NewPeriodicDetector(tc.period, tc.width, cfg)
+		if !errors.Is(err, peakdetect.ErrInvalidConfig) {
+			t.Fatalf("Invalid width %s did not produce error.\n  Expected: %s\n  Actual: %s", tc.width, peakdetect.ErrInvalidConfig, err)
+		}
+	}
+
+	_, err := peakdetect.NewPeriodicDetector(24*time.Hour, time.Minute, peakdetect.Config{})
+	if !errors.Is(err, peakdetect.ErrInvalidConfig) {
+		t.Fatalf("Zero lag did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidConfig, err)
+	}
+}
+
+func TestSeasonalDetector_Bucket(t *testing.T) {
+	periodic, err := peakdetect.NewPeriodicDetector(24*time.Hour, time.Minute, peakdetect.Config{Lag: 3})
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to create periodic detector.", err)
+	}
+	if periodic.Buckets() != 24*60 {
+		t.Fatalf("Incorrect number of buckets.\n  Expected: %d\n  Actual: %d", 24*60, periodic.Buckets())
+	}
+
+	location := time.FixedZone("UTC-5", -5*60*60)
+	testCases := map[time.Time]int{
+		time.Date(2021, 1, 4, 0, 0, 0, 0, time.UTC):   0,
+		time.Date(2021, 1, 4, 9, 30, 59, 0, time.UTC): 9*60 + 30,
+		time.Date(1960, 1, 4, 9, 30, 0, 0, time.UTC):  9*60 + 30,
+		time.Date(2021, 1, 4, 9, 30, 0, 0, location):  9*60 + 30,
+	}
+	for ts, expected := range testCases {
+		if actual := periodic.Bucket(ts); actual != expected {
+			t.Fatalf("Incorrect bucket for %s.\n  Expected: %d\n  Actual: %d", ts, expected, actual)
+		}
+	}
+
+	seasonal, err := peakdetect.NewSeasonalDetector(peakdetect.SeasonalityHourOfWeek, peakdetect.Config{Lag: 3})
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to create seasonal detector.", err)
+	}
+	monday := time.Date(2021, 1, 4, 9, 30, 0, 0, time.UTC)
+	if actual := seasonal.Bucket(monday); actual != 33 {
+		t.Fatalf("Incorrect bucket.\n  Expected: %d\n  Actual: %d", 33, actual)
+	}
+}
+
+func TestSeasonalDetector_Periodic(t *testing.T) {
+	const days = 5
+	cfg := peakdetect.Config{
+		Influence: 0.5,
+		Lag:       3,
+		Threshold: 3,
+	}
+	periodic, err := peakdetect.NewPeriodicDetector(24*time.Hour, 15*time.Minute, cfg)
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to create periodic detector.", err)
+	}
+
+	// The morning ramp happens every day, but the spike only happens once.
+	start := time.Date(2021, 1, 4, 0, 0, 0, 0, time.UTC)
+	spike := start.AddDate(0, 0, days-1).Add(14 * time.Hour)
+	for ts := start; ts.Before(start.AddDate(0, 0, days)); ts = ts.Add(15 * time.Minute) {
+		value := 10 + float64((ts.YearDay()+ts.Minute())%3)
+		if ts.Hour() >= 7 && ts.Hour() < 9 {
+			value += float64(ts.Sub(ts.Truncate(24*time.Hour).Add(7*time.Hour)) / time.Minute)
+		}
+		if ts.Equal(spike) {
+			value = 50
+		}
+
+		signal, err := periodic.NextAt(ts, value)
+		if err != nil {
+			t.Fatalf(logFmt, "Failed to process value.", err)
+		}
+		expected := peakdetect.SignalNeutral
+		if ts.Equal(spike) {
+			expected = peakdetect.SignalPositive
+		}
+		if signal != expected {
+			t.Fatalf("Incorrect signal at %s.\n  Expected: %d\n  Actual: %d", ts, expected, signal)
+		}
+	}
+}
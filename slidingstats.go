@@ -0,0 +1,187 @@
+package peakdetect
+
+import (
+	"fmt"
+	"math"
+)
+
+// SlidingStats tracks the mean and population standard deviation of a sliding window of values. It is what a
+// PeakDetector uses for its lag window, and it is useful on its own for any rolling statistics.
+//
+// The window is a ring buffer that is allocated once, when the SlidingStats is created. Each update takes O(1) time and
+// does not allocate, as the mean and variance are updated from only the value that enters the window and the value that
+// leaves it. The updates use compensated summation to limit the floating point error that accumulates over long
+// streams. With SetResyncInterval, the statistics are periodically recomputed from the window, which takes O(n) time
+// for a window of n values. See FixedSlidingStats for integer arithmetic.
+type SlidingStats struct {
+	cache                []float64
+	cacheLen             float64
+	cacheLenU            uint
+	index                uint
+	meanCompensation     float64
+	prevMean             float64
+	prevVariance         float64
+	resyncInterval       uint
+	updates              uint
+	varianceCompensation float64
+}
+
+// NewSlidingStats creates a new SlidingStats. The length of the initialValues is the size of the window. They must be
+// finite and there must be at least one.
+func NewSlidingStats(initialValues []float64) (*SlidingStats, error) {
+	if len(initialValues) == 0 {
+		return nil, fmt.Errorf("the length of the initial values is zero, the length is used as the window size: %w", ErrInvalidInitialValues)
+	}
+	for _, v := range initialValues {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, fmt.Errorf("the initial values must be finite: %w", ErrInvalidInitialValues)
+		}
+	}
+	s := &SlidingStats{}
+	s.initialize(initialValues)
+	return s, nil
+}
+
+// Next adds the value to the window, removing the oldest value, and returns the new mean and population standard
+// deviation of the window.
+func (m *SlidingStats) Next(value float64) (mean, stdDev float64) {
+	return m.next(value)
+}
+
+// Mean returns the mean of the window.
+func (m *SlidingStats) Mean() float64 {
+	return m.prevMean
+}
+
+// StdDev returns the population standard deviation of the window.
+func (m *SlidingStats) StdDev() float64 {
+	return math.Sqrt(m.prevVariance)
+}
+
+// Variance returns the population variance of the window.
+func (m *SlidingStats) Variance() float64 {
+	return m.prevVariance
+}
+
+// Len returns the size of the window.
+func (m *SlidingStats) Len() uint {
+	return m.cacheLenU
+}
+
+// Window returns a copy of the values in the window in chronological order.
+func (m *SlidingStats) Window() []float64 {
+	return m.window()
+}
+
+// SetResyncInterval sets the number of updates after which the mean and standard deviation are recomputed from the
+// window. Zero disables it. See Config.ResyncInterval.
+func (m *SlidingStats) SetResyncInterval(interval uint) {
+	m.resyncInterval = interval
+}
+
+// Clone returns an independent deep copy of the SlidingStats.
+func (m *SlidingStats) Clone() *SlidingStats {
+	clone := *m
+	clone.cache = append([]float64(nil), m.cache...)
+	return &clone
+}
+
+// initialize creates the needed assets for the SlidingStats. It also computes the resulting mean and population
+// standard deviation using Welford's method.
+//
+// https://www.johndcook.com/blog/standard_deviation/
+func (m *SlidingStats) initialize(initialValues []float64) (mean, stdDev float64) {
+	m.cacheLenU = uint(len(initialValues))
+	m.cacheLen = float64(m.cacheLenU)
+	m.cache = make([]float64, m.cacheLenU)
+	copy(m.cache, initialValues)
+	m.index = 0
+
+	mean = initialValues[0]
+	prevMean := mean
+	var sumOfSquares float64
+	for i := uint(2); i <= m.cacheLenU; i++ {
+		value := initialValues[i-1]
+		mean = prevMean + (value-prevMean)/float64(i)
+		sumOfSquares = sumOfSquares + (value-prevMean)*(value-mean)
+		prevMean = mean
+	}
+
+	m.prevMean = mean
+	m.prevVariance = sumOfSquares / m.cacheLen
+	m.meanCompensation, m.varianceCompensation, m.updates = 0, 0, 0
+	return mean, math.Sqrt(m.prevVariance)
+}
+
+func (m *SlidingStats) clone() baseline {
+	return m.Clone()
+}
+
+func (m *SlidingStats) reset() {
+	*m = SlidingStats{}
+}
+
+// window returns a copy of the values in the sliding window in chronological order.
+func (m *SlidingStats) window() []float64 {
+	window := make([]float64, 0, m.cacheLenU)
+	window = append(window, m.cache[m.index:]...)
+	return append(window, m.cache[:m.index]...)
+}
+
+// next computes the next mean and population standard deviation. It uses a sliding window and is based on Welford's
+// method.
+//
+// https://stackoverflow.com/a/14638138/14797322
+func (m *SlidingStats) next(value float64) (mean, stdDev float64) {
+	outOfWindow := m.cache[m.index]
+	m.cache[m.index] = value
+	m.index++
+	if m.index == m.cacheLenU {
+		m.index = 0
+	}
+
+	m.updates++
+	if m.resyncInterval != 0 && m.updates >= m.resyncInterval {
+		m.resync()
+		return m.prevMean, math.Sqrt(m.prevVariance)
+	}
+
+	// The updates are added with Kahan summation, so their rounding errors do not accumulate over long streams.
+	prevMean := m.prevMean
+	m.prevMean, m.meanCompensation = kahanAdd(m.prevMean, m.meanCompensation, (value-outOfWindow)/m.cacheLen)
+	m.prevVariance, m.varianceCompensation = kahanAdd(m.prevVariance, m.varianceCompensation, (value-m.prevMean+outOfWindow-prevMean)*(value-outOfWindow)/m.cacheLen)
+	if m.prevVariance < 0 {
+		// The variance can only be negative because of rounding errors, which would make the standard deviation NaN.
+		m.prevVariance, m.varianceCompensation = 0, 0
+	}
+
+	return m.prevMean, math.Sqrt(m.prevVariance)
+}
+
+// resync recomputes the mean and population variance from the values in the sliding window, discarding any
+// accumulated floating point error.
+func (m *SlidingStats) resync() {
+	var mean float64
+	for _, v := range m.cache {
+		mean += v
+	}
+	mean /= m.cacheLen
+	var variance float64
+	for _, v := range m.cache {
+		variance += (v - mean) * (v - mean)
+	}
+
+	m.prevMean = mean
+	m.prevVariance = variance / m.cacheLen
+	m.meanCompensation, m.varianceCompensation, m.updates = 0, 0, 0
+}
+
+// kahanAdd adds the value to the sum using Kahan summation. The compensation holds the low-order bits lost by previous
+// additions and must be kept alongside the sum.
+//
+// https://en.wikipedia.org/wiki/Kahan_summation_algorithm
+func kahanAdd(sum, compensation, value float64) (newSum, newCompensation float64) {
+	y := value - compensation
+	newSum = sum + y
+	return newSum, (newSum - sum) - y
+}
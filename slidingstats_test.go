@@ -0,0 +1,144 @@
+package peakdetect_test
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/MicahParks/peakdetect"
+)
+
+func TestNewSlidingStats(t *testing.T) {
+	for _, initialValues := range [][]float64{nil, {1, math.NaN()}} {
+		_, err := peakdetect.NewSlidingStats(initialValues)
+		if !errors.Is(err, peakdetect.ErrInvalidInitialValues) {
+			t.Fatalf("Invalid initial values did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidInitialValues, err)
+		}
+	}
+}
+
+func TestSlidingStats_Next(t *testing.T) {
+	stats, err := peakdetect.NewSlidingStats(exampleInputs[:exampleLag])
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to create sliding stats.", err)
+	}
+	if stats.Len() != exampleLag {
+		t.Fatalf("Incorrect length.\n  Expected: %d\n  Actual: %d", exampleLag, stats.Len())
+	}
+
+	for i, v := range exampleInputs[exampleLag:] {
+		mean, stdDev := stats.Next(v)
+		window := exampleInputs[i+1 : i+1+exampleLag]
+		expectedMean, expectedStdDev := meanStdDev(window)
+		if math.Abs(mean-expectedMean) > 1e-9 || math.Abs(stdDev-expectedStdDev) > 1e-9 {
+			t.Fatalf("Incorrect statistics at index %d.\n  Expected: %f, %f\n  Actual: %f, %f", i, expectedMean, expectedStdDev, mean, stdDev)
+		}
+		if stats.Mean() != mean || stats.StdDev() != stdDev {
+			t.Fatalf("The accessors did not match at index %d.\n  Expected: %f, %f\n  Actual: %f, %f", i, mean, stdDev, stats.Mean(), stats.StdDev())
+		}
+	}
+
+	clone := stats.Clone()
+	stats.Next(1000)
+	if clone.Mean() == stats.Mean() {
+		t.Fatalf("The clone was modified by the original.")
+	}
+	window := stats.Window()
+	if window[len(window)-1] != 1000 {
+		t.Fatalf("The window is not in chronological order.\n  Actual: %v", window)
+	}
+}
+
+func TestNewFixedSlidingStats(t *testing.T) {
+	for _, initialValues := range [][]int64{nil, {1, 1 << 31}, {-1 << 31, 1}} {
+		_, err := peakdetect.NewFixedSlidingStats(initialValues)
+		if !errors.Is(err, peakdetect.ErrInvalidInitialValues) {
+			t.Fatalf("Invalid initial values did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidInitialValues, err)
+		}
+	}
+}
+
+func TestFixedSlidingStats_Next(t *testing.T) {
+	const lag = 50
+	r := rand.New(rand.NewSource(1))
+	values := make([]int64, 10000)
+	for i := range values {
+		values[i] = r.Int63n(1<<32) - 1<<31 + 1
+	}
+
+	initial := make([]float64, lag)
+	for i, v := range values[:lag] {
+		initial[i] = float64(v)
+	}
+	fixed, err := peakdetect.NewFixedSlidingStats(values[:lag])
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to create fixed sliding stats.", err)
+	}
+
+	for i := lag; i < len(values); i++ {
+		mean, stdDev := fixed.Next(values[i])
+		window := make([]float64, lag)
+		for j, v := range values[i-lag+1 : i+1] {
+			window[j] = float64(v)
+		}
+		expectedMean, expectedStdDev := meanStdDev(window)
+		if math.Abs(float64(mean)-expectedMean) > 1 || math.Abs(float64(stdDev)-expectedStdDev) > 1 {
+			t.Fatalf("Incorrect statistics at index %d.\n  Expected: %f, %f\n  Actual: %d, %d", i, expectedMean, expectedStdDev, mean, stdDev)
+		}
+	}
+
+	// A constant window has no spread, exactly.
+	constant, err := peakdetect.NewFixedSlidingStats([]int64{-7, -7, -7})
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to create fixed sliding stats.", err)
+	}
+	mean, stdDev := constant.Next(-7)
+	if mean != -7 || stdDev != 0 || constant.Variance() != 0 {
+		t.Fatalf("Incorrect statistics for a constant window.\n  Expected: %d, %d\n  Actual: %d, %d", -7, 0, mean, stdDev)
+	}
+	mean, stdDev = constant.Next(2)
+	if mean != -4 || stdDev != 4 || constant.Variance() != 18 {
+		t.Fatalf("Incorrect statistics.\n  Expected: %d, %d, %d\n  Actual: %d, %d, %d", -4, 4, 18, mean, stdDev, constant.Variance())
+	}
+}
+
+// meanStdDev computes the mean and population standard deviation with two passes.
+func meanStdDev(values []float64) (mean, stdDev float64) {
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	for _, v := range values {
+		stdDev += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(stdDev / float64(len(values)))
+}
+
+func BenchmarkSlidingStats_Next(b *testing.B) {
+	stats, err := peakdetect.NewSlidingStats(exampleInputs[:exampleLag])
+	if err != nil {
+		b.Fatalf(logFmt, "Failed to create sliding stats.", err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		stats.Next(exampleInputs[i%len(exampleInputs)])
+	}
+}
+
+func BenchmarkFixedSlidingStats_Next(b *testing.B) {
+	values := make([]int64, len(exampleInputs))
+	for i, v := range exampleInputs {
+		values[i] = int64(v * 1000)
+	}
+	stats, err := peakdetect.NewFixedSlidingStats(values[:exampleLag])
+	if err != nil {
+		b.Fatalf(logFmt, "Failed to create fixed sliding stats.", err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		stats.Next(values[i%len(values)])
+	}
+}
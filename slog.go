@@ -0,0 +1,86 @@
+package peakdetect
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"sort"
+)
+
+// SeverityTier maps signals whose z-score magnitude is at least MinZScore to a slog level.
+type SeverityTier struct {
+	Level     slog.Level
+	MinZScore float64
+}
+
+// SlogHook logs signals with structured attributes at a level determined by their severity, so log based alerting
+// pipelines work without extra code.
+type SlogHook struct {
+	logger    *slog.Logger
+	seriesKey string
+	tiers     []SeverityTier
+}
+
+// NewSlogHook creates a SlogHook that logs to the logger. The seriesKey is attached to every record to identify the
+// series. The tiers do not need to be sorted. A signal is logged at the level of the tier with the greatest MinZScore
+// that its z-score magnitude reaches, or slog.LevelInfo if it reaches none.
+func NewSlogHook(logger *slog.Logger, seriesKey string, tiers []SeverityTier) *SlogHook {
+	sorted := append([]SeverityTier(nil), tiers...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].MinZScore > sorted[j].MinZScore
+	})
+	return &SlogHook{
+		logger:    logger,
+		seriesKey: seriesKey,
+		tiers:     sorted,
+	}
+}
+
+// Level returns the slog level for a signal with the given z-score.
+func (h *SlogHook) Level(zScore float64) slog.Level {
+	magnitude := math.Abs(zScore)
+	for _, tier := range h.tiers {
+		if magnitude >= tier.MinZScore {
+			return tier.Level
+		}
+	}
+	return slog.LevelInfo
+}
+
+// Log logs the explained value if it produced a signal. Neutral values are not logged. It is meant to be called with
+// the result of PeakDetector.Explain after Next. The labels of the PeakDetector are logged as a group named "labels".
+func (h *SlogHook) Log(ctx context.Context, explanation Explanation) {
+	if explanation.Signal == SignalNeutral {
+		return
+	}
+	direction := "positive"
+	if explanation.Signal == SignalNegative {
+		direction = "negative"
+	}
+	attrs := []slog.Attr{
+		slog.String("series", h.seriesKey),
+		slog.String("direction", direction),
+		slog.Float64("value", explanation.Value),
+		slog.Float64("z_score", explanation.ZScore),
+		slog.Float64("mean", explanation.Mean),
+		slog.Float64("std_dev", explanation.StdDev),
+	}
+	if len(explanation.Labels) != 0 {
+		labels := make([]interface{}, 0, len(explanation.Labels))
+		for _, key := range sortedKeys(explanation.Labels) {
+			labels = append(labels, slog.String(key, explanation.Labels[key]))
+		}
+		attrs = append(attrs, slog.Group("labels", labels...))
+	}
+	h.logger.LogAttrs(ctx, h.Level(explanation.ZScore), "peak detected", attrs...)
+}
+
+// sortedKeys returns the keys of the labels in ascending order.
+func sortedKeys(labels map[string]string) []string {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
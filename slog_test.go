@@ -0,0 +1,58 @@
+package peakdetect_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/MicahParks/peakdetect"
+)
+
+func TestSlogHook_Log(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(buf, nil))
+	hook := peakdetect.NewSlogHook(logger, "queue-depth", []peakdetect.SeverityTier{
+		{Level: slog.LevelError, MinZScore: 100},
+		{Level: slog.LevelWarn, MinZScore: 10},
+	})
+
+	detector := peakdetect.NewPeakDetector()
+	detector.SetLabels(map[string]string{"host": "a"})
+	err := detector.Initialize(exampleInfluence, exampleThreshold, exampleInputs[0:exampleLag])
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+
+	var signals int
+	for _, v := range exampleInputs[exampleLag:] {
+		if detector.Next(v) != peakdetect.SignalNeutral {
+			signals++
+		}
+		hook.Log(context.Background(), detector.Explain())
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != signals {
+		t.Fatalf("Unexpected number of log records.\n  Expected: %d\n  Actual: %d", signals, len(lines))
+	}
+
+	var record map[string]interface{}
+	err = json.Unmarshal([]byte(lines[0]), &record)
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to parse log record.", err)
+	}
+	labels, _ := record["labels"].(map[string]interface{})
+	if record["series"] != "queue-depth" || record["direction"] != "positive" || labels["host"] != "a" {
+		t.Fatalf("Log record did not have expected attributes.\n  Actual: %s", lines[0])
+	}
+
+	if level := hook.Level(-150); level != slog.LevelError {
+		t.Fatalf("Unexpected level.\n  Expected: %s\n  Actual: %s", slog.LevelError, level)
+	}
+	if level := hook.Level(5); level != slog.LevelInfo {
+		t.Fatalf("Unexpected level.\n  Expected: %s\n  Actual: %s", slog.LevelInfo, level)
+	}
+}
@@ -0,0 +1,67 @@
+package peakdetect
+
+import (
+	"sort"
+)
+
+// sortedWindow is a sliding window that is kept in a sorted slice alongside a ring buffer of the values in chronological
+// order, so order statistics such as the median and percentiles can be read in O(1). Each update is O(n) in the worst
+// case to keep the window sorted, with O(log n) comparisons.
+type sortedWindow struct {
+	cache  []float64
+	index  int
+	sorted []float64
+}
+
+func (s *sortedWindow) initialize(initialValues []float64) {
+	s.cache = append(make([]float64, 0, len(initialValues)), initialValues...)
+	s.index = 0
+	s.sorted = append(make([]float64, 0, len(initialValues)), initialValues...)
+	sort.Float64s(s.sorted)
+}
+
+// next adds the value to the window, removing the oldest.
+func (s *sortedWindow) next(value float64) {
+	outOfWindow := s.cache[s.index]
+	s.cache[s.index] = value
+	s.index++
+	if s.index == len(s.cache) {
+		s.index = 0
+	}
+
+	i := sort.SearchFloat64s(s.sorted, outOfWindow)
+	copy(s.sorted[i:], s.sorted[i+1:])
+	s.sorted = s.sorted[:len(s.sorted)-1]
+
+	i = sort.SearchFloat64s(s.sorted, value)
+	s.sorted = append(s.sorted, 0)
+	copy(s.sorted[i+1:], s.sorted[i:])
+	s.sorted[i] = value
+}
+
+// quantile returns the q-quantile of the window, for q in the range [0, 1], linearly interpolating between the closest
+// ranks.
+func (s *sortedWindow) quantile(q float64) float64 {
+	position := q * float64(len(s.sorted)-1)
+	i := int(position)
+	if i >= len(s.sorted)-1 {
+		return s.sorted[len(s.sorted)-1]
+	}
+	fraction := position - float64(i)
+	return s.sorted[i] + fraction*(s.sorted[i+1]-s.sorted[i])
+}
+
+func (s *sortedWindow) clone() sortedWindow {
+	return sortedWindow{
+		cache:  append([]float64(nil), s.cache...),
+		index:  s.index,
+		sorted: append(make([]float64, 0, cap(s.sorted)), s.sorted...),
+	}
+}
+
+// window returns a copy of the values in the window in chronological order.
+func (s *sortedWindow) window() []float64 {
+	window := make([]float64, 0, len(s.cache))
+	window = append(window, s.cache[s.index:]...)
+	return append(window, s.cache[:s.index]...)
+}
@@ -0,0 +1,194 @@
+package peakdetect
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// stateVersion is the version of the serialized state of a PeakDetector. It is incremented whenever the meaning of the
+// state changes.
+const stateVersion = 1
+
+// ErrInvalidState indicates that the serialized state of a detector provided is not valid.
+var ErrInvalidState = errors.New("the serialized detector state provided is invalid")
+
+// peakDetectorState is the serialized state of a peakDetector. The moving minimum, maximum, and moments are rebuilt from
+// the window rather than stored.
+type peakDetectorState struct {
+	Version         uint8              `json:"version"`
+	Active          Signal             `json:"active,omitempty"`
+	Config          Config             `json:"config"`
+	Count           uint64             `json:"count"`
+	Differences     []float64          `json:"differences,omitempty"`
+	Index           uint               `json:"index"`
+	Input           float64            `json:"input"`
+	InitialOutliers []int              `json:"initialOutliers,omitempty"`
+	Labels          map[string]string  `json:"labels,omitempty"`
+	Mean            float64            `json:"mean"`
+	Persistence     []persistenceEntry `json:"persistence,omitempty"`
+	Refractory      uint               `json:"refractory,omitempty"`
+	Robust          bool               `json:"robust,omitempty"`
+	StdDev          float64            `json:"stdDev"`
+	Value           float64            `json:"value"`
+	Variance        float64            `json:"variance"`
+	Window          []float64          `json:"window"`
+}
+
+func (p *peakDetector) MarshalBinary() ([]byte, error) {
+	state, err := p.state()
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	err = gob.NewEncoder(&buf).Encode(state)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode detector state: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (p *peakDetector) UnmarshalBinary(data []byte) error {
+	var state peakDetectorState
+	err := gob.NewDecoder(bytes.NewReader(data)).Decode(&state)
+	if err != nil {
+		return fmt.Errorf("failed to decode detector state: %v: %w", err, ErrInvalidState)
+	}
+	return p.restore(state)
+}
+
+func (p *peakDetector) MarshalJSON() ([]byte, error) {
+	state, err := p.state()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(state)
+}
+
+func (p *peakDetector) UnmarshalJSON(data []byte) error {
+	var state peakDetectorState
+	err := json.Unmarshal(data, &state)
+	if err != nil {
+		return fmt.Errorf("failed to decode detector state: %v: %w", err, ErrInvalidState)
+	}
+	return p.restore(state)
+}
+
+func (p *peakDetector) state() (peakDetectorState, error) {
+	if _, ok := p.baseline.(*customBaseline); ok {
+		return peakDetectorState{}, fmt.Errorf("a detector with a custom baseline cannot be encoded: %w", ErrInvalidConfig)
+	}
+	state := peakDetectorState{
+		Version:         stateVersion,
+		Active:          p.active,
+		Config:          p.config,
+		Count:           p.count,
+		Index:           p.index,
+		Input:           p.prevInput,
+		InitialOutliers: p.initialOutliers,
+		Labels:          p.labels,
+		Mean:            p.prevMean,
+		Persistence:     p.persistence,
+		Refractory:      p.refractory,
+		StdDev:          p.prevStdDev,
+		Value:           p.prevValue,
+		Window:          p.Window(),
+	}
+	if p.differencer != nil {
+		state.Differences = append([]float64(nil), p.differencer.prev...)
+	}
+	switch b := p.baseline.(type) {
+	case *SlidingStats:
+		state.Mean = b.prevMean
+		state.Variance = b.prevVariance
+	case *movingMedianMAD:
+		state.Robust = true
+	}
+	return state, nil
+}
+
+// restore replaces the state of the peakDetector. The Histogram is not part of the state and is kept.
+func (p *peakDetector) restore(state peakDetectorState) error {
+	if state.Version != stateVersion {
+		return fmt.Errorf("unsupported detector state version %d: %w", state.Version, ErrInvalidState)
+	}
+	err := state.Config.Validate()
+	if err != nil {
+		return fmt.Errorf("invalid detector config: %v: %w", err, ErrInvalidState)
+	}
+	lag := uint(len(state.Window))
+	if lag == 0 || state.Config.Lag != lag || state.Index >= lag {
+		return fmt.Errorf("the window of %d values does not match the lag %d and index %d: %w", lag, state.Config.Lag, state.Index, ErrInvalidState)
+	}
+
+	for i, v := range state.Window {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("the window value %f at %d is not finite: %w", v, i, ErrInvalidState)
+		}
+	}
+	if math.IsNaN(state.Mean) || math.IsInf(state.Mean, 0) || math.IsNaN(state.Variance) || math.IsInf(state.Variance, 0) {
+		return fmt.Errorf("the mean %f and variance %f must be finite: %w", state.Mean, state.Variance, ErrInvalidState)
+	}
+
+	if uint(len(state.Differences)) != state.Config.DerivativeOrder {
+		return fmt.Errorf("%d differences do not match the derivative order %d: %w", len(state.Differences), state.Config.DerivativeOrder, ErrInvalidState)
+	}
+	if window := state.Config.persistenceWindow(); uint(len(state.Persistence)) > window {
+		return fmt.Errorf("%d persistence entries are more than the persistence window %d: %w", len(state.Persistence), window, ErrInvalidState)
+	}
+
+	p.applyPendingPreprocessor()
+	p.active = state.Active
+	p.config = state.Config
+	p.count = state.Count
+	p.differencer = nil
+	if len(state.Differences) > 0 {
+		p.differencer = &differencer{
+			prev: state.Differences,
+			seen: len(state.Differences),
+		}
+	}
+	p.index = state.Index
+	p.prevInput = state.Input
+	p.initialOutliers = state.InitialOutliers
+	p.labels = state.Labels
+	p.last = lastValue{}
+	p.prevMean = state.Mean
+	p.persistence = state.Persistence
+	p.refractory = state.Refractory
+	p.prevStdDev = state.StdDev
+	p.prevValue = state.Value
+	if state.Robust {
+		// The median and median absolute deviation are exact, so they are recomputed from the window.
+		b := &movingMedianMAD{}
+		b.initialize(state.Window)
+		p.baseline = b
+	} else {
+		p.baseline = &SlidingStats{
+			cache:        state.Window,
+			cacheLen:     float64(lag),
+			cacheLenU:    lag,
+			prevMean:     state.Mean,
+			prevVariance: state.Variance,
+		}
+	}
+	p.setResyncInterval()
+	p.setQuantiles()
+
+	p.movingMinMax = newMovingMinMax(lag)
+	p.moments = nil
+	if p.config.TrackMoments {
+		p.moments, _ = NewMovingMoments(lag, p.config.SkewnessBound, p.config.KurtosisBound)
+	}
+	for _, v := range state.Window {
+		p.movingMinMax.Next(v)
+		if p.moments != nil {
+			p.moments.Next(v)
+		}
+	}
+
+	return nil
+}
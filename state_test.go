@@ -0,0 +1,133 @@
+package peakdetect_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/MicahParks/peakdetect"
+)
+
+func TestPeakDetector_MarshalBinary(t *testing.T) {
+	testCases := map[string]struct {
+		marshal   func(detector peakdetect.PeakDetector) ([]byte, error)
+		unmarshal func(detector peakdetect.PeakDetector, data []byte) error
+	}{
+		"Binary": {
+			marshal: func(detector peakdetect.PeakDetector) ([]byte, error) {
+				return detector.MarshalBinary()
+			},
+			unmarshal: func(detector peakdetect.PeakDetector, data []byte) error {
+				return detector.UnmarshalBinary(data)
+			},
+		},
+		"JSON": {
+			marshal: func(detector peakdetect.PeakDetector) ([]byte, error) {
+				return json.Marshal(detector)
+			},
+			unmarshal: func(detector peakdetect.PeakDetector, data []byte) error {
+				return json.Unmarshal(data, detector)
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			const split = exampleLag + 30
+
+			detector := peakdetect.NewPeakDetector()
+			detector.SetLabels(map[string]string{"series": "state"})
+			err := detector.InitializeWithConfig(peakdetect.Config{
+				Influence:    exampleInfluence,
+				Threshold:    exampleThreshold,
+				TrackMoments: true,
+			}, exampleInputs[:exampleLag])
+			if err != nil {
+				t.Fatalf(logFmt, "Error during initilization.", err)
+			}
+			detector.NextBatch(exampleInputs[exampleLag:split])
+
+			data, err := tc.marshal(detector)
+			if err != nil {
+				t.Fatalf(logFmt, "Failed to marshal detector state.", err)
+			}
+			restored := peakdetect.NewPeakDetector()
+			err = tc.unmarshal(restored, data)
+			if err != nil {
+				t.Fatalf(logFmt, "Failed to unmarshal detector state.", err)
+			}
+
+			if !reflect.DeepEqual(detector.Window(), restored.Window()) {
+				t.Fatalf("Restored window does not match.\n  Expected: %v\n  Actual: %v", detector.Window(), restored.Window())
+			}
+			// The moments are rebuilt from the window, so they may differ by rounding error.
+			expectedSummary, actualSummary := detector.Summary(), restored.Summary()
+			if math.Abs(expectedSummary.Moments.Skewness-actualSummary.Moments.Skewness) > 1e-9 {
+				t.Fatalf("Restored moments do not match.\n  Expected: %+v\n  Actual: %+v", *expectedSummary.Moments, *actualSummary.Moments)
+			}
+			expectedSummary.Moments, actualSummary.Moments = nil, nil
+			if !reflect.DeepEqual(expectedSummary, actualSummary) {
+				t.Fatalf("Restored summary does not match.\n  Expected: %+v\n  Actual: %+v", expectedSummary, actualSummary)
+			}
+			if restored.Labels()["series"] != "state" {
+				t.Fatalf("Restored labels do not match.\n  Actual: %v", restored.Labels())
+			}
+
+			expected := detector.NextBatch(exampleInputs[split:])
+			actual := restored.NextBatch(exampleInputs[split:])
+			if !reflect.DeepEqual(expected, actual) {
+				t.Fatalf("Restored detector signals do not match.\n  Expected: %v\n  Actual: %v", expected, actual)
+			}
+			for i, signal := range actual {
+				if signal != exampleOutputs[split+i] {
+					t.Fatalf("Incorrect signal at index %d.\n  Expected: %d\n  Actual: %d", split+i, exampleOutputs[split+i], signal)
+				}
+			}
+		})
+	}
+}
+
+func TestPeakDetector_UnmarshalBinaryInvalid(t *testing.T) {
+	detector := peakdetect.NewPeakDetector()
+	for _, data := range [][]byte{nil, []byte("not a detector")} {
+		err := detector.UnmarshalBinary(data)
+		if !errors.Is(err, peakdetect.ErrInvalidState) {
+			t.Fatalf("Invalid state did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidState, err)
+		}
+	}
+
+	err := detector.UnmarshalJSON([]byte(`{"version":1,"config":{"Lag":3,"Threshold":3},"window":[1,2]}`))
+	if !errors.Is(err, peakdetect.ErrInvalidState) {
+		t.Fatalf("Mismatched lag did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidState, err)
+	}
+
+	err = detector.UnmarshalJSON([]byte(`{"version":1,"config":{"Influence":2,"Lag":2,"Threshold":3},"window":[1,2]}`))
+	if !errors.Is(err, peakdetect.ErrInvalidState) {
+		t.Fatalf("Invalid config did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidState, err)
+	}
+
+	// JSON can not encode non-finite values, so the state is encoded with gob, which matches fields by name.
+	for _, window := range [][]float64{{1, math.NaN()}, {math.Inf(1), 2}} {
+		var buf bytes.Buffer
+		err = gob.NewEncoder(&buf).Encode(struct {
+			Version uint8
+			Config  peakdetect.Config
+			Window  []float64
+		}{
+			Version: 1,
+			Config:  peakdetect.Config{Lag: 2, Threshold: 3},
+			Window:  window,
+		})
+		if err != nil {
+			t.Fatalf(logFmt, "Failed to encode detector state.", err)
+		}
+		err = detector.UnmarshalBinary(buf.Bytes())
+		if !errors.Is(err, peakdetect.ErrInvalidState) {
+			t.Fatalf("Non-finite window values did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidState, err)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package peakdetect
+
+import (
+	"fmt"
+	"math"
+)
+
+func (p *peakDetector) InitializeFromStats(mean, stdDev float64, lag uint, cfg Config) error {
+	if lag == 0 {
+		return fmt.Errorf("the lag must be greater than zero: %w", ErrInvalidInitialValues)
+	}
+	if math.IsNaN(mean) || math.IsInf(mean, 0) || !(stdDev >= 0) || math.IsInf(stdDev, 1) {
+		return fmt.Errorf("the mean %f must be finite and the standard deviation %f must be finite and not negative: %w", mean, stdDev, ErrInvalidInitialValues)
+	}
+	if lag == 1 && stdDev != 0 {
+		return fmt.Errorf("a single value cannot have the standard deviation %f: %w", stdDev, ErrInvalidInitialValues)
+	}
+	if cfg.DerivativeOrder != 0 {
+		return fmt.Errorf("the derivative order %d must be zero when initializing from statistics: %w", cfg.DerivativeOrder, ErrInvalidConfig)
+	}
+
+	// Alternating values on either side of the mean have a population standard deviation equal to their distance from
+	// it, and a median absolute deviation equal to it too. With an odd lag, one value is the mean itself, which the
+	// distance is scaled up to compensate for.
+	spread := stdDev
+	p.useBaseline(cfg.Baseline)
+	_, robust := p.baseline.(*movingMedianMAD)
+	if robust {
+		spread /= madScale
+	} else if lag%2 == 1 && lag > 1 {
+		spread *= math.Sqrt(float64(lag) / float64(lag-1))
+	}
+	window := make([]float64, lag)
+	for i := range window {
+		switch {
+		case i == len(window)-1 && lag%2 == 1:
+			window[i] = mean
+		case i%2 == 0:
+			window[i] = mean + spread
+		default:
+			window[i] = mean - spread
+		}
+	}
+
+	p.applyPendingPreprocessor()
+	preprocessor := p.preprocessor
+	p.preprocessor = nil
+	err := p.InitializeWithConfig(cfg, window)
+	p.preprocessor = preprocessor
+	if err != nil {
+		return err
+	}
+	if preprocessor != nil {
+		preprocessor.Reset()
+	}
+	p.prevInput = mean
+	p.prevValue = mean
+	return nil
+}
+
+func (p *peakDetector) Stats() (mean, stdDev float64, lag uint) {
+	return p.prevMean, p.prevStdDev, p.config.Lag
+}
@@ -0,0 +1,81 @@
+package peakdetect_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/MicahParks/peakdetect"
+)
+
+func TestPeakDetector_InitializeFromStats(t *testing.T) {
+	for name, newDetector := range map[string]func() peakdetect.PeakDetector{
+		"MeanStdDev": peakdetect.NewPeakDetector,
+		"Robust":     peakdetect.NewRobustPeakDetector,
+	} {
+		t.Run(name, func(t *testing.T) {
+			for _, lag := range []uint{1, 2, 7, exampleLag} {
+				stdDev := 0.25
+				if lag == 1 {
+					stdDev = 0
+				}
+				detector := newDetector()
+				err := detector.InitializeFromStats(10, stdDev, lag, peakdetect.Config{Threshold: exampleThreshold})
+				if err != nil {
+					t.Fatalf(logFmt, "Failed to initialize from stats.", err)
+				}
+
+				mean, actualStdDev, actualLag := detector.Stats()
+				if math.Abs(mean-10) > 1e-9 || math.Abs(actualStdDev-stdDev) > 1e-9 || actualLag != lag {
+					t.Fatalf("The stats did not match for a lag of %d.\n  Expected: %f, %f, %d\n  Actual: %f, %f, %d", lag, 10.0, stdDev, lag, mean, actualStdDev, actualLag)
+				}
+			}
+		})
+	}
+}
+
+func TestPeakDetector_Stats(t *testing.T) {
+	detector := peakdetect.NewPeakDetector()
+	err := detector.Initialize(exampleInfluence, exampleThreshold, exampleInputs[:exampleLag])
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to initialize peak detector.", err)
+	}
+	mean, stdDev, lag := detector.Stats()
+
+	warm := peakdetect.NewPeakDetector()
+	err = warm.InitializeFromStats(mean, stdDev, lag, detector.Config())
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to initialize from stats.", err)
+	}
+
+	// The next value is compared to the same statistics.
+	expected := detector.NextDetailed(exampleInputs[exampleLag])
+	actual := warm.NextDetailed(exampleInputs[exampleLag])
+	if expected.Signal != actual.Signal || math.Abs(expected.ZScore-actual.ZScore) > 1e-9 {
+		t.Fatalf("The warm started detector did not match.\n  Expected: %+v\n  Actual: %+v", expected, actual)
+	}
+}
+
+func TestPeakDetector_InitializeFromStatsInvalid(t *testing.T) {
+	for _, tc := range []struct {
+		mean   float64
+		stdDev float64
+		lag    uint
+		cfg    peakdetect.Config
+		err    error
+	}{
+		{mean: 1, stdDev: 1, lag: 0, err: peakdetect.ErrInvalidInitialValues},
+		{mean: math.NaN(), stdDev: 1, lag: 5, err: peakdetect.ErrInvalidInitialValues},
+		{mean: 1, stdDev: -1, lag: 5, err: peakdetect.ErrInvalidInitialValues},
+		{mean: 1, stdDev: math.Inf(1), lag: 5, err: peakdetect.ErrInvalidInitialValues},
+		{mean: 1, stdDev: 1, lag: 1, err: peakdetect.ErrInvalidInitialValues},
+		{mean: 1, stdDev: 1, lag: 5, cfg: peakdetect.Config{DerivativeOrder: 1}, err: peakdetect.ErrInvalidConfig},
+		{mean: 1, stdDev: 1, lag: 5, cfg: peakdetect.Config{Lag: 4}, err: peakdetect.ErrInvalidInitialValues},
+		{mean: 1, stdDev: 1, lag: 5, cfg: peakdetect.Config{Influence: 2}, err: peakdetect.ErrInvalidConfig},
+	} {
+		err := peakdetect.NewPeakDetector().InitializeFromStats(tc.mean, tc.stdDev, tc.lag, tc.cfg)
+		if !errors.Is(err, tc.err) {
+			t.Fatalf("Incorrect error for %+v.\n  Expected: %v\n  Actual: %v", tc, tc.err, err)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package peakdetect
+
+import (
+	"fmt"
+	"math"
+)
+
+// StepEvent describes a step change in the level of the data.
+type StepEvent struct {
+	// Index is the index of the first value after the step. Indices count every value given to the StepDetector,
+	// starting at zero.
+	Index uint64
+	// PreLevel is the mean of the window before the step.
+	PreLevel float64
+	// PostLevel is the mean of the window after the step.
+	PostLevel float64
+	// Signal is SignalPositive for a step up and SignalNegative for a step down.
+	Signal Signal
+	// T is Welch's t statistic comparing the windows before and after the step.
+	T float64
+}
+
+// StepDetector detects step changes in realtime timeseries data. Unlike a PeakDetector, which signals for every value
+// that deviates from the moving mean, a StepDetector emits a single event for a sustained change in level.
+//
+// Two adjacent windows of equal size slide over the data. The window before the boundary is compared with the window
+// after the boundary using Welch's t statistic. When the magnitude of the statistic exceeds the threshold, the
+// detector follows it until it falls back below the threshold, then emits one event for the boundary with the largest
+// magnitude.
+type StepDetector interface {
+	// Initialize initializes the StepDetector with its configuration. window is the number of values in each of the two
+	// windows, so no events are emitted for the first 2*window values. window must be at least two, as the sample
+	// variance of each window is required. threshold is the magnitude Welch's t statistic must exceed for a step to be
+	// detected.
+	Initialize(window uint, threshold float64) error
+	// Next processes the next value. If it completes the detection of a step, the event is returned and ok is true.
+	Next(value float64) (event StepEvent, ok bool)
+	// NextBatch processes the next values and returns the events for any steps they complete.
+	NextBatch(values []float64) []StepEvent
+	// MemoryFootprint returns the approximate number of bytes used by the StepDetector, including its windows.
+	MemoryFootprint() uintptr
+}
+
+type stepDetector struct {
+	best      StepEvent
+	count     uint64
+	inStep    bool
+	post      *SlidingStats
+	pre       *SlidingStats
+	threshold float64
+	warmup    []float64
+	window    uint
+}
+
+// NewStepDetector creates a new StepDetector. It must be initialized before use.
+func NewStepDetector() StepDetector {
+	return &stepDetector{}
+}
+
+func (s *stepDetector) Initialize(window uint, threshold float64) error {
+	if window < 2 {
+		return fmt.Errorf("the window for a step detector must be at least two: %w", ErrInvalidWindow)
+	}
+	*s = stepDetector{
+		post:      &SlidingStats{},
+		pre:       &SlidingStats{},
+		threshold: threshold,
+		warmup:    make([]float64, 0, 2*window),
+		window:    window,
+	}
+	return nil
+}
+
+func (s *stepDetector) Next(value float64) (event StepEvent, ok bool) {
+	s.count++
+	if s.warmup != nil {
+		s.warmup = append(s.warmup, value)
+		if uint(len(s.warmup)) < 2*s.window {
+			return StepEvent{}, false
+		}
+		s.pre.initialize(s.warmup[:s.window])
+		s.post.initialize(s.warmup[s.window:])
+		s.warmup = nil
+	} else {
+		s.pre.next(s.post.cache[s.post.index])
+		s.post.next(value)
+	}
+
+	t := s.welch()
+	if math.Abs(t) > s.threshold {
+		signal := SignalPositive
+		if t < 0 {
+			signal = SignalNegative
+		}
+		if s.inStep && signal != s.best.Signal {
+			event, ok = s.best, true
+			s.inStep = false
+		}
+		if !s.inStep || math.Abs(t) > math.Abs(s.best.T) {
+			s.best = StepEvent{
+				Index:     s.count - uint64(s.window),
+				PreLevel:  s.pre.prevMean,
+				PostLevel: s.post.prevMean,
+				Signal:    signal,
+				T:         t,
+			}
+		}
+		s.inStep = true
+		return event, ok
+	}
+
+	if s.inStep {
+		s.inStep = false
+		return s.best, true
+	}
+	return StepEvent{}, false
+}
+
+func (s *stepDetector) NextBatch(values []float64) []StepEvent {
+	var events []StepEvent
+	for _, v := range values {
+		if event, ok := s.Next(v); ok {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// welch computes Welch's t statistic for the difference between the mean of the window after the boundary and the mean
+// of the window before the boundary.
+func (s *stepDetector) welch() float64 {
+	n := float64(s.window)
+	besselCorrection := n / (n - 1)
+	preVariance := s.pre.prevVariance * besselCorrection
+	postVariance := s.post.prevVariance * besselCorrection
+
+	diff := s.post.prevMean - s.pre.prevMean
+	stdErr := math.Sqrt(math.Max(preVariance/n+postVariance/n, 0))
+	return zScore(diff, stdErr)
+}
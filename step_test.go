@@ -0,0 +1,53 @@
+package peakdetect_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/MicahParks/peakdetect"
+)
+
+func TestStepDetector_Initialize(t *testing.T) {
+	detector := peakdetect.NewStepDetector()
+	err := detector.Initialize(1, 5)
+	if !errors.Is(err, peakdetect.ErrInvalidWindow) {
+		t.Fatalf("Invalid initilization did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidWindow, err)
+	}
+}
+
+func TestStepDetector_NextBatch(t *testing.T) {
+	noise := []float64{0.1, -0.1, 0, 0.05, -0.05}
+	var data []float64
+	for i := 0; i < 30; i++ {
+		data = append(data, 1+noise[i%len(noise)])
+	}
+	for i := 0; i < 30; i++ {
+		data = append(data, 5+noise[i%len(noise)])
+	}
+	for i := 0; i < 30; i++ {
+		data = append(data, 2+noise[i%len(noise)])
+	}
+
+	detector := peakdetect.NewStepDetector()
+	err := detector.Initialize(5, 10)
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+
+	events := detector.NextBatch(data)
+	if len(events) != 2 {
+		t.Fatalf("Unexpected number of step events.\n  Expected: %d\n  Actual: %d", 2, len(events))
+	}
+
+	expected := []peakdetect.StepEvent{
+		{Index: 30, PreLevel: 1, PostLevel: 5, Signal: peakdetect.SignalPositive},
+		{Index: 60, PreLevel: 5, PostLevel: 2, Signal: peakdetect.SignalNegative},
+	}
+	for i, event := range events {
+		e := expected[i]
+		if event.Index != e.Index || event.Signal != e.Signal || math.Abs(event.PreLevel-e.PreLevel) > 0.1 || math.Abs(event.PostLevel-e.PostLevel) > 0.1 {
+			t.Fatalf("Step event did not match.\n  Expected: %+v\n  Actual: %+v", e, event)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package peakdetect
+
+import (
+	"math"
+	"sort"
+)
+
+// WindowSummary is summary statistics of the values retained in the lag window of a PeakDetector.
+type WindowSummary struct {
+	// Histogram is a snapshot of the Histogram set on the PeakDetector, if any. Its window is independent of the lag.
+	Histogram *HistogramSnapshot
+
+	// Moments are the rolling moments of the lag window, including the skewness and kurtosis meta-signals. They are only
+	// present if Config.TrackMoments is set.
+	Moments *Moments
+
+	Max    float64
+	Mean   float64
+	Median float64
+	Min    float64
+	Range  float64
+	StdDev float64
+}
+
+// Summary returns summary statistics of the lag window. The minimum, maximum, mean, and standard deviation are
+// maintained incrementally. The median is computed from a sorted copy of the window. If the PeakDetector is not
+// initialized, there is no window, so only the Histogram is set.
+func (p *peakDetector) Summary() WindowSummary {
+	var histogram *HistogramSnapshot
+	if p.histogram != nil {
+		snapshot := p.histogram.Snapshot()
+		histogram = &snapshot
+	}
+	if p.config.Lag == 0 {
+		return WindowSummary{
+			Histogram: histogram,
+		}
+	}
+	min, max := p.movingMinMax.Min(), p.movingMinMax.Max()
+	var moments *Moments
+	if p.moments != nil {
+		m := p.moments.Moments()
+		moments = &m
+	}
+	window := p.baseline.window()
+	mean, stdDev := p.prevMean, p.prevStdDev
+	if _, ok := p.baseline.(*SlidingStats); !ok {
+		mean, stdDev = meanStdDev(window)
+	}
+	return WindowSummary{
+		Histogram: histogram,
+		Moments:   moments,
+		Max:       max,
+		Mean:      mean,
+		Median:    median(window),
+		Min:       min,
+		Range:     max - min,
+		StdDev:    stdDev,
+	}
+}
+
+// meanStdDev returns the mean and population standard deviation of the values.
+func meanStdDev(values []float64) (mean, stdDev float64) {
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	var variance float64
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(variance / float64(len(values)))
+}
+
+// median sorts the values in place and returns their median.
+func median(values []float64) float64 {
+	sort.Float64s(values)
+	middle := len(values) / 2
+	if len(values)%2 == 0 {
+		return (values[middle-1] + values[middle]) / 2
+	}
+	return values[middle]
+}
@@ -0,0 +1,42 @@
+package peakdetect_test
+
+import (
+	"testing"
+
+	"github.com/MicahParks/peakdetect"
+)
+
+func TestPeakDetector_Summary(t *testing.T) {
+	data := []float64{1, 2, 3, 4, 100, 6}
+	const lag = 3
+
+	detector := peakdetect.NewPeakDetector()
+	err := detector.Initialize(0.5, 3, data[:lag])
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+
+	summary := detector.Summary()
+	expected := peakdetect.WindowSummary{Max: 3, Mean: 2, Median: 2, Min: 1, Range: 2, StdDev: summary.StdDev}
+	if summary != expected {
+		t.Fatalf("Summary did not match after initialization.\n  Expected: %+v\n  Actual: %+v", expected, summary)
+	}
+
+	detector.NextBatch(data[lag:])
+	summary = detector.Summary()
+	expected = peakdetect.WindowSummary{Max: 52, Mean: summary.Mean, Median: 6, Min: 4, Range: 48, StdDev: summary.StdDev}
+	if summary != expected {
+		t.Fatalf("Summary did not match after processing values.\n  Expected: %+v\n  Actual: %+v", expected, summary)
+	}
+	if summary.Mean != 62.0/3 {
+		t.Fatalf("Summary mean did not match.\n  Expected: %f\n  Actual: %f", 62.0/3, summary.Mean)
+	}
+}
+
+func TestPeakDetector_SummaryUninitialized(t *testing.T) {
+	for _, detector := range []peakdetect.PeakDetector{peakdetect.NewPeakDetector(), peakdetect.NewRobustPeakDetector()} {
+		if summary := detector.Summary(); summary != (peakdetect.WindowSummary{}) {
+			t.Fatalf("Summary of an uninitialized detector should be the zero value.\n  Actual: %+v", summary)
+		}
+	}
+}
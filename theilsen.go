@@ -0,0 +1,122 @@
+package peakdetect
+
+import (
+	"fmt"
+	"math"
+)
+
+// TheilSen is a rolling Theil-Sen estimator of the trend slope of the most recent values. The slope is the median of the
+// slopes between every pair of values in the window, so it is robust to outliers. It signals drift when the magnitude
+// of the slope exceeds a bound.
+//
+// Slow monotonic drift, such as from a memory leak or a degrading sensor, never deviates far enough from the moving
+// mean to be a peak, but it does produce a consistent slope.
+//
+// Each update is O(n^2) for a window of n values, as every pairwise slope is considered.
+type TheilSen struct {
+	bound   float64
+	count   uint
+	index   uint
+	scratch []float64
+	values  []float64
+}
+
+// NewTheilSen creates a new TheilSen over the given window size, which must be at least two. A drift signal is produced
+// when the magnitude of the slope, in units per value, exceeds bound.
+func NewTheilSen(window uint, bound float64) (*TheilSen, error) {
+	if window < 2 {
+		return nil, fmt.Errorf("the window size for a Theil-Sen estimator must be at least two: %w", ErrInvalidWindow)
+	}
+	return &TheilSen{
+		bound:   bound,
+		scratch: make([]float64, 0, window*(window-1)/2),
+		values:  make([]float64, window),
+	}, nil
+}
+
+// Next processes the next value and returns the slope of the window along with its drift signal. The signal is
+// SignalPositive for upward drift and SignalNegative for downward drift. Until the window is full, the slope is zero
+// and the signal is neutral.
+func (t *TheilSen) Next(value float64) (slope float64, signal Signal) {
+	window := uint(len(t.values))
+	t.values[t.index] = value
+	t.index++
+	if t.index == window {
+		t.index = 0
+	}
+	if t.count < window {
+		t.count++
+		if t.count < window {
+			return 0, SignalNeutral
+		}
+	}
+
+	// The oldest value is at t.index.
+	t.scratch = t.scratch[:0]
+	for i := uint(0); i < window; i++ {
+		yi := t.values[(t.index+i)%window]
+		for j := i + 1; j < window; j++ {
+			yj := t.values[(t.index+j)%window]
+			t.scratch = append(t.scratch, (yj-yi)/float64(j-i))
+		}
+	}
+	slope = medianSelect(t.scratch)
+
+	switch {
+	case slope > t.bound:
+		signal = SignalPositive
+	case slope < -t.bound:
+		signal = SignalNegative
+	default:
+		signal = SignalNeutral
+	}
+	return slope, signal
+}
+
+// medianSelect returns the median of the values in O(n) expected time. The values are reordered.
+func medianSelect(values []float64) float64 {
+	n := len(values)
+	middle := n / 2
+	upper := selectKth(values, middle)
+	if n%2 == 1 {
+		return upper
+	}
+	// After selection, every value before the middle is less than or equal to the upper middle value.
+	lower := values[0]
+	for _, v := range values[1:middle] {
+		lower = math.Max(lower, v)
+	}
+	return (lower + upper) / 2
+}
+
+// selectKth partially sorts the values so that the k-th smallest value, counting from zero, is at index k, with smaller
+// or equal values before it. It returns that value.
+func selectKth(values []float64, k int) float64 {
+	left, right := 0, len(values)-1
+	for left < right {
+		pivot := values[left+(right-left)/2]
+		i, j := left, right
+		for i <= j {
+			for values[i] < pivot {
+				i++
+			}
+			for values[j] > pivot {
+				j--
+			}
+			if i <= j {
+				values[i], values[j] = values[j], values[i]
+				i++
+				j--
+			}
+		}
+		switch {
+		case k <= j:
+			right = j
+		case k >= i:
+			left = i
+		default:
+			return values[k]
+		}
+	}
+	return values[k]
+}
@@ -0,0 +1,54 @@
+package peakdetect_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/MicahParks/peakdetect"
+)
+
+func TestNewTheilSen(t *testing.T) {
+	_, err := peakdetect.NewTheilSen(1, 0.1)
+	if !errors.Is(err, peakdetect.ErrInvalidWindow) {
+		t.Fatalf("Invalid window did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidWindow, err)
+	}
+}
+
+func TestTheilSen_Next(t *testing.T) {
+	const window = 20
+
+	estimator, err := peakdetect.NewTheilSen(window, 0.05)
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to create Theil-Sen estimator.", err)
+	}
+
+	// A flat series with noise starts drifting upward by 0.1 per value at index 100. One large outlier inside the drift
+	// must not affect the slope.
+	noise := []float64{0.2, -0.1, 0.05, -0.2, 0.1}
+	for i := 0; i < 200; i++ {
+		value := 10 + noise[i%len(noise)]
+		if i >= 100 {
+			value += 0.1 * float64(i-100)
+		}
+		if i == 150 {
+			value += 1000
+		}
+
+		slope, signal := estimator.Next(value)
+		switch {
+		case i < window-1:
+			if slope != 0 || signal != peakdetect.SignalNeutral {
+				t.Fatalf("Estimator should be neutral before the window is full at index %d.", i)
+			}
+		case i < 100:
+			if signal != peakdetect.SignalNeutral {
+				t.Fatalf("Unexpected drift before the drift starts at index %d.\n  Slope: %f", i, slope)
+			}
+		case i >= 100+window:
+			if signal != peakdetect.SignalPositive || math.Abs(slope-0.1) > 0.02 {
+				t.Fatalf("Drift was not detected at index %d.\n  Slope: %f\n  Signal: %d", i, slope, signal)
+			}
+		}
+	}
+}
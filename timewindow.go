@@ -0,0 +1,124 @@
+package peakdetect
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// TimeWindowDetector is a PeakDetector for irregularly sampled data whose lag is a duration instead of a number of
+// values. The moving mean and standard deviation are computed over the values inside the time window that ends at each
+// new value, so the number of values in the window varies.
+//
+// The Influence and Threshold of the Config are used. No signals are produced until a full window has passed since the
+// first value.
+type TimeWindowDetector struct {
+	config    Config
+	head      int
+	m2        float64
+	mean      float64
+	prevValue float64
+	samples   []Sample
+	start     time.Time
+	window    time.Duration
+}
+
+// NewTimeWindowDetector creates a new TimeWindowDetector with the given window duration. The cfg must pass
+// Config.Validate and its Lag must be zero, as the window duration is used instead.
+func NewTimeWindowDetector(window time.Duration, cfg Config) (*TimeWindowDetector, error) {
+	if window <= 0 {
+		return nil, fmt.Errorf("the window duration must be positive: %w", ErrInvalidInterval)
+	}
+	if cfg.Lag != 0 {
+		return nil, fmt.Errorf("the lag must be zero, as the window duration is used instead: %w", ErrInvalidConfig)
+	}
+	err := cfg.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return &TimeWindowDetector{
+		config: cfg,
+		window: window,
+	}, nil
+}
+
+// NextAt processes the value at the given time and determines its signal. Values must be given in chronological order.
+func (d *TimeWindowDetector) NextAt(t time.Time, value float64) (Signal, error) {
+	if d.len() == 0 && d.start.IsZero() {
+		d.start = t
+	} else if last := d.samples[len(d.samples)-1].Time; t.Before(last) {
+		return SignalNeutral, fmt.Errorf("the time %s is before the previous time %s: %w", t, last, ErrOutOfOrder)
+	}
+
+	cutoff := t.Add(-d.window)
+	for d.len() > 0 && !d.samples[d.head].Time.After(cutoff) {
+		d.remove()
+	}
+
+	signal := SignalNeutral
+	if !t.Before(d.start.Add(d.window)) && d.len() > 0 {
+		stdDev := math.Sqrt(d.m2 / float64(d.len()))
+		if math.Abs(value-d.mean) > d.config.Threshold*stdDev {
+			if value > d.mean {
+				signal = SignalPositive
+			} else {
+				signal = SignalNegative
+			}
+			value = d.config.Influence*value + (1-d.config.Influence)*d.prevValue
+		}
+	}
+
+	d.add(Sample{
+		Time:  t,
+		Value: value,
+	})
+	d.prevValue = value
+
+	return signal, nil
+}
+
+// MeanStdDev returns the moving mean and population standard deviation of the values in the current window.
+func (d *TimeWindowDetector) MeanStdDev() (mean, stdDev float64) {
+	if d.len() == 0 {
+		return 0, 0
+	}
+	return d.mean, math.Sqrt(d.m2 / float64(d.len()))
+}
+
+// Len returns the number of values in the current window.
+func (d *TimeWindowDetector) Len() int {
+	return d.len()
+}
+
+func (d *TimeWindowDetector) len() int {
+	return len(d.samples) - d.head
+}
+
+// add adds the sample to the window using Welford's method.
+func (d *TimeWindowDetector) add(sample Sample) {
+	d.samples = append(d.samples, sample)
+	n := float64(d.len())
+	delta := sample.Value - d.mean
+	d.mean += delta / n
+	d.m2 += delta * (sample.Value - d.mean)
+}
+
+// remove removes the oldest sample from the window by reversing Welford's method.
+func (d *TimeWindowDetector) remove() {
+	oldest := d.samples[d.head].Value
+	d.head++
+	n := float64(d.len())
+	if n == 0 {
+		d.mean, d.m2 = 0, 0
+	} else {
+		delta := oldest - d.mean
+		d.mean -= delta / n
+		d.m2 = math.Max(d.m2-delta*(oldest-d.mean), 0)
+	}
+
+	// Reclaim the space of removed samples once they are the majority of the slice.
+	if d.head > len(d.samples)/2 {
+		d.samples = append(d.samples[:0], d.samples[d.head:]...)
+		d.head = 0
+	}
+}
@@ -0,0 +1,79 @@
+package peakdetect_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/MicahParks/peakdetect"
+)
+
+func TestNewTimeWindowDetector(t *testing.T) {
+	_, err := peakdetect.NewTimeWindowDetector(0, peakdetect.Config{})
+	if !errors.Is(err, peakdetect.ErrInvalidInterval) {
+		t.Fatalf("Invalid window did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidInterval, err)
+	}
+	_, err = peakdetect.NewTimeWindowDetector(time.Minute, peakdetect.Config{Lag: 5})
+	if !errors.Is(err, peakdetect.ErrInvalidConfig) {
+		t.Fatalf("Lag did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidConfig, err)
+	}
+}
+
+func TestTimeWindowDetector_NextAt(t *testing.T) {
+	const window = 30 * time.Second
+
+	detector, err := peakdetect.NewTimeWindowDetector(window, peakdetect.Config{
+		Influence: exampleInfluence,
+		Threshold: exampleThreshold,
+	})
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to create detector.", err)
+	}
+
+	// The example data at one second intervals produces the example signals.
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	var last time.Time
+	for i, v := range exampleInputs {
+		ts := start.Add(time.Duration(i) * time.Second)
+		signal, err := detector.NextAt(ts, v)
+		if err != nil {
+			t.Fatalf(logFmt, "Failed to process value.", err)
+		}
+		if i >= exampleLag && signal != exampleOutputs[i] {
+			t.Fatalf("Incorrect signal at index %d.\n  Expected: %d\n  Actual: %d", i, exampleOutputs[i], signal)
+		}
+		if i < exampleLag && signal != peakdetect.SignalNeutral {
+			t.Fatalf("Signal during the first window at index %d.", i)
+		}
+		last = ts
+	}
+	if detector.Len() != exampleLag {
+		t.Fatalf("Incorrect window length.\n  Expected: %d\n  Actual: %d", exampleLag, detector.Len())
+	}
+
+	// Irregular samples fill the window by time rather than count.
+	ts := last.Add(100 * time.Millisecond)
+	for j := 0; j < 100; j++ {
+		_, err = detector.NextAt(ts, 1)
+		if err != nil {
+			t.Fatalf(logFmt, "Failed to process value.", err)
+		}
+	}
+	if detector.Len() != exampleLag+100 {
+		t.Fatalf("Incorrect window length.\n  Expected: %d\n  Actual: %d", exampleLag+100, detector.Len())
+	}
+	_, err = detector.NextAt(ts.Add(window-time.Millisecond), 1)
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to process value.", err)
+	}
+	mean, stdDev := detector.MeanStdDev()
+	if detector.Len() != 101 || math.Abs(mean-1) > 1e-9 || stdDev > 1e-6 {
+		t.Fatalf("Expired values were not removed.\n  Length: %d\n  Mean: %f\n  StdDev: %f", detector.Len(), mean, stdDev)
+	}
+
+	_, err = detector.NextAt(start, 1)
+	if !errors.Is(err, peakdetect.ErrOutOfOrder) {
+		t.Fatalf("Out of order value did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrOutOfOrder, err)
+	}
+}
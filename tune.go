@@ -0,0 +1,98 @@
+package peakdetect
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	// tuneIterations is the number of bisection steps used to find a threshold for each lag and influence.
+	tuneIterations = 30
+	// tuneMaxThreshold is the largest threshold Tune considers.
+	tuneMaxThreshold = 50
+	// tuneMinLag is the smallest lag Tune considers.
+	tuneMinLag = 5
+)
+
+var (
+	// tuneInfluences are the influences Tune considers, in order of preference.
+	tuneInfluences = []float64{0, 0.25, 0.5}
+	// tuneLags are the lags Tune considers, in order of preference. Longer lags are more robust for stationary data.
+	tuneLags = []uint{100, 50, 30, 20, 10, tuneMinLag}
+)
+
+// Tune analyzes a historical sample and recommends a Config whose signaling rate on the sample is approximately the
+// target. The signaling rate is the fraction of values after the lag that are not neutral. For example, a target of
+// 0.01 means one in every hundred values signals.
+//
+// Every combination of a set of candidate lags and influences is run over the sample, with the threshold found by
+// bisection. The combination whose rate is closest to the target is recommended, preferring longer lags and smaller
+// influences on ties. Lags longer than half of the sample are not considered. The result is a data-driven starting
+// point, not a replacement for examining the signals on the data. Every value of the sample must be finite.
+func Tune(sample []float64, targetSignalRate float64) (Config, error) {
+	if !(targetSignalRate > 0 && targetSignalRate < 1) {
+		return Config{}, fmt.Errorf("the target signal rate %f must be in the range (0, 1): %w", targetSignalRate, ErrInvalidConfig)
+	}
+	if len(sample) < 2*tuneMinLag {
+		return Config{}, fmt.Errorf("the sample must have at least %d values to tune: %w", 2*tuneMinLag, ErrInvalidInitialValues)
+	}
+	for i, v := range sample {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return Config{}, fmt.Errorf("the sample value %f at index %d must be finite to tune: %w", v, i, ErrInvalidInitialValues)
+		}
+	}
+
+	var best Config
+	bestError := math.Inf(1)
+	for _, lag := range tuneLags {
+		if 2*int(lag) > len(sample) {
+			continue
+		}
+		for _, influence := range tuneInfluences {
+			cfg := Config{
+				Influence: influence,
+				Lag:       lag,
+			}
+			rate := tuneThreshold(sample, &cfg, targetSignalRate)
+			if e := math.Abs(rate - targetSignalRate); e < bestError {
+				best = cfg
+				bestError = e
+			}
+		}
+	}
+
+	return best, nil
+}
+
+// tuneThreshold sets the threshold of the cfg by bisection so its signaling rate on the sample is approximately the
+// target. The signaling rate for the threshold is returned.
+func tuneThreshold(sample []float64, cfg *Config, target float64) float64 {
+	low, high := 0.0, float64(tuneMaxThreshold)
+	rate := math.NaN()
+	for i := 0; i < tuneIterations; i++ {
+		cfg.Threshold = (low + high) / 2
+		rate = signalRate(sample, *cfg)
+		if rate > target {
+			low = cfg.Threshold
+		} else {
+			high = cfg.Threshold
+		}
+	}
+	return rate
+}
+
+// signalRate returns the fraction of the values after the lag that are not neutral.
+func signalRate(sample []float64, cfg Config) float64 {
+	detector := NewPeakDetector()
+	err := detector.InitializeWithConfig(cfg, sample[:cfg.Lag])
+	if err != nil {
+		return math.NaN()
+	}
+	var signals int
+	for _, v := range sample[cfg.Lag:] {
+		if detector.Next(v) != SignalNeutral {
+			signals++
+		}
+	}
+	return float64(signals) / float64(len(sample)-int(cfg.Lag))
+}
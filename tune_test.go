@@ -0,0 +1,63 @@
+package peakdetect_test
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/MicahParks/peakdetect"
+)
+
+func TestTune(t *testing.T) {
+	const target = 0.02
+
+	r := rand.New(rand.NewSource(1))
+	sample := make([]float64, 5000)
+	for i := range sample {
+		sample[i] = 100 + r.NormFloat64()
+		if r.Intn(100) == 0 {
+			sample[i] += 10
+		}
+	}
+
+	cfg, err := peakdetect.Tune(sample, target)
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to tune.", err)
+	}
+	if cfg.Lag == 0 || cfg.Threshold <= 0 {
+		t.Fatalf("Incomplete recommendation.\n  Actual: %+v", cfg)
+	}
+
+	detector, err := peakdetect.NewFromConfig(cfg, sample[:cfg.Lag])
+	if err != nil {
+		t.Fatalf(logFmt, "Recommended config could not be used.", err)
+	}
+	var signals int
+	for _, signal := range detector.NextBatch(sample[cfg.Lag:]) {
+		if signal != peakdetect.SignalNeutral {
+			signals++
+		}
+	}
+	rate := float64(signals) / float64(len(sample)-int(cfg.Lag))
+	if math.Abs(rate-target) > target/10 {
+		t.Fatalf("Recommended config did not achieve the target signal rate.\n  Expected: %f\n  Actual: %f", target, rate)
+	}
+}
+
+func TestTuneInvalid(t *testing.T) {
+	_, err := peakdetect.Tune(exampleInputs, 0)
+	if !errors.Is(err, peakdetect.ErrInvalidConfig) {
+		t.Fatalf("Invalid target did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidConfig, err)
+	}
+	_, err = peakdetect.Tune(exampleInputs[:3], 0.1)
+	if !errors.Is(err, peakdetect.ErrInvalidInitialValues) {
+		t.Fatalf("Short sample did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidInitialValues, err)
+	}
+	sample := append([]float64(nil), exampleInputs...)
+	sample[20] = math.NaN()
+	_, err = peakdetect.Tune(sample, 0.1)
+	if !errors.Is(err, peakdetect.ErrInvalidInitialValues) {
+		t.Fatalf("Non-finite sample did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidInitialValues, err)
+	}
+}
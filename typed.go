@@ -0,0 +1,69 @@
+package peakdetect
+
+// Number is a constraint for the numeric types a TypedPeakDetector accepts.
+type Number interface {
+	~float32 | ~float64 | ~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64
+}
+
+// TypedPeakDetector is a PeakDetector that accepts values of any Number type, such as float32 or int16 sensor samples,
+// so callers do not need to convert and copy every batch to float64 first. Batches are converted one value at a time
+// as they are processed, without a copy.
+//
+// The algorithm itself still operates on float64. The moving mean and standard deviation of integer data are not
+// integers, and signals store influence adjusted values in the lag window, so each value is converted as it is
+// processed.
+type TypedPeakDetector[T Number] struct {
+	detector *peakDetector
+}
+
+// NewTypedPeakDetector creates a new TypedPeakDetector. It must be initialized before use.
+func NewTypedPeakDetector[T Number]() *TypedPeakDetector[T] {
+	return &TypedPeakDetector[T]{
+		detector: NewPeakDetector().(*peakDetector),
+	}
+}
+
+// Initialize initializes the TypedPeakDetector. See PeakDetector.Initialize.
+func (p *TypedPeakDetector[T]) Initialize(influence, threshold float64, initialValues []T) error {
+	return p.detector.Initialize(influence, threshold, toFloat64s(initialValues))
+}
+
+// InitializeWithConfig initializes the TypedPeakDetector with a Config. See PeakDetector.InitializeWithConfig.
+func (p *TypedPeakDetector[T]) InitializeWithConfig(cfg Config, initialValues []T) error {
+	return p.detector.InitializeWithConfig(cfg, toFloat64s(initialValues))
+}
+
+// Next processes the next value and determines its signal.
+func (p *TypedPeakDetector[T]) Next(value T) Signal {
+	return p.detector.Next(float64(value))
+}
+
+// NextBatch processes the next values and determines their signals. Their signals will be returned in a slice equal to
+// the length of the input.
+func (p *TypedPeakDetector[T]) NextBatch(values []T) []Signal {
+	return p.NextBatchInto(make([]Signal, 0, len(values)), values)
+}
+
+// NextBatchInto processes the next values like NextBatch, but appends their signals to dst[:0] and returns the result.
+// Reusing dst across calls avoids allocation. See PeakDetector.NextBatchInto.
+func (p *TypedPeakDetector[T]) NextBatchInto(dst []Signal, values []T) []Signal {
+	dst = dst[:0]
+	for _, v := range values {
+		dst = append(dst, p.detector.Next(float64(v)))
+		p.detector.backfill(dst)
+	}
+	return dst
+}
+
+// Detector returns the underlying PeakDetector for access to the rest of its methods, such as Explain and Summary.
+func (p *TypedPeakDetector[T]) Detector() PeakDetector {
+	return p.detector
+}
+
+func toFloat64s[T Number](values []T) []float64 {
+	floats := make([]float64, len(values))
+	for i, v := range values {
+		floats[i] = float64(v)
+	}
+	return floats
+}
@@ -0,0 +1,112 @@
+package peakdetect_test
+
+import (
+	"testing"
+
+	"github.com/MicahParks/peakdetect"
+)
+
+func TestTypedPeakDetector(t *testing.T) {
+	inputs := make([]float32, len(exampleInputs))
+	for i, v := range exampleInputs {
+		inputs[i] = float32(v)
+	}
+
+	detector := peakdetect.NewTypedPeakDetector[float32]()
+	err := detector.Initialize(exampleInfluence, exampleThreshold, inputs[:exampleLag])
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+
+	signals := detector.NextBatch(inputs[exampleLag:])
+	for i, signal := range signals {
+		if signal != exampleOutputs[exampleLag+i] {
+			t.Fatalf("Incorrect signal at index %d.\n  Expected: %d\n  Actual: %d", exampleLag+i, exampleOutputs[exampleLag+i], signal)
+		}
+	}
+}
+
+func TestTypedPeakDetector_Integer(t *testing.T) {
+	detector := peakdetect.NewTypedPeakDetector[int16]()
+	err := detector.InitializeWithConfig(peakdetect.Config{Threshold: 3}, []int16{100, 101, 99, 100, 102, 98})
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+
+	if signal := detector.Next(101); signal != peakdetect.SignalNeutral {
+		t.Fatalf("Incorrect signal.\n  Expected: %d\n  Actual: %d", peakdetect.SignalNeutral, signal)
+	}
+	if signal := detector.Next(-200); signal != peakdetect.SignalNegative {
+		t.Fatalf("Incorrect signal.\n  Expected: %d\n  Actual: %d", peakdetect.SignalNegative, signal)
+	}
+	if explanation := detector.Detector().Explain(); explanation.Value != -200 {
+		t.Fatalf("Incorrect explained value.\n  Expected: %f\n  Actual: %f", -200.0, explanation.Value)
+	}
+}
+
+func TestTypedPeakDetector_NextBatchBackfill(t *testing.T) {
+	cfg := peakdetect.Config{Influence: exampleInfluence, Persistence: 2, PersistenceBackfill: true, Threshold: exampleThreshold}
+	reference := peakdetect.NewPeakDetector()
+	err := reference.InitializeWithConfig(cfg, exampleInputs[:exampleLag])
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+	expected := reference.NextBatch(exampleInputs[exampleLag:])
+
+	detector := peakdetect.NewTypedPeakDetector[float64]()
+	err = detector.InitializeWithConfig(cfg, exampleInputs[:exampleLag])
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+	signals := detector.NextBatch(exampleInputs[exampleLag:])
+	for i, signal := range signals {
+		if signal != expected[i] {
+			t.Fatalf("Backfilled signal did not match at index %d.\n  Expected: %d\n  Actual: %d", exampleLag+i, expected[i], signal)
+		}
+	}
+}
+
+func TestTypedPeakDetector_NextBatchIntoAllocs(t *testing.T) {
+	inputs := make([]int16, len(exampleInputs))
+	for i, v := range exampleInputs {
+		inputs[i] = int16(v * 10)
+	}
+	detector := peakdetect.NewTypedPeakDetector[int16]()
+	err := detector.Initialize(exampleInfluence, exampleThreshold, inputs[:exampleLag])
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+
+	dst := make([]peakdetect.Signal, 0, len(inputs))
+	allocs := testing.AllocsPerRun(10, func() {
+		dst = detector.NextBatchInto(dst, inputs[exampleLag:])
+	})
+	if allocs != 0 {
+		t.Fatalf("Processing a batch into a reused slice should not allocate.\n  Expected: %d\n  Actual: %.0f", 0, allocs)
+	}
+	allocs = testing.AllocsPerRun(10, func() {
+		detector.NextBatch(inputs[exampleLag:])
+	})
+	if allocs != 1 {
+		t.Fatalf("Processing a batch should only allocate the signals.\n  Expected: %d\n  Actual: %.0f", 1, allocs)
+	}
+}
+
+func BenchmarkTypedPeakDetector_NextBatchInto(b *testing.B) {
+	inputs := make([]float32, len(exampleInputs))
+	for i, v := range exampleInputs {
+		inputs[i] = float32(v)
+	}
+	detector := peakdetect.NewTypedPeakDetector[float32]()
+	err := detector.Initialize(exampleInfluence, exampleThreshold, inputs[:exampleLag])
+	if err != nil {
+		b.Fatalf(logFmt, "Error during initilization.", err)
+	}
+	dst := make([]peakdetect.Signal, 0, len(inputs))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dst = detector.NextBatchInto(dst, inputs[exampleLag:])
+	}
+}
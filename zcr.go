@@ -0,0 +1,84 @@
+package peakdetect
+
+import (
+	"fmt"
+)
+
+// ZeroCrossingRate is a streaming feature extractor for the rate at which values cross their moving mean. Its output
+// can be given to a second PeakDetector, so spikes in the zero-crossing rate, which indicate a change in the noise
+// regime, can be detected even when the values themselves do not deviate from the mean.
+type ZeroCrossingRate struct {
+	count     uint
+	crossings []bool
+	index     uint
+	prevSign  int8
+	sum       float64
+	values    []float64
+	window    uint
+}
+
+// NewZeroCrossingRate creates a new ZeroCrossingRate over the given window size. The window size must be greater than
+// zero.
+func NewZeroCrossingRate(window uint) (*ZeroCrossingRate, error) {
+	if window == 0 {
+		return nil, fmt.Errorf("the window size for a zero-crossing rate must be greater than zero: %w", ErrInvalidWindow)
+	}
+	return &ZeroCrossingRate{
+		crossings: make([]bool, 0, window),
+		values:    make([]float64, 0, window),
+		window:    window,
+	}, nil
+}
+
+// Next processes the next value and returns the fraction of the last window values that crossed the moving mean. A
+// value crosses the mean if it is on the opposite side of the mean of the previous window values than the last value
+// that was not equal to its mean.
+func (z *ZeroCrossingRate) Next(value float64) float64 {
+	mean := value
+	if len(z.values) != 0 {
+		mean = z.sum / float64(len(z.values))
+	}
+
+	var sign int8
+	switch {
+	case value > mean:
+		sign = 1
+	case value < mean:
+		sign = -1
+	}
+	crossed := sign != 0 && z.prevSign != 0 && sign != z.prevSign
+	if sign != 0 {
+		z.prevSign = sign
+	}
+
+	if uint(len(z.values)) < z.window {
+		z.crossings = append(z.crossings, crossed)
+		z.values = append(z.values, value)
+	} else {
+		if z.crossings[z.index] {
+			z.count--
+		}
+		z.sum -= z.values[z.index]
+		z.crossings[z.index] = crossed
+		z.values[z.index] = value
+	}
+	z.index++
+	if z.index == z.window {
+		z.index = 0
+	}
+	if crossed {
+		z.count++
+	}
+	z.sum += value
+
+	return float64(z.count) / float64(z.window)
+}
+
+// NextBatch processes the next values and returns their zero-crossing rates.
+func (z *ZeroCrossingRate) NextBatch(values []float64) []float64 {
+	rates := make([]float64, len(values))
+	for i, v := range values {
+		rates[i] = z.Next(v)
+	}
+	return rates
+}
@@ -0,0 +1,67 @@
+package peakdetect_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/MicahParks/peakdetect"
+)
+
+func TestNewZeroCrossingRate(t *testing.T) {
+	_, err := peakdetect.NewZeroCrossingRate(0)
+	if !errors.Is(err, peakdetect.ErrInvalidWindow) {
+		t.Fatalf("Invalid window did not produce error.\n  Expected: %s\n  Actual: %s", peakdetect.ErrInvalidWindow, err)
+	}
+}
+
+func TestZeroCrossingRate_Next(t *testing.T) {
+	zcr, err := peakdetect.NewZeroCrossingRate(4)
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to create zero-crossing rate.", err)
+	}
+
+	rates := zcr.NextBatch([]float64{0, 1, -1, 1, -1, 5, 6, 7, 8})
+	expected := []float64{0, 0, 0.25, 0.5, 0.75, 1, 0.75, 0.5, 0.25}
+	for i, rate := range rates {
+		if rate != expected[i] {
+			t.Fatalf("Rate did not match at index %d.\n  Expected: %f\n  Actual: %f", i, expected[i], rate)
+		}
+	}
+}
+
+func TestZeroCrossingRate_Chained(t *testing.T) {
+	// A slow oscillation turns into fast noise at index 200. The values stay within the same range, but the
+	// zero-crossing rate jumps.
+	var data []float64
+	for i := 0; i < 300; i++ {
+		v := 1.0
+		if i < 200 && (i/10)%2 == 1 || i >= 200 && i%2 == 1 {
+			v = -1
+		}
+		data = append(data, v)
+	}
+
+	zcr, err := peakdetect.NewZeroCrossingRate(20)
+	if err != nil {
+		t.Fatalf(logFmt, "Failed to create zero-crossing rate.", err)
+	}
+	rates := zcr.NextBatch(data)
+
+	detector := peakdetect.NewPeakDetector()
+	err = detector.Initialize(0, 5, rates[100:150])
+	if err != nil {
+		t.Fatalf(logFmt, "Error during initilization.", err)
+	}
+	signals := detector.NextBatch(rates[150:])
+
+	var first int
+	for i, signal := range signals {
+		if signal == peakdetect.SignalPositive {
+			first = i + 150
+			break
+		}
+	}
+	if first < 200 || first > 205 {
+		t.Fatalf("Change in noise regime was not detected promptly.\n  First signal index: %d", first)
+	}
+}